@@ -13,3 +13,9 @@ var ErrNilAntiFloodConfig = errors.New("nil antiflood config")
 
 // ErrNilApiConfig signals that a nil api config has been provided
 var ErrNilApiConfig = errors.New("nil api config")
+
+// ErrNilDatabaseHandler signals that a nil database handler has been provided
+var ErrNilDatabaseHandler = errors.New("nil database handler")
+
+// ErrNilTokenHandler signals that a nil token handler has been provided
+var ErrNilTokenHandler = errors.New("nil token handler")
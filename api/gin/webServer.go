@@ -32,6 +32,9 @@ type ArgsNewWebServer struct {
 	Facade          shared.FacadeHandler
 	ApiConfig       config.ApiRoutesConfig
 	AntiFloodConfig config.WebServerAntifloodConfig
+	DatabaseHandler core.DatabaseHandler
+	TokenHandler    authentication.TokenHandler
+	Registration    config.RegistrationConfig
 }
 
 type webServer struct {
@@ -39,6 +42,9 @@ type webServer struct {
 	facade          shared.FacadeHandler
 	apiConfig       config.ApiRoutesConfig
 	antiFloodConfig config.WebServerAntifloodConfig
+	databaseHandler core.DatabaseHandler
+	tokenHandler    authentication.TokenHandler
+	registration    config.RegistrationConfig
 	httpServer      elrondShared.HttpServerCloser
 	groups          map[string]shared.GroupHandler
 	cancelFunc      func()
@@ -55,6 +61,9 @@ func NewWebServerHandler(args ArgsNewWebServer) (*webServer, error) {
 		facade:          args.Facade,
 		antiFloodConfig: args.AntiFloodConfig,
 		apiConfig:       args.ApiConfig,
+		databaseHandler: args.DatabaseHandler,
+		tokenHandler:    args.TokenHandler,
+		registration:    args.Registration,
 	}
 
 	return gws, nil
@@ -72,6 +81,12 @@ func checkArgs(args ArgsNewWebServer) error {
 	if check.IfNilReflect(args.ApiConfig) {
 		return apiErrors.ErrNilApiConfig
 	}
+	if check.IfNil(args.DatabaseHandler) {
+		return apiErrors.ErrNilDatabaseHandler
+	}
+	if check.IfNil(args.TokenHandler) {
+		return apiErrors.ErrNilTokenHandler
+	}
 
 	return nil
 }
@@ -137,26 +152,30 @@ func (ws *webServer) StartHttpServer() error {
 func (ws *webServer) createGroups() error {
 	groupsMap := make(map[string]shared.GroupHandler)
 
-	dbHandler, err := core.NewDatabaseHandler("dragos:AVNS_UML_UBE0UxB_vSJngi-@tcp(db-mysql-fra1-74446-do-user-14078486-0.b.db.ondigitalocean.com:25060)/id_db?parseTime=true")
-
-	authGroup, err := groups.NewAuthGroup(ws.facade, dbHandler)
+	authGroup, err := groups.NewAuthGroup(ws.facade, ws.databaseHandler, ws.tokenHandler)
 	if err != nil {
 		return err
 	}
 	groupsMap["auth"] = authGroup
 
-	evaluationGroup, err := groups.NewEvaluationGroup(ws.facade, dbHandler)
+	evaluationGroup, err := groups.NewEvaluationGroup(ws.facade, ws.databaseHandler)
 	if err != nil {
 		return err
 	}
 	groupsMap["evaluation"] = evaluationGroup
 
-	adminGroup, err := groups.NewAdminGroup(ws.facade, dbHandler)
+	adminGroup, err := groups.NewAdminGroup(ws.facade, ws.databaseHandler, ws.tokenHandler, ws.registration)
 	if err != nil {
 		return err
 	}
 	groupsMap["admin"] = adminGroup
 
+	studentGroup, err := groups.NewStudentGroup(ws.facade, ws.databaseHandler)
+	if err != nil {
+		return err
+	}
+	groupsMap["student"] = studentGroup
+
 	ws.groups = groupsMap
 
 	return nil
@@ -190,7 +209,7 @@ func (ws *webServer) registerRoutes(ginRouter *gin.Engine) {
 		log.Debug("registering gin API group", "group name", groupName)
 		ginGroup := ginRouter.Group(fmt.Sprintf("/%s", groupName))
 		if groupHandler.IsAuthenticationNeeded() {
-			ginGroup.Use(authentication.Auth())
+			ginGroup.Use(authentication.Auth(ws.tokenHandler, ws.databaseHandler))
 		}
 		groupHandler.RegisterRoutes(ginGroup, ws.apiConfig)
 	}
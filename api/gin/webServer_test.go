@@ -10,6 +10,7 @@ import (
 	"github.com/dragos-rebegea/evaluare-tool/api/shared"
 	"github.com/dragos-rebegea/evaluare-tool/config"
 	"github.com/dragos-rebegea/evaluare-tool/core"
+	"github.com/dragos-rebegea/evaluare-tool/testsCommon/authentication"
 	"github.com/dragos-rebegea/evaluare-tool/testsCommon/facade"
 	"github.com/dragos-rebegea/evaluare-tool/testsCommon/groups"
 	"github.com/multiversx/mx-chain-core-go/core/check"
@@ -39,9 +40,20 @@ func createMockArgsNewWebServer() ArgsNewWebServer {
 			SameSourceRequests:           1,
 			SameSourceResetIntervalInSec: 1,
 		},
+		DatabaseHandler: createSQLiteDatabaseHandler(),
+		TokenHandler:    &authentication.TokenHandlerStub{},
 	}
 }
 
+func createSQLiteDatabaseHandler() core.DatabaseHandler {
+	dbHandler, _ := core.NewDatabaseHandler(core.ArgsDatabaseHandler{
+		Driver:           core.SQLiteDriver,
+		ConnectionString: ":memory:",
+		AutoMigrate:      true,
+	})
+	return dbHandler
+}
+
 func TestNewWebServerHandler(t *testing.T) {
 	t.Parallel()
 
@@ -55,6 +67,26 @@ func TestNewWebServerHandler(t *testing.T) {
 		assert.Equal(t, apiErrors.ErrNilFacade, err)
 		assert.True(t, check.IfNil(ws))
 	})
+	t.Run("nil database handler should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsNewWebServer()
+		args.DatabaseHandler = nil
+
+		ws, err := NewWebServerHandler(args)
+		assert.Equal(t, apiErrors.ErrNilDatabaseHandler, err)
+		assert.True(t, check.IfNil(ws))
+	})
+	t.Run("nil token handler should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsNewWebServer()
+		args.TokenHandler = nil
+
+		ws, err := NewWebServerHandler(args)
+		assert.Equal(t, apiErrors.ErrNilTokenHandler, err)
+		assert.True(t, check.IfNil(ws))
+	})
 	t.Run("should work", func(t *testing.T) {
 		t.Parallel()
 
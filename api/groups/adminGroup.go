@@ -3,12 +3,17 @@ package groups
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 	"sync"
+	"time"
 
 	"github.com/dragos-rebegea/evaluare-tool/api/shared"
 	"github.com/dragos-rebegea/evaluare-tool/authentication"
+	"github.com/dragos-rebegea/evaluare-tool/config"
 	"github.com/dragos-rebegea/evaluare-tool/core"
+	"github.com/dragos-rebegea/evaluare-tool/export"
 	"github.com/gin-gonic/gin"
 	"github.com/multiversx/mx-chain-core-go/core/check"
 	"github.com/multiversx/mx-chain-go/api/errors"
@@ -19,51 +24,206 @@ type adminGroup struct {
 	*baseGroup
 	facade               shared.FacadeHandler
 	mutFacade            sync.RWMutex
-	database             *core.DatabaseHandler
+	database             core.DatabaseHandler
+	tokenHandler         authentication.TokenHandler
+	registration         config.RegistrationConfig
 	authenticationNeeded bool
 }
 
 // NewAdminGroup returns a new instance of adminGroup
-func NewAdminGroup(facade shared.FacadeHandler, dbHandler *core.DatabaseHandler) (*adminGroup, error) {
+func NewAdminGroup(
+	facade shared.FacadeHandler,
+	dbHandler core.DatabaseHandler,
+	tokenHandler authentication.TokenHandler,
+	registration config.RegistrationConfig,
+) (*adminGroup, error) {
 	if check.IfNil(facade) {
 		return nil, fmt.Errorf("%w for admin group", errors.ErrNilFacadeHandler)
 	}
 	if check.IfNil(dbHandler) {
 		return nil, fmt.Errorf("%w for admin group", ErrNilDatabaseHandler)
 	}
+	if check.IfNil(tokenHandler) {
+		return nil, fmt.Errorf("%w for admin group", ErrNilTokenHandler)
+	}
 
 	ag := &adminGroup{
 		facade:               facade,
 		baseGroup:            &baseGroup{},
 		database:             dbHandler,
+		tokenHandler:         tokenHandler,
+		registration:         registration,
 		authenticationNeeded: true,
 	}
 
 	endpoints := []*elrondApiShared.EndpointHandlerData{
 		{
-			Path:    "/createClass",
-			Method:  http.MethodPost,
-			Handler: ag.createClass,
+			Path:                  "/createClass",
+			Method:                http.MethodPost,
+			Handler:               ag.createClass,
+			AdditionalMiddlewares: requirePermission(authentication.PermClassWrite),
+		},
+		{
+			Path:                  "/createProfesor",
+			Method:                http.MethodPost,
+			Handler:               ag.registerProfesor,
+			AdditionalMiddlewares: requirePermission(authentication.PermUserWrite),
+		},
+		{
+			Path:                  "/setAbsent",
+			Method:                http.MethodPost,
+			Handler:               ag.setAbsent,
+			AdditionalMiddlewares: requirePermission(authentication.PermClassWrite),
+		},
+		{
+			Path:                  "/delStudent",
+			Method:                http.MethodPost,
+			Handler:               ag.delStudent,
+			AdditionalMiddlewares: requirePermission(authentication.PermClassWrite),
+		},
+		{
+			Path:                  "/createExam",
+			Method:                http.MethodPost,
+			Handler:               ag.createExam,
+			AdditionalMiddlewares: requirePermission(authentication.PermExamWrite),
+		},
+		{
+			Path:                  "/updateRubrica",
+			Method:                http.MethodPost,
+			Handler:               ag.updateRubrica,
+			AdditionalMiddlewares: requirePermission(authentication.PermExamWrite),
+		},
+		{
+			Path:                  "/cloneExam",
+			Method:                http.MethodPost,
+			Handler:               ag.cloneExam,
+			AdditionalMiddlewares: requirePermission(authentication.PermExamWrite),
+		},
+		{
+			Path:                  "/moveExamStudents",
+			Method:                http.MethodPost,
+			Handler:               ag.moveExamStudents,
+			AdditionalMiddlewares: requirePermission(authentication.PermClassWrite),
+		},
+		{
+			Path:                  "/setExamStatus",
+			Method:                http.MethodPost,
+			Handler:               ag.setExamStatus,
+			AdditionalMiddlewares: requirePermission(authentication.PermExamWrite),
+		},
+		{
+			Path:                  "/getExamTranzitii/:exam",
+			Method:                http.MethodGet,
+			Handler:               ag.getExamTranzitii,
+			AdditionalMiddlewares: requirePermission(authentication.PermExamRead),
+		},
+		{
+			Path:                  "/assignEvaluator",
+			Method:                http.MethodPost,
+			Handler:               ag.assignEvaluator,
+			AdditionalMiddlewares: requirePermission(authentication.PermGradingManage),
+		},
+		{
+			Path:                  "/assignArbiter",
+			Method:                http.MethodPost,
+			Handler:               ag.assignArbiter,
+			AdditionalMiddlewares: requirePermission(authentication.PermGradingManage),
+		},
+		{
+			Path:                  "/getArbitraje",
+			Method:                http.MethodGet,
+			Handler:               ag.getArbitraje,
+			AdditionalMiddlewares: requirePermission(authentication.PermGradingManage),
+		},
+		{
+			Path:                  "/fileContestatie",
+			Method:                http.MethodPost,
+			Handler:               ag.fileContestatie,
+			AdditionalMiddlewares: requirePermission(authentication.PermGradingManage),
+		},
+		{
+			Path:                  "/assignContestatie",
+			Method:                http.MethodPost,
+			Handler:               ag.assignContestatie,
+			AdditionalMiddlewares: requirePermission(authentication.PermGradingManage),
+		},
+		{
+			Path:                  "/resolveContestatie",
+			Method:                http.MethodPost,
+			Handler:               ag.resolveContestatie,
+			AdditionalMiddlewares: requirePermission(authentication.PermGradingManage),
+		},
+		{
+			Path:                  "/getContestatii",
+			Method:                http.MethodGet,
+			Handler:               ag.getContestatii,
+			AdditionalMiddlewares: requirePermission(authentication.PermGradingManage),
+		},
+		{
+			Path:                  "/getProgress/:class/:exam",
+			Method:                http.MethodGet,
+			Handler:               ag.getProgress,
+			AdditionalMiddlewares: requirePermission(authentication.PermClassReadAll),
+		},
+		{
+			Path:                  "/getProfesorProgress/:profesor",
+			Method:                http.MethodGet,
+			Handler:               ag.getProfesorProgress,
+			AdditionalMiddlewares: requirePermission(authentication.PermClassReadAll),
+		},
+		{
+			Path:                  "/getStatistics/:exam",
+			Method:                http.MethodGet,
+			Handler:               ag.getStatistics,
+			AdditionalMiddlewares: requirePermission(authentication.PermClassReadAll),
+		},
+		{
+			Path:                  "/getItemAnalysis/:exam",
+			Method:                http.MethodGet,
+			Handler:               ag.getItemAnalysis,
+			AdditionalMiddlewares: requirePermission(authentication.PermExamRead),
+		},
+		{
+			Path:                  "/setCompetente",
+			Method:                http.MethodPost,
+			Handler:               ag.setCompetente,
+			AdditionalMiddlewares: requirePermission(authentication.PermExamWrite),
+		},
+		{
+			Path:                  "/getCompetente",
+			Method:                http.MethodGet,
+			Handler:               ag.getCompetente,
+			AdditionalMiddlewares: requirePermission(authentication.PermExamRead),
+		},
+		{
+			Path:                  "/revokeSessions",
+			Method:                http.MethodPost,
+			Handler:               ag.revokeSessions,
+			AdditionalMiddlewares: requirePermission(authentication.PermUserWrite),
 		},
 		{
-			Path:    "/createProfesor",
-			Method:  http.MethodPost,
-			Handler: ag.registerProfesor,
+			Path:                  "/assignRol",
+			Method:                http.MethodPost,
+			Handler:               ag.assignRol,
+			AdditionalMiddlewares: requirePermission(authentication.PermUserWrite),
 		},
 		{
-			Path:    "/setAbsent",
-			Method:  http.MethodPost,
-			Handler: ag.setAbsent,
+			Path:                  "/revokeRol",
+			Method:                http.MethodPost,
+			Handler:               ag.revokeRol,
+			AdditionalMiddlewares: requirePermission(authentication.PermUserWrite),
 		},
 		{
-			Path:    "/delStudent",
-			Method:  http.MethodPost,
-			Handler: ag.delStudent,
+			Path:                  "/getRoluri",
+			Method:                http.MethodGet,
+			Handler:               ag.getRoluri,
+			AdditionalMiddlewares: requirePermission(authentication.PermUserWrite),
 		},
 		{
-			Path:    "/createExam",
-			Method:  http.MethodPost,
-			Handler: ag.createExam,
+			Path:                  "/inviteUser",
+			Method:                http.MethodPost,
+			Handler:               ag.inviteUser,
+			AdditionalMiddlewares: requirePermission(authentication.PermUserWrite),
 		},
 	}
 	ag.endpoints = endpoints
@@ -71,41 +231,7 @@ func NewAdminGroup(facade shared.FacadeHandler, dbHandler *core.DatabaseHandler)
 	return ag, nil
 }
 
-func (ag *adminGroup) checkIfAdmin(c *gin.Context) bool {
-	email := c.GetString(authentication.EmailKey)
-
-	isAdmin, err := ag.database.IsAdmin(email)
-	if err != nil {
-		c.JSON(
-			http.StatusInternalServerError,
-			elrondApiShared.GenericAPIResponse{
-				Data:  nil,
-				Error: err.Error(),
-				Code:  elrondApiShared.ReturnCodeInternalError,
-			},
-		)
-		return false
-	}
-
-	if !isAdmin {
-		c.JSON(
-			http.StatusForbidden,
-			elrondApiShared.GenericAPIResponse{
-				Data:  nil,
-				Error: "you are not an admin",
-				Code:  elrondApiShared.ReturnCodeInternalError,
-			},
-		)
-		return false
-	}
-
-	return true
-}
-
 func (ag *adminGroup) registerProfesor(context *gin.Context) {
-	if !ag.checkIfAdmin(context) {
-		return
-	}
 	var prof authentication.Profesor
 	if err := context.ShouldBindJSON(&prof); err != nil {
 		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -113,7 +239,6 @@ func (ag *adminGroup) registerProfesor(context *gin.Context) {
 		return
 	}
 
-	prof.IsAdmin = false
 	err := ag.database.CreateProfesor(&prof)
 	if err != nil {
 		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -130,10 +255,6 @@ func (ag *adminGroup) registerProfesor(context *gin.Context) {
 }
 
 func (ag *adminGroup) createClass(c *gin.Context) {
-	if !ag.checkIfAdmin(c) {
-		return
-	}
-
 	var class core.Class
 	err := json.NewDecoder(c.Request.Body).Decode(&class)
 	if err != nil {
@@ -148,7 +269,7 @@ func (ag *adminGroup) createClass(c *gin.Context) {
 		return
 	}
 
-	students, err := ag.database.CreateClass(&class)
+	result, err := ag.database.CreateClass(&class)
 	if err != nil {
 		c.JSON(
 			http.StatusInternalServerError,
@@ -160,21 +281,30 @@ func (ag *adminGroup) createClass(c *gin.Context) {
 		)
 		return
 	}
-	for _, student := range students {
-		c.JSON(http.StatusCreated, gin.H{
-			"userId":   student.ID,
-			"email":    student.Email,
-			"username": student.Username,
-			"password": student.Password,
-		})
-	}
-}
 
-func (ag *adminGroup) setAbsent(c *gin.Context) {
-	if !ag.checkIfAdmin(c) {
+	if result.RolledBack {
+		c.JSON(
+			http.StatusUnprocessableEntity,
+			elrondApiShared.GenericAPIResponse{
+				Data:  result,
+				Error: "class import rolled back",
+				Code:  elrondApiShared.ReturnCodeRequestError,
+			},
+		)
 		return
 	}
 
+	c.JSON(
+		http.StatusCreated,
+		elrondApiShared.GenericAPIResponse{
+			Data:  result,
+			Error: "",
+			Code:  elrondApiShared.ReturnCodeSuccess,
+		},
+	)
+}
+
+func (ag *adminGroup) setAbsent(c *gin.Context) {
 	var mark core.AbsentStatus
 	err := json.NewDecoder(c.Request.Body).Decode(&mark)
 	if err != nil {
@@ -189,7 +319,8 @@ func (ag *adminGroup) setAbsent(c *gin.Context) {
 		return
 	}
 
-	err = ag.database.SetAbsent(&mark)
+	email := c.GetString(authentication.EmailKey)
+	err = ag.database.SetAbsent(email, &mark)
 	if err != nil {
 		c.JSON(
 			http.StatusInternalServerError,
@@ -204,10 +335,6 @@ func (ag *adminGroup) setAbsent(c *gin.Context) {
 }
 
 func (ag *adminGroup) delStudent(c *gin.Context) {
-	if !ag.checkIfAdmin(c) {
-		return
-	}
-
 	var student authentication.Student
 	err := json.NewDecoder(c.Request.Body).Decode(&student)
 	if err != nil {
@@ -238,10 +365,6 @@ func (ag *adminGroup) delStudent(c *gin.Context) {
 
 // createExam will create a new exam
 func (ag *adminGroup) createExam(c *gin.Context) {
-	if !ag.checkIfAdmin(c) {
-		return
-	}
-
 	var exam core.Exam
 	err := json.NewDecoder(c.Request.Body).Decode(&exam)
 	if err != nil {
@@ -270,25 +393,857 @@ func (ag *adminGroup) createExam(c *gin.Context) {
 	}
 }
 
-// UpdateFacade will update the facade
-func (ag *adminGroup) UpdateFacade(newFacade shared.FacadeHandler) error {
-	if check.IfNil(newFacade) {
-		return errors.ErrNilFacadeHandler
+// updateRubrica will change the marking scheme of a variant
+func (ag *adminGroup) updateRubrica(c *gin.Context) {
+	var update core.RubricaUpdate
+	err := json.NewDecoder(c.Request.Body).Decode(&update)
+	if err != nil {
+		c.JSON(
+			http.StatusBadRequest,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeInternalError,
+			},
+		)
+		return
 	}
 
-	ag.mutFacade.Lock()
-	ag.facade = newFacade
-	ag.mutFacade.Unlock()
+	err = ag.database.UpdateRubrica(&update)
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeInternalError,
+			},
+		)
+		return
+	}
 
-	return nil
+	c.JSON(
+		http.StatusOK,
+		elrondApiShared.GenericAPIResponse{
+			Data:  update,
+			Error: "",
+			Code:  elrondApiShared.ReturnCodeSuccess,
+		},
+	)
 }
 
-// IsAuthenticationNeeded will return true if the group requires authentication
-func (ag *adminGroup) IsAuthenticationNeeded() bool {
-	return ag.authenticationNeeded
+// cloneExam will copy an exam under a new name
+func (ag *adminGroup) cloneExam(c *gin.Context) {
+	var request core.CloneExamRequest
+	err := json.NewDecoder(c.Request.Body).Decode(&request)
+	if err != nil {
+		c.JSON(
+			http.StatusBadRequest,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeInternalError,
+			},
+		)
+		return
+	}
+
+	exam, err := ag.database.CloneExam(&request)
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeInternalError,
+			},
+		)
+		return
+	}
+
+	c.JSON(
+		http.StatusOK,
+		elrondApiShared.GenericAPIResponse{
+			Data:  exam,
+			Error: "",
+			Code:  elrondApiShared.ReturnCodeSuccess,
+		},
+	)
 }
 
-// IsInterfaceNil returns true if there is no value under the interface
-func (ag *adminGroup) IsInterfaceNil() bool {
-	return ag == nil
+// moveExamStudents will move the students assigned to an exam to another one
+func (ag *adminGroup) moveExamStudents(c *gin.Context) {
+	var request core.MoveStudentsRequest
+	err := json.NewDecoder(c.Request.Body).Decode(&request)
+	if err != nil {
+		c.JSON(
+			http.StatusBadRequest,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeInternalError,
+			},
+		)
+		return
+	}
+
+	result, err := ag.database.MoveExamStudents(&request)
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeInternalError,
+			},
+		)
+		return
+	}
+
+	c.JSON(
+		http.StatusOK,
+		elrondApiShared.GenericAPIResponse{
+			Data:  result,
+			Error: "",
+			Code:  elrondApiShared.ReturnCodeSuccess,
+		},
+	)
+}
+
+// setExamStatus will move an exam to another state of its lifecycle
+func (ag *adminGroup) setExamStatus(c *gin.Context) {
+	var request core.ExamStatusRequest
+	err := json.NewDecoder(c.Request.Body).Decode(&request)
+	if err != nil {
+		c.JSON(
+			http.StatusBadRequest,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeInternalError,
+			},
+		)
+		return
+	}
+
+	tranzitie, err := ag.database.SetExamStatus(c.GetString(authentication.EmailKey), &request)
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeInternalError,
+			},
+		)
+		return
+	}
+
+	c.JSON(
+		http.StatusOK,
+		elrondApiShared.GenericAPIResponse{
+			Data:  tranzitie,
+			Error: "",
+			Code:  elrondApiShared.ReturnCodeSuccess,
+		},
+	)
+}
+
+// getExamTranzitii will return the state changes of an exam
+func (ag *adminGroup) getExamTranzitii(c *gin.Context) {
+	tranzitii, err := ag.database.GetExamTranzitii(c.Param("exam"))
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeInternalError,
+			},
+		)
+		return
+	}
+
+	c.JSON(
+		http.StatusOK,
+		elrondApiShared.GenericAPIResponse{
+			Data:  gin.H{"tranzitii": tranzitii},
+			Error: "",
+			Code:  elrondApiShared.ReturnCodeSuccess,
+		},
+	)
+}
+
+// assignEvaluator will assign a profesor as first or second corrector of a class
+func (ag *adminGroup) assignEvaluator(c *gin.Context) {
+	var assignment core.EvaluatorAssignment
+	err := json.NewDecoder(c.Request.Body).Decode(&assignment)
+	if err != nil {
+		c.JSON(
+			http.StatusBadRequest,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeInternalError,
+			},
+		)
+		return
+	}
+
+	err = ag.database.AssignEvaluator(&assignment)
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeInternalError,
+			},
+		)
+		return
+	}
+
+	c.JSON(
+		http.StatusOK,
+		elrondApiShared.GenericAPIResponse{
+			Data:  assignment,
+			Error: "",
+			Code:  elrondApiShared.ReturnCodeSuccess,
+		},
+	)
+}
+
+// assignArbiter will assign a profesor to grade a paper awaiting arbitration
+func (ag *adminGroup) assignArbiter(c *gin.Context) {
+	var assignment core.ArbiterAssignment
+	err := json.NewDecoder(c.Request.Body).Decode(&assignment)
+	if err != nil {
+		c.JSON(
+			http.StatusBadRequest,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeInternalError,
+			},
+		)
+		return
+	}
+
+	err = ag.database.AssignArbiter(&assignment)
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeInternalError,
+			},
+		)
+		return
+	}
+
+	c.JSON(
+		http.StatusOK,
+		elrondApiShared.GenericAPIResponse{
+			Data:  assignment,
+			Error: "",
+			Code:  elrondApiShared.ReturnCodeSuccess,
+		},
+	)
+}
+
+// getArbitraje will return the papers awaiting arbitration
+func (ag *adminGroup) getArbitraje(c *gin.Context) {
+	arbitraje, err := ag.database.GetArbitraje()
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeInternalError,
+			},
+		)
+		return
+	}
+
+	c.JSON(
+		http.StatusOK,
+		elrondApiShared.GenericAPIResponse{
+			Data:  gin.H{"arbitraje": arbitraje},
+			Error: "",
+			Code:  elrondApiShared.ReturnCodeSuccess,
+		},
+	)
+}
+
+// fileContestatie will record an appeal against the grade of a student
+func (ag *adminGroup) fileContestatie(c *gin.Context) {
+	var request core.ContestatieRequest
+	err := json.NewDecoder(c.Request.Body).Decode(&request)
+	if err != nil {
+		c.JSON(
+			http.StatusBadRequest,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeInternalError,
+			},
+		)
+		return
+	}
+
+	contestatie, err := ag.database.FileContestatie(c.GetString(authentication.EmailKey), &request)
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeInternalError,
+			},
+		)
+		return
+	}
+
+	c.JSON(
+		http.StatusOK,
+		elrondApiShared.GenericAPIResponse{
+			Data:  contestatie,
+			Error: "",
+			Code:  elrondApiShared.ReturnCodeSuccess,
+		},
+	)
+}
+
+// assignContestatie will assign a profesor to re-evaluate a contested paper
+func (ag *adminGroup) assignContestatie(c *gin.Context) {
+	var request core.ContestatieAssignment
+	err := json.NewDecoder(c.Request.Body).Decode(&request)
+	if err != nil {
+		c.JSON(
+			http.StatusBadRequest,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeInternalError,
+			},
+		)
+		return
+	}
+
+	err = ag.database.AssignContestatie(&request)
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeInternalError,
+			},
+		)
+		return
+	}
+
+	c.JSON(
+		http.StatusOK,
+		elrondApiShared.GenericAPIResponse{
+			Data:  request,
+			Error: "",
+			Code:  elrondApiShared.ReturnCodeSuccess,
+		},
+	)
+}
+
+// resolveContestatie will decide the final grade of a re-evaluated paper
+func (ag *adminGroup) resolveContestatie(c *gin.Context) {
+	var request core.ContestatieRequest
+	err := json.NewDecoder(c.Request.Body).Decode(&request)
+	if err != nil {
+		c.JSON(
+			http.StatusBadRequest,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeInternalError,
+			},
+		)
+		return
+	}
+
+	contestatie, err := ag.database.ResolveContestatie(&request)
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeInternalError,
+			},
+		)
+		return
+	}
+
+	c.JSON(
+		http.StatusOK,
+		elrondApiShared.GenericAPIResponse{
+			Data:  contestatie,
+			Error: "",
+			Code:  elrondApiShared.ReturnCodeSuccess,
+		},
+	)
+}
+
+// getContestatii will return the appeals, optionally filtered by status
+func (ag *adminGroup) getContestatii(c *gin.Context) {
+	contestatii, err := ag.database.GetContestatii(c.Query("status"))
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeInternalError,
+			},
+		)
+		return
+	}
+
+	c.JSON(
+		http.StatusOK,
+		elrondApiShared.GenericAPIResponse{
+			Data:  gin.H{"contestatii": contestatii},
+			Error: "",
+			Code:  elrondApiShared.ReturnCodeSuccess,
+		},
+	)
+}
+
+// getProgress will return the grading progress of a class on an exam
+func (ag *adminGroup) getProgress(c *gin.Context) {
+	progress, err := ag.database.GetClassProgress(c.Param("class"), c.Param("exam"))
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeInternalError,
+			},
+		)
+		return
+	}
+
+	c.JSON(
+		http.StatusOK,
+		elrondApiShared.GenericAPIResponse{
+			Data:  progress,
+			Error: "",
+			Code:  elrondApiShared.ReturnCodeSuccess,
+		},
+	)
+}
+
+// getProfesorProgress will return the grading progress of a profesor
+func (ag *adminGroup) getProfesorProgress(c *gin.Context) {
+	progress, err := ag.database.GetProfesorProgress(c.Param("profesor"))
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeInternalError,
+			},
+		)
+		return
+	}
+
+	c.JSON(
+		http.StatusOK,
+		elrondApiShared.GenericAPIResponse{
+			Data:  gin.H{"progres": progress},
+			Error: "",
+			Code:  elrondApiShared.ReturnCodeSuccess,
+		},
+	)
+}
+
+// getStatistics will return the statistics of the whole school on an exam
+func (ag *adminGroup) getStatistics(c *gin.Context) {
+	result, err := ag.database.GetExamStatistics(c.Param("exam"))
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeInternalError,
+			},
+		)
+		return
+	}
+
+	c.JSON(
+		http.StatusOK,
+		elrondApiShared.GenericAPIResponse{
+			Data:  result,
+			Error: "",
+			Code:  elrondApiShared.ReturnCodeSuccess,
+		},
+	)
+}
+
+// getItemAnalysis will return the item analysis of an exam, as JSON or as CSV if format=csv is requested
+func (ag *adminGroup) getItemAnalysis(c *gin.Context) {
+	exam := c.Param("exam")
+	analysis, err := ag.database.GetItemAnalysis(exam)
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeInternalError,
+			},
+		)
+		return
+	}
+
+	if c.Query("format") == csvFormat {
+		writeCSV(c, exam+"_analiza_itemi.csv", func(w io.Writer) error {
+			return export.WriteItemAnalysisCSV(w, analysis)
+		})
+		return
+	}
+
+	c.JSON(
+		http.StatusOK,
+		elrondApiShared.GenericAPIResponse{
+			Data:  analysis,
+			Error: "",
+			Code:  elrondApiShared.ReturnCodeSuccess,
+		},
+	)
+}
+
+// setCompetente will add competencies of the curriculum or update their descriptions
+func (ag *adminGroup) setCompetente(c *gin.Context) {
+	var competente []*core.Competenta
+	err := json.NewDecoder(c.Request.Body).Decode(&competente)
+	if err != nil {
+		c.JSON(
+			http.StatusBadRequest,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeInternalError,
+			},
+		)
+		return
+	}
+
+	err = ag.database.SetCompetente(competente)
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeInternalError,
+			},
+		)
+		return
+	}
+
+	c.JSON(
+		http.StatusOK,
+		elrondApiShared.GenericAPIResponse{
+			Data:  competente,
+			Error: "",
+			Code:  elrondApiShared.ReturnCodeSuccess,
+		},
+	)
+}
+
+// getCompetente will return the competencies of the curriculum, optionally filtered by subject
+func (ag *adminGroup) getCompetente(c *gin.Context) {
+	competente, err := ag.database.GetCompetente(c.Query("materie"))
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeInternalError,
+			},
+		)
+		return
+	}
+
+	c.JSON(
+		http.StatusOK,
+		elrondApiShared.GenericAPIResponse{
+			Data:  gin.H{"competente": competente},
+			Error: "",
+			Code:  elrondApiShared.ReturnCodeSuccess,
+		},
+	)
+}
+
+// revokeSessions will close every open session of a profesor, logging them out of all devices
+func (ag *adminGroup) revokeSessions(c *gin.Context) {
+	var request core.RevokeSessionsRequest
+	err := json.NewDecoder(c.Request.Body).Decode(&request)
+	if err != nil {
+		c.JSON(
+			http.StatusBadRequest,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeInternalError,
+			},
+		)
+		return
+	}
+
+	revocate, err := ag.database.RevokeSesiuniProfesor(request.Email)
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeInternalError,
+			},
+		)
+		return
+	}
+
+	c.JSON(
+		http.StatusOK,
+		elrondApiShared.GenericAPIResponse{
+			Data:  gin.H{"sesiuni": revocate},
+			Error: "",
+			Code:  elrondApiShared.ReturnCodeSuccess,
+		},
+	)
+}
+
+// assignRol will grant a role to a profesor. The diriginte role also names the class
+func (ag *adminGroup) assignRol(c *gin.Context) {
+	var request core.RolRequest
+	err := json.NewDecoder(c.Request.Body).Decode(&request)
+	if err != nil {
+		c.JSON(
+			http.StatusBadRequest,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeInternalError,
+			},
+		)
+		return
+	}
+
+	err = ag.database.AssignRol(&request)
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeInternalError,
+			},
+		)
+		return
+	}
+
+	c.JSON(
+		http.StatusOK,
+		elrondApiShared.GenericAPIResponse{
+			Data:  gin.H{"rol": request},
+			Error: "",
+			Code:  elrondApiShared.ReturnCodeSuccess,
+		},
+	)
+}
+
+// revokeRol will take back a role of a profesor
+func (ag *adminGroup) revokeRol(c *gin.Context) {
+	var request core.RolRequest
+	err := json.NewDecoder(c.Request.Body).Decode(&request)
+	if err != nil {
+		c.JSON(
+			http.StatusBadRequest,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeInternalError,
+			},
+		)
+		return
+	}
+
+	err = ag.database.RevokeRol(&request)
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeInternalError,
+			},
+		)
+		return
+	}
+
+	c.JSON(
+		http.StatusOK,
+		elrondApiShared.GenericAPIResponse{
+			Data:  gin.H{"rol": request},
+			Error: "",
+			Code:  elrondApiShared.ReturnCodeSuccess,
+		},
+	)
+}
+
+// getRoluri will return the roles of a profesor, or of every user if no email is provided
+func (ag *adminGroup) getRoluri(c *gin.Context) {
+	roluri, err := ag.database.GetRoluri(c.Query("email"))
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeInternalError,
+			},
+		)
+		return
+	}
+
+	c.JSON(
+		http.StatusOK,
+		elrondApiShared.GenericAPIResponse{
+			Data:  gin.H{"roluri": roluri},
+			Error: "",
+			Code:  elrondApiShared.ReturnCodeSuccess,
+		},
+	)
+}
+
+// UpdateFacade will update the facade
+func (ag *adminGroup) UpdateFacade(newFacade shared.FacadeHandler) error {
+	if check.IfNil(newFacade) {
+		return errors.ErrNilFacadeHandler
+	}
+
+	ag.mutFacade.Lock()
+	ag.facade = newFacade
+	ag.mutFacade.Unlock()
+
+	return nil
+}
+
+// IsAuthenticationNeeded will return true if the group requires authentication
+func (ag *adminGroup) IsAuthenticationNeeded() bool {
+	return ag.authenticationNeeded
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (ag *adminGroup) IsInterfaceNil() bool {
+	return ag == nil
+}
+
+// inviteUser will issue a signed invitation, valid once until it expires, allowing the invited email to register
+// with the role. The role is required and must be one a profesor account can hold
+func (ag *adminGroup) inviteUser(c *gin.Context) {
+	var request core.InvitatieRequest
+	err := json.NewDecoder(c.Request.Body).Decode(&request)
+	if err == nil && len(request.Email) == 0 {
+		err = ErrMissingEmail
+	}
+	if err != nil {
+		c.JSON(
+			http.StatusBadRequest,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeRequestError,
+			},
+		)
+		return
+	}
+	if !authentication.IsProfesorRol(request.Rol) || request.Rol == authentication.RolDiriginte {
+		c.JSON(
+			http.StatusBadRequest,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: fmt.Sprintf("%s: %s", core.ErrInvalidRol.Error(), request.Rol),
+				Code:  elrondApiShared.ReturnCodeRequestError,
+			},
+		)
+		return
+	}
+
+	id, err := authentication.NewID()
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeInternalError,
+			},
+		)
+		return
+	}
+	invitatie := &authentication.Invitatie{
+		ID:        id,
+		Email:     request.Email,
+		Rol:       request.Rol,
+		Admin:     c.GetString(authentication.EmailKey),
+		ExpiresAt: time.Now().Add(time.Duration(ag.registration.InvitationLifetimeInSec) * time.Second),
+	}
+	token, err := ag.tokenHandler.GenerateInvitation(invitatie.ID, invitatie.Email, invitatie.Rol, invitatie.ExpiresAt)
+	if err == nil {
+		err = ag.database.CreateInvitatie(invitatie)
+	}
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeInternalError,
+			},
+		)
+		return
+	}
+
+	c.JSON(
+		http.StatusOK,
+		elrondApiShared.GenericAPIResponse{
+			Data: gin.H{
+				"token":      token,
+				"link":       ag.invitationLink(token),
+				"expires_at": invitatie.ExpiresAt,
+			},
+			Error: "",
+			Code:  elrondApiShared.ReturnCodeSuccess,
+		},
+	)
+}
+
+// invitationLink adds the invitation to the configured URL of the registration page
+func (ag *adminGroup) invitationLink(token string) string {
+	link, err := url.Parse(ag.registration.InvitationURL)
+	if err != nil || len(ag.registration.InvitationURL) == 0 {
+		return ""
+	}
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+	return link.String()
 }
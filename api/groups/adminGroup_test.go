@@ -0,0 +1,469 @@
+package groups
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dragos-rebegea/evaluare-tool/authentication"
+	"github.com/dragos-rebegea/evaluare-tool/config"
+	"github.com/dragos-rebegea/evaluare-tool/core"
+	authMocks "github.com/dragos-rebegea/evaluare-tool/testsCommon/authentication"
+	"github.com/dragos-rebegea/evaluare-tool/testsCommon/database"
+	"github.com/dragos-rebegea/evaluare-tool/testsCommon/facade"
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type createClassResponse struct {
+	Data  core.CreateClassResult `json:"data"`
+	Error string                 `json:"error"`
+}
+
+type arbitrajeResponse struct {
+	Data struct {
+		Arbitraje []authentication.Arbitraj `json:"arbitraje"`
+	} `json:"data"`
+	Error string `json:"error"`
+}
+
+func getAdminRoutesConfig() config.ApiRoutesConfig {
+	return config.ApiRoutesConfig{
+		APIPackages: map[string]config.APIPackageConfig{
+			"admin": {
+				Routes: []config.RouteConfig{
+					{Name: "/createClass", Open: true},
+					{Name: "/setExamStatus", Open: true},
+					{Name: "/getArbitraje", Open: true},
+					{Name: "/getContestatii", Open: true},
+					{Name: "/getItemAnalysis/:exam", Open: true},
+					{Name: "/getCompetente", Open: true},
+					{Name: "/revokeSessions", Open: true},
+					{Name: "/assignRol", Open: true},
+					{Name: "/inviteUser", Open: true},
+				},
+			},
+		},
+	}
+}
+
+func createAdminDatabaseHandlerStub() *database.DatabaseHandlerStub {
+	return &database.DatabaseHandlerStub{}
+}
+
+func TestNewAdminGroup(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil facade should error", func(t *testing.T) {
+		t.Parallel()
+
+		ag, err := NewAdminGroup(nil, createAdminDatabaseHandlerStub(), &authMocks.TokenHandlerStub{}, config.RegistrationConfig{})
+		assert.NotNil(t, err)
+		assert.True(t, check.IfNil(ag))
+	})
+	t.Run("nil database handler should error", func(t *testing.T) {
+		t.Parallel()
+
+		ag, err := NewAdminGroup(&facade.FacadeStub{}, nil, &authMocks.TokenHandlerStub{}, config.RegistrationConfig{})
+		assert.True(t, errors.Is(err, ErrNilDatabaseHandler))
+		assert.True(t, check.IfNil(ag))
+	})
+	t.Run("nil token handler should error", func(t *testing.T) {
+		t.Parallel()
+
+		ag, err := NewAdminGroup(&facade.FacadeStub{}, createAdminDatabaseHandlerStub(), nil, config.RegistrationConfig{})
+		assert.True(t, errors.Is(err, ErrNilTokenHandler))
+		assert.True(t, check.IfNil(ag))
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		ag, err := NewAdminGroup(&facade.FacadeStub{}, createAdminDatabaseHandlerStub(), &authMocks.TokenHandlerStub{}, config.RegistrationConfig{})
+		assert.Nil(t, err)
+		assert.False(t, check.IfNil(ag))
+	})
+}
+
+func TestAdminGroup_createClass(t *testing.T) {
+	t.Parallel()
+
+	t.Run("not an admin should error", func(t *testing.T) {
+		t.Parallel()
+
+		dbHandler := createAdminDatabaseHandlerStub()
+		dbHandler.CreateClassCalled = func(class *core.Class) (*core.CreateClassResult, error) {
+			require.Fail(t, "should not have been called")
+			return nil, nil
+		}
+		ag, _ := NewAdminGroup(&facade.FacadeStub{}, dbHandler, &authMocks.TokenHandlerStub{}, config.RegistrationConfig{})
+		ws := startWebServerWithRoles(ag, "admin", getAdminRoutesConfig(), authentication.RolProfesor, authentication.RolDirector)
+
+		req, _ := http.NewRequest("POST", "/admin/createClass", requestToReader(core.Class{Nume: "8A"}))
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusForbidden, resp.Code)
+	})
+	t.Run("partial import should report created and rejected students in one response", func(t *testing.T) {
+		t.Parallel()
+
+		dbHandler := createAdminDatabaseHandlerStub()
+		dbHandler.CreateClassCalled = func(class *core.Class) (*core.CreateClassResult, error) {
+			return &core.CreateClassResult{
+				Clasa:    class.Nume,
+				Created:  []*core.CreatedStudent{{ID: 1, Username: "a_b"}, {ID: 2, Username: "c_d"}},
+				Rejected: []*core.RejectedStudent{{Nume: "e", Reason: "duplicate"}},
+			}, nil
+		}
+		ag, _ := NewAdminGroup(&facade.FacadeStub{}, dbHandler, &authMocks.TokenHandlerStub{}, config.RegistrationConfig{})
+		ws := startWebServer(ag, "admin", getAdminRoutesConfig())
+
+		req, _ := http.NewRequest("POST", "/admin/createClass", requestToReader(core.Class{Nume: "8A"}))
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusCreated, resp.Code)
+		assert.Equal(t, 1, strings.Count(resp.Body.String(), `"data"`))
+
+		response := createClassResponse{}
+		loadResponse(resp.Body, &response)
+		require.Equal(t, 2, len(response.Data.Created))
+		require.Equal(t, 1, len(response.Data.Rejected))
+		assert.Equal(t, "duplicate", response.Data.Rejected[0].Reason)
+	})
+	t.Run("rolled back import should error", func(t *testing.T) {
+		t.Parallel()
+
+		dbHandler := createAdminDatabaseHandlerStub()
+		dbHandler.CreateClassCalled = func(class *core.Class) (*core.CreateClassResult, error) {
+			return &core.CreateClassResult{
+				Clasa:      class.Nume,
+				Created:    make([]*core.CreatedStudent, 0),
+				Rejected:   []*core.RejectedStudent{{Nume: "e", Reason: "duplicate"}},
+				RolledBack: true,
+			}, nil
+		}
+		ag, _ := NewAdminGroup(&facade.FacadeStub{}, dbHandler, &authMocks.TokenHandlerStub{}, config.RegistrationConfig{})
+		ws := startWebServer(ag, "admin", getAdminRoutesConfig())
+
+		req, _ := http.NewRequest("POST", "/admin/createClass", requestToReader(core.Class{Nume: "8A", AllOrNothing: true}))
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+
+		response := createClassResponse{}
+		loadResponse(resp.Body, &response)
+		assert.True(t, response.Data.RolledBack)
+		assert.NotEmpty(t, response.Error)
+	})
+}
+
+func TestAdminGroup_setExamStatus(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid transition should error", func(t *testing.T) {
+		t.Parallel()
+
+		dbHandler := createAdminDatabaseHandlerStub()
+		dbHandler.SetExamStatusCalled = func(email string, request *core.ExamStatusRequest) (*authentication.TranzitieExam, error) {
+			return nil, core.ErrInvalidExamTransition
+		}
+		ag, _ := NewAdminGroup(&facade.FacadeStub{}, dbHandler, &authMocks.TokenHandlerStub{}, config.RegistrationConfig{})
+		ws := startWebServer(ag, "admin", getAdminRoutesConfig())
+
+		request := core.ExamStatusRequest{Exam: "sim1", Status: core.ExamRezultatePublicate}
+		req, _ := http.NewRequest("POST", "/admin/setExamStatus", requestToReader(request))
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusInternalServerError, resp.Code)
+		assert.True(t, strings.Contains(resp.Body.String(), core.ErrInvalidExamTransition.Error()))
+	})
+	t.Run("should return the audit record", func(t *testing.T) {
+		t.Parallel()
+
+		dbHandler := createAdminDatabaseHandlerStub()
+		dbHandler.SetExamStatusCalled = func(email string, request *core.ExamStatusRequest) (*authentication.TranzitieExam, error) {
+			return &authentication.TranzitieExam{Exam: request.Exam, DinStatus: core.ExamNotareDeschisa, InStatus: request.Status}, nil
+		}
+		ag, _ := NewAdminGroup(&facade.FacadeStub{}, dbHandler, &authMocks.TokenHandlerStub{}, config.RegistrationConfig{})
+		ws := startWebServer(ag, "admin", getAdminRoutesConfig())
+
+		request := core.ExamStatusRequest{Exam: "sim1", Status: core.ExamNotareInchisa}
+		req, _ := http.NewRequest("POST", "/admin/setExamStatus", requestToReader(request))
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.True(t, strings.Contains(resp.Body.String(), `"in_status":"notare_inchisa"`))
+	})
+}
+
+func TestAdminGroup_getArbitraje(t *testing.T) {
+	t.Parallel()
+
+	t.Run("database error should error", func(t *testing.T) {
+		t.Parallel()
+
+		dbHandler := createAdminDatabaseHandlerStub()
+		dbHandler.GetArbitrajeCalled = func() ([]authentication.Arbitraj, error) {
+			return nil, errors.New("expected error")
+		}
+		ag, _ := NewAdminGroup(&facade.FacadeStub{}, dbHandler, &authMocks.TokenHandlerStub{}, config.RegistrationConfig{})
+		ws := startWebServer(ag, "admin", getAdminRoutesConfig())
+
+		req, _ := http.NewRequest("GET", "/admin/getArbitraje", nil)
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusInternalServerError, resp.Code)
+	})
+	t.Run("should return the papers awaiting arbitration", func(t *testing.T) {
+		t.Parallel()
+
+		dbHandler := createAdminDatabaseHandlerStub()
+		dbHandler.GetArbitrajeCalled = func() ([]authentication.Arbitraj, error) {
+			return []authentication.Arbitraj{
+				{Student: 1, Exam: "sim1", Materie: "matematica", Nota1: 5, Nota2: 8, Status: core.ArbitrajInAsteptare},
+			}, nil
+		}
+		ag, _ := NewAdminGroup(&facade.FacadeStub{}, dbHandler, &authMocks.TokenHandlerStub{}, config.RegistrationConfig{})
+		ws := startWebServer(ag, "admin", getAdminRoutesConfig())
+
+		req, _ := http.NewRequest("GET", "/admin/getArbitraje", nil)
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		response := arbitrajeResponse{}
+		loadResponse(resp.Body, &response)
+		require.Equal(t, 1, len(response.Data.Arbitraje))
+		assert.Equal(t, core.ArbitrajInAsteptare, response.Data.Arbitraje[0].Status)
+	})
+}
+
+func TestAdminGroup_getContestatii(t *testing.T) {
+	t.Parallel()
+
+	dbHandler := createAdminDatabaseHandlerStub()
+	status := ""
+	dbHandler.GetContestatiiCalled = func(s string) ([]authentication.Contestatie, error) {
+		status = s
+		return []authentication.Contestatie{{Student: 1, Exam: "sim1", Status: s}}, nil
+	}
+	ag, _ := NewAdminGroup(&facade.FacadeStub{}, dbHandler, &authMocks.TokenHandlerStub{}, config.RegistrationConfig{})
+	ws := startWebServer(ag, "admin", getAdminRoutesConfig())
+
+	req, _ := http.NewRequest("GET", "/admin/getContestatii?status="+core.ContestatieDepusa, nil)
+	resp := httptest.NewRecorder()
+	ws.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, core.ContestatieDepusa, status)
+	assert.True(t, strings.Contains(resp.Body.String(), `"contestatii"`))
+}
+
+func TestAdminGroup_getItemAnalysis(t *testing.T) {
+	t.Parallel()
+
+	dbHandler := createAdminDatabaseHandlerStub()
+	dbHandler.GetItemAnalysisCalled = func(exam string) (*core.ItemAnalysis, error) {
+		return &core.ItemAnalysis{
+			Exam:      exam,
+			Exercitii: []*core.ItemStatistics{{Numar: "1", Materie: "matematica", Facilitate: 0.5}},
+		}, nil
+	}
+	ag, _ := NewAdminGroup(&facade.FacadeStub{}, dbHandler, &authMocks.TokenHandlerStub{}, config.RegistrationConfig{})
+	ws := startWebServer(ag, "admin", getAdminRoutesConfig())
+
+	t.Run("json", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/admin/getItemAnalysis/sim1", nil)
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.True(t, strings.Contains(resp.Body.String(), `"facilitate":0.5`))
+	})
+	t.Run("csv", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/admin/getItemAnalysis/sim1?format=csv", nil)
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", resp.Header().Get("Content-Type"))
+		assert.True(t, strings.Contains(resp.Header().Get("Content-Disposition"), "sim1_analiza_itemi.csv"))
+		assert.True(t, strings.HasPrefix(resp.Body.String(), "exercitiu,materie"))
+	})
+}
+
+func TestAdminGroup_getCompetente(t *testing.T) {
+	t.Parallel()
+
+	dbHandler := createAdminDatabaseHandlerStub()
+	materie := ""
+	dbHandler.GetCompetenteCalled = func(m string) ([]*core.Competenta, error) {
+		materie = m
+		return []*core.Competenta{{Materie: m, Cod: "2.1", Descriere: "Calcule cu numere reale"}}, nil
+	}
+	ag, _ := NewAdminGroup(&facade.FacadeStub{}, dbHandler, &authMocks.TokenHandlerStub{}, config.RegistrationConfig{})
+	ws := startWebServer(ag, "admin", getAdminRoutesConfig())
+
+	req, _ := http.NewRequest("GET", "/admin/getCompetente?materie=matematica", nil)
+	resp := httptest.NewRecorder()
+	ws.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "matematica", materie)
+	assert.True(t, strings.Contains(resp.Body.String(), `"cod":"2.1"`))
+}
+
+func TestAdminGroup_revokeSessions(t *testing.T) {
+	t.Parallel()
+
+	dbHandler := createAdminDatabaseHandlerStub()
+	email := ""
+	dbHandler.RevokeSesiuniProfesorCalled = func(e string) (int64, error) {
+		email = e
+		return 2, nil
+	}
+	ag, _ := NewAdminGroup(&facade.FacadeStub{}, dbHandler, &authMocks.TokenHandlerStub{}, config.RegistrationConfig{})
+	ws := startWebServer(ag, "admin", getAdminRoutesConfig())
+
+	req, _ := http.NewRequest("POST", "/admin/revokeSessions", requestToReader(core.RevokeSessionsRequest{Email: "mate@test.ro"}))
+	resp := httptest.NewRecorder()
+	ws.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "mate@test.ro", email)
+	assert.True(t, strings.Contains(resp.Body.String(), `"sesiuni":2`))
+}
+
+func TestAdminGroup_inviteUser(t *testing.T) {
+	t.Parallel()
+
+	registration := config.RegistrationConfig{
+		InvitationLifetimeInSec: 3600,
+		InvitationURL:           "http://localhost:3000/invitatie",
+	}
+
+	t.Run("diriginte invitation should error", func(t *testing.T) {
+		t.Parallel()
+
+		ag, _ := NewAdminGroup(&facade.FacadeStub{}, createAdminDatabaseHandlerStub(), &authMocks.TokenHandlerStub{}, registration)
+		ws := startWebServer(ag, "admin", getAdminRoutesConfig())
+
+		request := core.InvitatieRequest{Email: "mate@test.ro", Rol: authentication.RolDiriginte}
+		req, _ := http.NewRequest("POST", "/admin/inviteUser", requestToReader(request))
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+	t.Run("missing or unknown role should error", func(t *testing.T) {
+		t.Parallel()
+
+		dbHandler := createAdminDatabaseHandlerStub()
+		dbHandler.CreateInvitatieCalled = func(invitatie *authentication.Invitatie) error {
+			assert.Fail(t, "should not store the invitation")
+			return nil
+		}
+		ag, _ := NewAdminGroup(&facade.FacadeStub{}, dbHandler, &authMocks.TokenHandlerStub{}, registration)
+		ws := startWebServer(ag, "admin", getAdminRoutesConfig())
+
+		for _, rol := range []string{"", "superadmin"} {
+			request := core.InvitatieRequest{Email: "mate@test.ro", Rol: rol}
+			req, _ := http.NewRequest("POST", "/admin/inviteUser", requestToReader(request))
+			resp := httptest.NewRecorder()
+			ws.ServeHTTP(resp, req)
+
+			assert.Equal(t, http.StatusBadRequest, resp.Code)
+			assert.True(t, strings.Contains(resp.Body.String(), core.ErrInvalidRol.Error()))
+		}
+	})
+	t.Run("missing email should error", func(t *testing.T) {
+		t.Parallel()
+
+		ag, _ := NewAdminGroup(&facade.FacadeStub{}, createAdminDatabaseHandlerStub(), &authMocks.TokenHandlerStub{}, registration)
+		ws := startWebServer(ag, "admin", getAdminRoutesConfig())
+
+		req, _ := http.NewRequest("POST", "/admin/inviteUser", requestToReader(core.InvitatieRequest{}))
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		assert.True(t, strings.Contains(resp.Body.String(), ErrMissingEmail.Error()))
+	})
+	t.Run("should store and sign an admin invitation", func(t *testing.T) {
+		t.Parallel()
+
+		dbHandler := createAdminDatabaseHandlerStub()
+		var stored *authentication.Invitatie
+		dbHandler.CreateInvitatieCalled = func(invitatie *authentication.Invitatie) error {
+			stored = invitatie
+			return nil
+		}
+		tokenHandler := &authMocks.TokenHandlerStub{
+			GenerateInvitationCalled: func(id string, email string, rol string, expiresAt time.Time) (string, error) {
+				return "semnata." + id, nil
+			},
+		}
+		ag, _ := NewAdminGroup(&facade.FacadeStub{}, dbHandler, tokenHandler, registration)
+		ws := startWebServer(ag, "admin", getAdminRoutesConfig())
+
+		req, _ := http.NewRequest("POST", "/admin/inviteUser", requestToReader(core.InvitatieRequest{Email: "mate@test.ro", Rol: authentication.RolAdmin}))
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		require.NotNil(t, stored)
+		assert.Equal(t, "mate@test.ro", stored.Email)
+		assert.Equal(t, authentication.RolAdmin, stored.Rol)
+		assert.True(t, stored.ExpiresAt.After(time.Now().Add(59*time.Minute)))
+		assert.True(t, strings.Contains(resp.Body.String(), "http://localhost:3000/invitatie?token=semnata."+stored.ID))
+	})
+}
+
+func TestAdminGroup_assignRol(t *testing.T) {
+	t.Parallel()
+
+	t.Run("without the user:write permission should error", func(t *testing.T) {
+		t.Parallel()
+
+		ag, _ := NewAdminGroup(&facade.FacadeStub{}, createAdminDatabaseHandlerStub(), &authMocks.TokenHandlerStub{}, config.RegistrationConfig{})
+		ws := startWebServerWithRoles(ag, "admin", getAdminRoutesConfig(), authentication.RolDirector)
+
+		request := core.RolRequest{Email: "mate@test.ro", Rol: authentication.RolAdmin}
+		req, _ := http.NewRequest("POST", "/admin/assignRol", requestToReader(request))
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusForbidden, resp.Code)
+		assert.True(t, strings.Contains(resp.Body.String(), authentication.PermUserWrite))
+	})
+	t.Run("should assign the role", func(t *testing.T) {
+		t.Parallel()
+
+		dbHandler := createAdminDatabaseHandlerStub()
+		var assigned *core.RolRequest
+		dbHandler.AssignRolCalled = func(request *core.RolRequest) error {
+			assigned = request
+			return nil
+		}
+		ag, _ := NewAdminGroup(&facade.FacadeStub{}, dbHandler, &authMocks.TokenHandlerStub{}, config.RegistrationConfig{})
+		ws := startWebServer(ag, "admin", getAdminRoutesConfig())
+
+		request := core.RolRequest{Email: "mate@test.ro", Rol: authentication.RolDiriginte, Clasa: "8A"}
+		req, _ := http.NewRequest("POST", "/admin/assignRol", requestToReader(request))
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		require.NotNil(t, assigned)
+		assert.Equal(t, request, *assigned)
+	})
+}
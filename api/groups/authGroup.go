@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/dragos-rebegea/evaluare-tool/api/shared"
 	"github.com/dragos-rebegea/evaluare-tool/authentication"
@@ -15,29 +16,37 @@ import (
 )
 
 const (
-	registerPath = "/register"
+	registerPath         = "/register"
+	acceptInvitationPath = "/acceptInvitation"
+	refreshPath          = "/refresh"
+	logoutPath           = "/logout"
 )
 
 type authGroup struct {
 	*baseGroup
 	facade               shared.FacadeHandler
 	mutFacade            sync.RWMutex
-	database             *core.DatabaseHandler
+	database             core.DatabaseHandler
+	tokenHandler         authentication.TokenHandler
 	authenticationNeeded bool
 }
 
 // NewAuthGroup returns a new instance of evaluationGroup
-func NewAuthGroup(facade shared.FacadeHandler, dbHandler *core.DatabaseHandler) (*authGroup, error) {
+func NewAuthGroup(facade shared.FacadeHandler, dbHandler core.DatabaseHandler, tokenHandler authentication.TokenHandler) (*authGroup, error) {
 	if check.IfNil(facade) {
 		return nil, fmt.Errorf("%w for auth group", errors.ErrNilFacadeHandler)
 	}
 	if check.IfNil(dbHandler) {
 		return nil, fmt.Errorf("%w for auth group", ErrNilDatabaseHandler)
 	}
+	if check.IfNil(tokenHandler) {
+		return nil, fmt.Errorf("%w for auth group", ErrNilTokenHandler)
+	}
 	ag := &authGroup{
 		facade:               facade,
 		baseGroup:            &baseGroup{},
 		database:             dbHandler,
+		tokenHandler:         tokenHandler,
 		authenticationNeeded: false,
 	}
 
@@ -52,40 +61,129 @@ func NewAuthGroup(facade shared.FacadeHandler, dbHandler *core.DatabaseHandler)
 			Method:  http.MethodPost,
 			Handler: ag.registerAdmin,
 		},
+		{
+			Path:    acceptInvitationPath,
+			Method:  http.MethodPost,
+			Handler: ag.acceptInvitation,
+		},
+		{
+			Path:    refreshPath,
+			Method:  http.MethodPost,
+			Handler: ag.refreshToken,
+		},
+		{
+			Path:    logoutPath,
+			Method:  http.MethodPost,
+			Handler: ag.logout,
+		},
 	}
 	ag.endpoints = endpoints
 
 	return ag, nil
 }
 
+// TokenRequest logs in a profesor by email, or a student by the username received when their class was created
 type TokenRequest struct {
 	Email    string `json:"email"`
+	Username string `json:"username"`
 	Password string `json:"password"`
 }
 
+// RefreshRequest identifies a session by its refresh token
+type RefreshRequest struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
+}
+
+// RegisterRequest registers the first admin. The setup token is optional, and neither way works once an admin
+// exists
+type RegisterRequest struct {
+	SetupToken string `json:"setupToken"`
+	authentication.Profesor
+}
+
+// AcceptInvitationRequest registers the invited user. The email is taken from the invitation
+type AcceptInvitationRequest struct {
+	Token string `json:"token" binding:"required"`
+	authentication.Profesor
+}
+
+// registerAdmin bootstraps the first admin, with or without a setup token printed at startup or created with
+// the setup-token command. Once an admin exists it answers with 403, and later admins are invited by an
+// existing admin
 func (ag *authGroup) registerAdmin(context *gin.Context) {
-	var admin authentication.Profesor
-	if err := context.ShouldBindJSON(&admin); err != nil {
+	var request RegisterRequest
+	if err := context.ShouldBindJSON(&request); err != nil {
 		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		context.Abort()
 		return
 	}
 
-	admin.IsAdmin = true
-	err := ag.database.CreateProfesor(&admin)
+	admin := request.Profesor
+	var err error
+	if len(request.SetupToken) == 0 {
+		err = ag.database.CreateFirstAdmin(&admin)
+	} else {
+		err = ag.database.RedeemInvitatie(authentication.HashToken(request.SetupToken), &admin)
+	}
+	if err == core.ErrAdminExists {
+		context.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		context.Abort()
+		return
+	}
+	if err == core.ErrInvalidInvitatie {
+		context.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		context.Abort()
+		return
+	}
 	if err != nil {
 		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		context.Abort()
 		return
 	}
 
+	respondWithCredentials(context, &admin)
+}
+
+// acceptInvitation registers a user invited by an admin, with the role of the invitation
+func (ag *authGroup) acceptInvitation(context *gin.Context) {
+	var request AcceptInvitationRequest
+	if err := context.ShouldBindJSON(&request); err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		context.Abort()
+		return
+	}
+
+	claims, err := ag.tokenHandler.ValidateInvitation(request.Token)
+	if err != nil {
+		context.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		context.Abort()
+		return
+	}
+
+	profesor := request.Profesor
+	profesor.Email = claims.Email
+	err = ag.database.RedeemInvitatie(claims.Id, &profesor)
+	if err == core.ErrInvalidInvitatie {
+		context.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		context.Abort()
+		return
+	}
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		context.Abort()
+		return
+	}
+
+	respondWithCredentials(context, &profesor)
+}
+
+func respondWithCredentials(context *gin.Context, profesor *authentication.Profesor) {
 	context.JSON(http.StatusCreated, gin.H{
-		"userId":   admin.ID,
-		"email":    admin.Email,
-		"username": admin.Username,
-		"password": admin.Password,
+		"userId":   profesor.ID,
+		"email":    profesor.Email,
+		"username": profesor.Username,
+		"password": profesor.Password,
 	})
-
 }
 
 func (ag *authGroup) generateToken(context *gin.Context) {
@@ -96,6 +194,11 @@ func (ag *authGroup) generateToken(context *gin.Context) {
 		return
 	}
 
+	if len(request.Username) > 0 {
+		ag.generateStudentToken(context, &request)
+		return
+	}
+
 	user, err := ag.database.GetProfesorByEmail(request.Email)
 	if err != nil {
 		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -110,13 +213,166 @@ func (ag *authGroup) generateToken(context *gin.Context) {
 		return
 	}
 
-	tokenString, err := authentication.GenerateJWT(user.Email, user.Username)
+	sesiuneID, refreshToken, ok := ag.startSesiune(context, user.Email, 0)
+	if !ok {
+		return
+	}
+	ag.respondWithTokens(context, user, sesiuneID, refreshToken)
+}
+
+// generateStudentToken logs in a student. The access token carries only the student role and the ID of the
+// student, so it can read nothing but the student's own results
+func (ag *authGroup) generateStudentToken(context *gin.Context, request *TokenRequest) {
+	student, err := ag.database.GetStudentByUsername(request.Username)
+	if err != nil {
+		context.JSON(http.StatusUnauthorized, gin.H{"error": "invalid credentials"})
+		context.Abort()
+		return
+	}
+
+	credentialError := student.CheckPassword(request.Password)
+	if credentialError != nil {
+		context.JSON(http.StatusUnauthorized, gin.H{"error": "invalid credentials"})
+		context.Abort()
+		return
+	}
+
+	sesiuneID, refreshToken, ok := ag.startSesiune(context, student.Email, student.ID)
+	if !ok {
+		return
+	}
+	ag.respondWithStudentTokens(context, student, sesiuneID, refreshToken)
+}
+
+// startSesiune stores a new session of the user and returns its ID and its refresh token. On failure it
+// responds with the error and returns false
+func (ag *authGroup) startSesiune(context *gin.Context, email string, student uint) (string, string, bool) {
+	refreshToken, refreshHash, err := authentication.NewToken()
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		context.Abort()
+		return "", "", false
+	}
+	sesiuneID, err := authentication.NewID()
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		context.Abort()
+		return "", "", false
+	}
+	err = ag.database.CreateSesiune(&authentication.Sesiune{
+		ID:          sesiuneID,
+		Email:       email,
+		Student:     student,
+		RefreshHash: refreshHash,
+		ExpiresAt:   time.Now().Add(ag.tokenHandler.RefreshTokenLifetime()),
+	})
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		context.Abort()
+		return "", "", false
+	}
+	return sesiuneID, refreshToken, true
+}
+
+// refreshToken exchanges a refresh token for a new access token and a new refresh token of the same session
+func (ag *authGroup) refreshToken(context *gin.Context) {
+	var request RefreshRequest
+	if err := context.ShouldBindJSON(&request); err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		context.Abort()
+		return
+	}
+
+	refreshToken, refreshHash, err := authentication.NewToken()
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		context.Abort()
+		return
+	}
+	expiresAt := time.Now().Add(ag.tokenHandler.RefreshTokenLifetime())
+	sesiune, err := ag.database.RefreshSesiune(authentication.HashToken(request.RefreshToken), refreshHash, expiresAt)
+	if err == core.ErrInvalidRefreshToken {
+		context.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		context.Abort()
+		return
+	}
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		context.Abort()
+		return
+	}
+
+	if sesiune.Student != 0 {
+		student, errStudent := ag.database.GetStudentByID(sesiune.Student)
+		if errStudent != nil {
+			context.JSON(http.StatusUnauthorized, gin.H{"error": errStudent.Error()})
+			context.Abort()
+			return
+		}
+		ag.respondWithStudentTokens(context, student, sesiune.ID, refreshToken)
+		return
+	}
+
+	user, err := ag.database.GetProfesorByEmail(sesiune.Email)
+	if err != nil {
+		context.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		context.Abort()
+		return
+	}
+
+	ag.respondWithTokens(context, user, sesiune.ID, refreshToken)
+}
+
+// logout closes the session of a refresh token, so neither its refresh token nor its access tokens are accepted
+func (ag *authGroup) logout(context *gin.Context) {
+	var request RefreshRequest
+	if err := context.ShouldBindJSON(&request); err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		context.Abort()
+		return
+	}
+
+	err := ag.database.RevokeSesiune(authentication.HashToken(request.RefreshToken))
+	if err == core.ErrInvalidRefreshToken {
+		context.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		context.Abort()
+		return
+	}
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		context.Abort()
+		return
+	}
+	context.JSON(http.StatusOK, gin.H{"revoked": true})
+}
+
+// respondWithTokens issues an access token carrying the current roles of the user
+func (ag *authGroup) respondWithTokens(context *gin.Context, user *authentication.Profesor, sesiune string, refreshToken string) {
+	roluri, err := ag.database.GetRoluri(user.Email)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		context.Abort()
+		return
+	}
+
+	tokenString, err := ag.tokenHandler.GenerateJWT(user.Email, user.Username, sesiune, authentication.RoleNames(roluri))
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		context.Abort()
+		return
+	}
+	context.JSON(http.StatusOK, gin.H{"token": tokenString, "refreshToken": refreshToken})
+}
+
+// respondWithStudentTokens issues an access token scoped to the student
+func (ag *authGroup) respondWithStudentTokens(context *gin.Context, student *authentication.Student, sesiune string, refreshToken string) {
+	tokenString, err := ag.tokenHandler.GenerateStudentJWT(student.ID, student.Email, student.Username, sesiune)
 	if err != nil {
 		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		context.Abort()
 		return
 	}
-	context.JSON(http.StatusOK, gin.H{"token": tokenString})
+	context.JSON(http.StatusOK, gin.H{"token": tokenString, "refreshToken": refreshToken})
 }
 
 // UpdateFacade will update the facade
@@ -1,8 +1,13 @@
 package groups
 
 import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
 	"strings"
 
+	"github.com/dragos-rebegea/evaluare-tool/authentication"
 	"github.com/dragos-rebegea/evaluare-tool/config"
 	"github.com/gin-gonic/gin"
 	"github.com/multiversx/mx-chain-go/api/shared"
@@ -11,6 +16,8 @@ import (
 
 var log = logger.GetOrCreate("api/groups")
 
+const csvFormat = "csv"
+
 type endpointProperties struct {
 	isOpen bool
 }
@@ -37,7 +44,37 @@ func (bg *baseGroup) RegisterRoutes(
 			continue
 		}
 
-		ws.Handle(handlerData.Method, handlerData.Path, handlerData.Handler)
+		middlewares := make([]gin.HandlerFunc, 0)
+		beforeSpecificMiddlewares, afterSpecificMiddlewares := extractSpecificMiddlewares(handlerData.AdditionalMiddlewares)
+
+		middlewares = append(middlewares, beforeSpecificMiddlewares...)
+		middlewares = append(middlewares, handlerData.Handler)
+		middlewares = append(middlewares, afterSpecificMiddlewares...)
+
+		ws.Handle(handlerData.Method, handlerData.Path, middlewares...)
+	}
+}
+
+func extractSpecificMiddlewares(middlewares []shared.AdditionalMiddleware) ([]gin.HandlerFunc, []gin.HandlerFunc) {
+	before := make([]gin.HandlerFunc, 0)
+	after := make([]gin.HandlerFunc, 0)
+	for _, middleware := range middlewares {
+		if middleware.Position == shared.Before {
+			before = append(before, middleware.Middleware)
+			continue
+		}
+		after = append(after, middleware.Middleware)
+	}
+	return before, after
+}
+
+// requirePermission returns the middleware rejecting the users whose roles do not grant the permission
+func requirePermission(permisiune string) []shared.AdditionalMiddleware {
+	return []shared.AdditionalMiddleware{
+		{
+			Middleware: authentication.RequirePermission(permisiune),
+			Position:   shared.Before,
+		},
 	}
 }
 
@@ -67,3 +104,24 @@ func getEndpointProperties(ws *gin.RouterGroup, path string, apiConfig config.Ap
 		isOpen: false,
 	}
 }
+
+// writeCSV sends the output of the provided writer as a CSV attachment. The report is rendered in memory first,
+// so that an error can still be returned as JSON
+func writeCSV(c *gin.Context, fileName string, write func(w io.Writer) error) {
+	buff := &bytes.Buffer{}
+	err := write(buff)
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			shared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  shared.ReturnCodeInternalError,
+			},
+		)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", buff.Bytes())
+}
@@ -7,6 +7,7 @@ import (
 	"io"
 
 	"github.com/dragos-rebegea/evaluare-tool/api/shared"
+	"github.com/dragos-rebegea/evaluare-tool/authentication"
 	"github.com/dragos-rebegea/evaluare-tool/config"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -22,8 +23,16 @@ func init() {
 }
 
 func startWebServer(group shared.GroupHandler, path string, apiConfig config.ApiRoutesConfig) *gin.Engine {
+	return startWebServerWithRoles(group, path, apiConfig, authentication.RolAdmin)
+}
+
+// startWebServerWithRoles serves the group as if the access token carried the provided roles
+func startWebServerWithRoles(group shared.GroupHandler, path string, apiConfig config.ApiRoutesConfig, roluri ...string) *gin.Engine {
 	ws := gin.New()
 	ws.Use(cors.Default())
+	ws.Use(func(c *gin.Context) {
+		c.Set(authentication.RoluriKey, roluri)
+	})
 	routes := ws.Group(path)
 	group.RegisterRoutes(routes, apiConfig)
 	return ws
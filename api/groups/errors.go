@@ -4,3 +4,12 @@ import "errors"
 
 // ErrNilDatabaseHandler signals that a nil database handler has been provided
 var ErrNilDatabaseHandler = errors.New("nil database handler")
+
+// ErrNilTokenHandler signals that a nil token handler has been provided
+var ErrNilTokenHandler = errors.New("nil token handler")
+
+// ErrMissingEmail signals that the request does not name the email of the user
+var ErrMissingEmail = errors.New("missing email")
+
+// ErrNotStudentToken signals that the access token does not belong to a student
+var ErrNotStudentToken = errors.New("access token does not belong to a student")
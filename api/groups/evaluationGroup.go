@@ -3,12 +3,14 @@ package groups
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"sync"
 
 	"github.com/dragos-rebegea/evaluare-tool/api/shared"
 	"github.com/dragos-rebegea/evaluare-tool/authentication"
 	"github.com/dragos-rebegea/evaluare-tool/core"
+	"github.com/dragos-rebegea/evaluare-tool/export"
 	"github.com/gin-gonic/gin"
 	"github.com/multiversx/mx-chain-core-go/core/check"
 	"github.com/multiversx/mx-chain-go/api/errors"
@@ -17,16 +19,18 @@ import (
 
 const tokenPath = "/token"
 
+const nivelElevi = "elevi"
+
 type evaluationGroup struct {
 	*baseGroup
 	facade               shared.FacadeHandler
 	mutFacade            sync.RWMutex
-	database             *core.DatabaseHandler
+	database             core.DatabaseHandler
 	authenticationNeeded bool
 }
 
 // NewEvaluationGroup returns a new instance of evaluationGroup
-func NewEvaluationGroup(facade shared.FacadeHandler, dbHandler *core.DatabaseHandler) (*evaluationGroup, error) {
+func NewEvaluationGroup(facade shared.FacadeHandler, dbHandler core.DatabaseHandler) (*evaluationGroup, error) {
 	if check.IfNil(facade) {
 		return nil, fmt.Errorf("%w for evaluation group", errors.ErrNilFacadeHandler)
 	}
@@ -42,39 +46,100 @@ func NewEvaluationGroup(facade shared.FacadeHandler, dbHandler *core.DatabaseHan
 
 	endpoints := []*elrondApiShared.EndpointHandlerData{
 		{
-			Path:    "/getStudentsByClass/:class",
-			Method:  http.MethodGet,
-			Handler: eg.getStudentsByClass,
+			Path:                  "/getStudentsByClass/:class",
+			Method:                http.MethodGet,
+			Handler:               eg.getStudentsByClass,
+			AdditionalMiddlewares: requirePermission(authentication.PermClassRead),
+		},
+		{
+			Path:                  "/getAllClasses",
+			Method:                http.MethodGet,
+			Handler:               eg.getAllClasses,
+			AdditionalMiddlewares: requirePermission(authentication.PermClassRead),
+		},
+		{
+			Path:                  "/addCalificativ",
+			Method:                http.MethodPost,
+			Handler:               eg.addCalificativ,
+			AdditionalMiddlewares: requirePermission(authentication.PermGradeWrite),
+		},
+		{
+			Path:                  "/addCalificative",
+			Method:                http.MethodPost,
+			Handler:               eg.addCalificative,
+			AdditionalMiddlewares: requirePermission(authentication.PermGradeWrite),
+		},
+		{
+			Path:                  "/setComentariu",
+			Method:                http.MethodPost,
+			Handler:               eg.setComentariu,
+			AdditionalMiddlewares: requirePermission(authentication.PermGradeWrite),
+		},
+		{
+			Path:                  "/updateCalificativ",
+			Method:                http.MethodPost,
+			Handler:               eg.updateCalificativ,
+			AdditionalMiddlewares: requirePermission(authentication.PermGradeWrite),
+		},
+		{
+			Path:                  "/getCalificative/:student",
+			Method:                http.MethodGet,
+			Handler:               eg.getCalificative,
+			AdditionalMiddlewares: requirePermission(authentication.PermGradeRead),
+		},
+		{
+			Path:                  "/getExercitii/:student",
+			Method:                http.MethodGet,
+			Handler:               eg.getExercitii,
+			AdditionalMiddlewares: requirePermission(authentication.PermGradeRead),
+		},
+		{
+			Path:                  "/getScore/:student",
+			Method:                http.MethodGet,
+			Handler:               eg.getScore,
+			AdditionalMiddlewares: requirePermission(authentication.PermGradeRead),
+		},
+		{
+			Path:                  "/getNote/:student",
+			Method:                http.MethodGet,
+			Handler:               eg.getNote,
+			AdditionalMiddlewares: requirePermission(authentication.PermGradeRead),
 		},
 		{
-			Path:    "/getAllClasses",
-			Method:  http.MethodGet,
-			Handler: eg.getAllClasses,
+			Path:                  "/getProgress",
+			Method:                http.MethodGet,
+			Handler:               eg.getProgress,
+			AdditionalMiddlewares: requirePermission(authentication.PermGradeWrite),
 		},
 		{
-			Path:    "/addCalificativ",
-			Method:  http.MethodPost,
-			Handler: eg.addCalificativ,
+			Path:                  "/getStatistics/:class/:exam",
+			Method:                http.MethodGet,
+			Handler:               eg.getStatistics,
+			AdditionalMiddlewares: requirePermission(authentication.PermClassRead),
 		},
 		{
-			Path:    "/updateCalificativ",
-			Method:  http.MethodPost,
-			Handler: eg.updateCalificativ,
+			Path:                  "/getCompetencyReport/:class/:exam",
+			Method:                http.MethodGet,
+			Handler:               eg.getCompetencyReport,
+			AdditionalMiddlewares: requirePermission(authentication.PermClassRead),
 		},
 		{
-			Path:    "/getCalificative/:student",
-			Method:  http.MethodGet,
-			Handler: eg.getCalificative,
+			Path:                  "/getEvolutie/:student",
+			Method:                http.MethodGet,
+			Handler:               eg.getEvolutie,
+			AdditionalMiddlewares: requirePermission(authentication.PermClassRead),
 		},
 		{
-			Path:    "/getExercitii/:student",
-			Method:  http.MethodGet,
-			Handler: eg.getExercitii,
+			Path:                  "/getEvolutieClasa/:class",
+			Method:                http.MethodGet,
+			Handler:               eg.getEvolutieClasa,
+			AdditionalMiddlewares: requirePermission(authentication.PermClassRead),
 		},
 		{
-			Path:    "/ping",
-			Method:  http.MethodGet,
-			Handler: eg.ping,
+			Path:                  "/ping",
+			Method:                http.MethodGet,
+			Handler:               eg.ping,
+			AdditionalMiddlewares: requirePermission(authentication.PermGradeRead),
 		},
 	}
 	eg.endpoints = endpoints
@@ -84,12 +149,8 @@ func NewEvaluationGroup(facade shared.FacadeHandler, dbHandler *core.DatabaseHan
 
 // sendTransaction returns will send the transaction signed by the guardian if the verification passed
 func (eg *evaluationGroup) getStudentsByClass(c *gin.Context) {
-	if !eg.checkIfProfesor(c) {
-		return
-	}
-
 	class := c.Param("class")
-	elevi, err := eg.database.GetStudentsByClass(class)
+	elevi, err := eg.database.GetStudentsByClass(class, c.Query("exam"))
 	if err != nil {
 		c.JSON(
 			http.StatusInternalServerError,
@@ -113,10 +174,6 @@ func (eg *evaluationGroup) getStudentsByClass(c *gin.Context) {
 }
 
 func (eg *evaluationGroup) getAllClasses(c *gin.Context) {
-	if !eg.checkIfProfesor(c) {
-		return
-	}
-
 	email := c.GetString(authentication.EmailKey)
 	classes, err := eg.database.GetAllClasses(email)
 	if err != nil {
@@ -142,10 +199,6 @@ func (eg *evaluationGroup) getAllClasses(c *gin.Context) {
 }
 
 func (eg *evaluationGroup) addCalificativ(c *gin.Context) {
-	if !eg.checkIfProfesor(c) {
-		return
-	}
-
 	var calificativ core.Calificativ
 	err := json.NewDecoder(c.Request.Body).Decode(&calificativ)
 	if err != nil {
@@ -183,12 +236,86 @@ func (eg *evaluationGroup) addCalificativ(c *gin.Context) {
 	)
 }
 
-// updateCalificativ
-func (eg *evaluationGroup) updateCalificativ(c *gin.Context) {
-	if !eg.checkIfProfesor(c) {
+// addCalificative will store many marks in one transaction and report the outcome of each of them
+func (eg *evaluationGroup) addCalificative(c *gin.Context) {
+	var batch core.CalificativBatch
+	err := json.NewDecoder(c.Request.Body).Decode(&batch)
+	if err != nil {
+		c.JSON(
+			http.StatusBadRequest,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeInternalError,
+			},
+		)
+		return
+	}
+	email := c.GetString(authentication.EmailKey)
+	result, err := eg.database.AddCalificative(email, &batch)
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeInternalError,
+			},
+		)
+		return
+	}
+
+	c.JSON(
+		http.StatusOK,
+		elrondApiShared.GenericAPIResponse{
+			Data:  result,
+			Error: "",
+			Code:  elrondApiShared.ReturnCodeSuccess,
+		},
+	)
+}
+
+// setComentariu will store the comment of the profesor on an exercise of a paper or on the whole paper
+func (eg *evaluationGroup) setComentariu(c *gin.Context) {
+	var request core.ComentariuRequest
+	err := json.NewDecoder(c.Request.Body).Decode(&request)
+	if err != nil {
+		c.JSON(
+			http.StatusBadRequest,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeInternalError,
+			},
+		)
+		return
+	}
+	email := c.GetString(authentication.EmailKey)
+	comentariu, err := eg.database.SetComentariu(email, &request)
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeInternalError,
+			},
+		)
 		return
 	}
 
+	c.JSON(
+		http.StatusOK,
+		elrondApiShared.GenericAPIResponse{
+			Data:  comentariu,
+			Error: "",
+			Code:  elrondApiShared.ReturnCodeSuccess,
+		},
+	)
+}
+
+// updateCalificativ
+func (eg *evaluationGroup) updateCalificativ(c *gin.Context) {
 	var calificativ core.Calificativ
 	err := json.NewDecoder(c.Request.Body).Decode(&calificativ)
 	if err != nil {
@@ -227,10 +354,6 @@ func (eg *evaluationGroup) updateCalificativ(c *gin.Context) {
 }
 
 func (eg *evaluationGroup) getExercitii(context *gin.Context) {
-	if !eg.checkIfProfesor(context) {
-		return
-	}
-
 	email := context.GetString(authentication.EmailKey)
 	student := context.Param("student")
 	exercitii, err := eg.database.GetExercitiiForProfesorAndStudent(email, student)
@@ -258,13 +381,37 @@ func (eg *evaluationGroup) getExercitii(context *gin.Context) {
 }
 
 func (eg *evaluationGroup) getCalificative(context *gin.Context) {
-	if !eg.checkIfProfesor(context) {
+	email := context.GetString(authentication.EmailKey)
+	student := context.Param("student")
+	calificative, err := eg.database.GetCalificative(email, student)
+	if err != nil {
+		context.JSON(
+			http.StatusInternalServerError,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeInternalError,
+			},
+		)
 		return
 	}
 
+	context.JSON(
+		http.StatusOK,
+		elrondApiShared.GenericAPIResponse{
+			Data:  calificative,
+			Error: "",
+			Code:  elrondApiShared.ReturnCodeSuccess,
+		},
+	)
+
+}
+
+// getScore returns the points of a student for every exam and subject
+func (eg *evaluationGroup) getScore(context *gin.Context) {
 	email := context.GetString(authentication.EmailKey)
 	student := context.Param("student")
-	calificative, err := eg.database.GetCalificative(email, student)
+	score, err := eg.database.GetStudentScore(email, student)
 	if err != nil {
 		context.JSON(
 			http.StatusInternalServerError,
@@ -280,46 +427,133 @@ func (eg *evaluationGroup) getCalificative(context *gin.Context) {
 	context.JSON(
 		http.StatusOK,
 		elrondApiShared.GenericAPIResponse{
-			Data:  calificative,
+			Data:  score,
 			Error: "",
 			Code:  elrondApiShared.ReturnCodeSuccess,
 		},
 	)
+}
 
+// getNote returns the grades of a student on the 1-10 scale and their average
+func (eg *evaluationGroup) getNote(context *gin.Context) {
+	email := context.GetString(authentication.EmailKey)
+	student := context.Param("student")
+	grades, err := eg.database.GetStudentGrades(email, student)
+	if err != nil {
+		context.JSON(
+			http.StatusInternalServerError,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeInternalError,
+			},
+		)
+		return
+	}
+
+	context.JSON(
+		http.StatusOK,
+		elrondApiShared.GenericAPIResponse{
+			Data:  grades,
+			Error: "",
+			Code:  elrondApiShared.ReturnCodeSuccess,
+		},
+	)
 }
 
-func (eg *evaluationGroup) ping(c *gin.Context) {
-	if !eg.checkIfProfesor(c) {
+// getProgress returns the grading progress of the profesor in every class they grade
+func (eg *evaluationGroup) getProgress(c *gin.Context) {
+	progress, err := eg.database.GetProfesorProgress(c.GetString(authentication.EmailKey))
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeInternalError,
+			},
+		)
 		return
 	}
 
 	c.JSON(
 		http.StatusOK,
 		elrondApiShared.GenericAPIResponse{
-			Data:  "pong",
+			Data:  gin.H{"progres": progress},
 			Error: "",
 			Code:  elrondApiShared.ReturnCodeSuccess,
 		},
 	)
 }
 
-// UpdateFacade will update the facade
-func (eg *evaluationGroup) UpdateFacade(newFacade shared.FacadeHandler) error {
-	if check.IfNil(newFacade) {
-		return errors.ErrNilFacadeHandler
+// getStatistics returns the variant distribution and the score statistics of a class on an exam
+func (eg *evaluationGroup) getStatistics(c *gin.Context) {
+	result, err := eg.database.GetClassStatistics(c.GetString(authentication.EmailKey), c.Param("class"), c.Param("exam"))
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeInternalError,
+			},
+		)
+		return
 	}
 
-	eg.mutFacade.Lock()
-	eg.facade = newFacade
-	eg.mutFacade.Unlock()
-
-	return nil
+	c.JSON(
+		http.StatusOK,
+		elrondApiShared.GenericAPIResponse{
+			Data:  result,
+			Error: "",
+			Code:  elrondApiShared.ReturnCodeSuccess,
+		},
+	)
 }
 
-func (eg *evaluationGroup) checkIfProfesor(c *gin.Context) bool {
-	email := c.GetString(authentication.EmailKey)
+// getCompetencyReport returns the mastery of the competencies of a class and of its students on an exam, weakest
+// first. With format=csv the class report is sent as CSV, or the student profiles if nivel=elevi is also requested
+func (eg *evaluationGroup) getCompetencyReport(c *gin.Context) {
+	class, exam := c.Param("class"), c.Param("exam")
+	report, err := eg.database.GetCompetencyReport(c.GetString(authentication.EmailKey), class, exam)
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeInternalError,
+			},
+		)
+		return
+	}
+
+	if c.Query("format") == csvFormat {
+		if c.Query("nivel") == nivelElevi {
+			writeCSV(c, class+"_"+exam+"_competente_elevi.csv", func(w io.Writer) error {
+				return export.WriteStudentCompetenciesCSV(w, report)
+			})
+			return
+		}
+		writeCSV(c, class+"_"+exam+"_competente.csv", func(w io.Writer) error {
+			return export.WriteCompetencyReportCSV(w, report)
+		})
+		return
+	}
 
-	isProfesor, err := eg.database.IsProfesor(email)
+	c.JSON(
+		http.StatusOK,
+		elrondApiShared.GenericAPIResponse{
+			Data:  report,
+			Error: "",
+			Code:  elrondApiShared.ReturnCodeSuccess,
+		},
+	)
+}
+
+// getEvolutie returns the results of a student on every exam they sat, with the change since the previous one
+func (eg *evaluationGroup) getEvolutie(c *gin.Context) {
+	result, err := eg.database.GetStudentEvolution(c.GetString(authentication.EmailKey), c.Param("student"))
 	if err != nil {
 		c.JSON(
 			http.StatusInternalServerError,
@@ -329,22 +563,67 @@ func (eg *evaluationGroup) checkIfProfesor(c *gin.Context) bool {
 				Code:  elrondApiShared.ReturnCodeInternalError,
 			},
 		)
-		return false
+		return
 	}
 
-	if !isProfesor {
+	c.JSON(
+		http.StatusOK,
+		elrondApiShared.GenericAPIResponse{
+			Data:  result,
+			Error: "",
+			Code:  elrondApiShared.ReturnCodeSuccess,
+		},
+	)
+}
+
+// getEvolutieClasa returns the averages of a class on every exam its students sat, with the change since the
+// previous one
+func (eg *evaluationGroup) getEvolutieClasa(c *gin.Context) {
+	result, err := eg.database.GetClassEvolution(c.GetString(authentication.EmailKey), c.Param("class"))
+	if err != nil {
 		c.JSON(
-			http.StatusForbidden,
+			http.StatusInternalServerError,
 			elrondApiShared.GenericAPIResponse{
 				Data:  nil,
-				Error: "Nu esti un profesor",
+				Error: err.Error(),
 				Code:  elrondApiShared.ReturnCodeInternalError,
 			},
 		)
-		return false
+		return
 	}
 
-	return true
+	c.JSON(
+		http.StatusOK,
+		elrondApiShared.GenericAPIResponse{
+			Data:  result,
+			Error: "",
+			Code:  elrondApiShared.ReturnCodeSuccess,
+		},
+	)
+}
+
+func (eg *evaluationGroup) ping(c *gin.Context) {
+	c.JSON(
+		http.StatusOK,
+		elrondApiShared.GenericAPIResponse{
+			Data:  "pong",
+			Error: "",
+			Code:  elrondApiShared.ReturnCodeSuccess,
+		},
+	)
+}
+
+// UpdateFacade will update the facade
+func (eg *evaluationGroup) UpdateFacade(newFacade shared.FacadeHandler) error {
+	if check.IfNil(newFacade) {
+		return errors.ErrNilFacadeHandler
+	}
+
+	eg.mutFacade.Lock()
+	eg.facade = newFacade
+	eg.mutFacade.Unlock()
+
+	return nil
 }
 
 // IsAuthenticationNeeded will return true if the group requires authentication
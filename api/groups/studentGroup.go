@@ -0,0 +1,233 @@
+package groups
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/dragos-rebegea/evaluare-tool/api/shared"
+	"github.com/dragos-rebegea/evaluare-tool/authentication"
+	"github.com/dragos-rebegea/evaluare-tool/core"
+	"github.com/gin-gonic/gin"
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-go/api/errors"
+	elrondApiShared "github.com/multiversx/mx-chain-go/api/shared"
+)
+
+type studentGroup struct {
+	*baseGroup
+	facade               shared.FacadeHandler
+	mutFacade            sync.RWMutex
+	database             core.DatabaseHandler
+	authenticationNeeded bool
+}
+
+// NewStudentGroup returns a new instance of studentGroup. Its routes serve only the student named by the access
+// token, so a student can never read the data of another student
+func NewStudentGroup(facade shared.FacadeHandler, dbHandler core.DatabaseHandler) (*studentGroup, error) {
+	if check.IfNil(facade) {
+		return nil, fmt.Errorf("%w for student group", errors.ErrNilFacadeHandler)
+	}
+	if check.IfNil(dbHandler) {
+		return nil, fmt.Errorf("%w for student group", ErrNilDatabaseHandler)
+	}
+	sg := &studentGroup{
+		facade:               facade,
+		baseGroup:            &baseGroup{},
+		database:             dbHandler,
+		authenticationNeeded: true,
+	}
+
+	endpoints := []*elrondApiShared.EndpointHandlerData{
+		{
+			Path:                  "/getExame",
+			Method:                http.MethodGet,
+			Handler:               sg.getExame,
+			AdditionalMiddlewares: requirePermission(authentication.PermResultsOwn),
+		},
+		{
+			Path:                  "/getRezultate/:exam",
+			Method:                http.MethodGet,
+			Handler:               sg.getRezultate,
+			AdditionalMiddlewares: requirePermission(authentication.PermResultsOwn),
+		},
+		{
+			Path:                  "/fileContestatie",
+			Method:                http.MethodPost,
+			Handler:               sg.fileContestatie,
+			AdditionalMiddlewares: requirePermission(authentication.PermContestatieFile),
+		},
+	}
+	sg.endpoints = endpoints
+
+	return sg, nil
+}
+
+// getExame will return the exams assigned to the student and whether their results were released
+func (sg *studentGroup) getExame(c *gin.Context) {
+	student, ok := studentFromToken(c)
+	if !ok {
+		return
+	}
+
+	exame, err := sg.database.GetStudentExame(student)
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeInternalError,
+			},
+		)
+		return
+	}
+
+	c.JSON(
+		http.StatusOK,
+		elrondApiShared.GenericAPIResponse{
+			Data:  gin.H{"exame": exame},
+			Error: "",
+			Code:  elrondApiShared.ReturnCodeSuccess,
+		},
+	)
+}
+
+// getRezultate will return the grade, the points per exercise with their variants and the comments of the
+// student on an exam whose results were released
+func (sg *studentGroup) getRezultate(c *gin.Context) {
+	student, ok := studentFromToken(c)
+	if !ok {
+		return
+	}
+
+	rezultate, err := sg.database.GetStudentRezultate(student, c.Param("exam"))
+	if err == core.ErrRezultateNepublicate || err == core.ErrStudentNotInExam {
+		c.JSON(
+			http.StatusForbidden,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeRequestError,
+			},
+		)
+		return
+	}
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeInternalError,
+			},
+		)
+		return
+	}
+
+	c.JSON(
+		http.StatusOK,
+		elrondApiShared.GenericAPIResponse{
+			Data:  rezultate,
+			Error: "",
+			Code:  elrondApiShared.ReturnCodeSuccess,
+		},
+	)
+}
+
+// fileContestatie will record the appeal of the student against their grade on one subject of an exam
+func (sg *studentGroup) fileContestatie(c *gin.Context) {
+	student, ok := studentFromToken(c)
+	if !ok {
+		return
+	}
+
+	var request core.StudentContestatieRequest
+	err := json.NewDecoder(c.Request.Body).Decode(&request)
+	if err != nil {
+		c.JSON(
+			http.StatusBadRequest,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeRequestError,
+			},
+		)
+		return
+	}
+
+	contestatie, err := sg.database.FileStudentContestatie(student, &request)
+	if err == core.ErrRezultateNepublicate || err == core.ErrStudentNotInExam {
+		c.JSON(
+			http.StatusForbidden,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeRequestError,
+			},
+		)
+		return
+	}
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: err.Error(),
+				Code:  elrondApiShared.ReturnCodeInternalError,
+			},
+		)
+		return
+	}
+
+	c.JSON(
+		http.StatusOK,
+		elrondApiShared.GenericAPIResponse{
+			Data:  contestatie,
+			Error: "",
+			Code:  elrondApiShared.ReturnCodeSuccess,
+		},
+	)
+}
+
+// studentFromToken returns the student named by the access token. Tokens of the profesori name no student and
+// are rejected, whatever their roles
+func studentFromToken(c *gin.Context) (uint, bool) {
+	student := c.GetUint(authentication.StudentKey)
+	if student == 0 {
+		c.JSON(
+			http.StatusForbidden,
+			elrondApiShared.GenericAPIResponse{
+				Data:  nil,
+				Error: ErrNotStudentToken.Error(),
+				Code:  elrondApiShared.ReturnCodeRequestError,
+			},
+		)
+		return 0, false
+	}
+	return student, true
+}
+
+// UpdateFacade will update the facade
+func (sg *studentGroup) UpdateFacade(newFacade shared.FacadeHandler) error {
+	if check.IfNil(newFacade) {
+		return errors.ErrNilFacadeHandler
+	}
+
+	sg.mutFacade.Lock()
+	sg.facade = newFacade
+	sg.mutFacade.Unlock()
+
+	return nil
+}
+
+// IsAuthenticationNeeded will return true if the group requires authentication
+func (sg *studentGroup) IsAuthenticationNeeded() bool {
+	return sg.authenticationNeeded
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (sg *studentGroup) IsInterfaceNil() bool {
+	return sg == nil
+}
@@ -0,0 +1,162 @@
+package groups
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dragos-rebegea/evaluare-tool/authentication"
+	"github.com/dragos-rebegea/evaluare-tool/config"
+	"github.com/dragos-rebegea/evaluare-tool/core"
+	"github.com/dragos-rebegea/evaluare-tool/testsCommon/database"
+	"github.com/dragos-rebegea/evaluare-tool/testsCommon/facade"
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/stretchr/testify/assert"
+)
+
+func getStudentRoutesConfig() config.ApiRoutesConfig {
+	return config.ApiRoutesConfig{
+		APIPackages: map[string]config.APIPackageConfig{
+			"student": {
+				Routes: []config.RouteConfig{
+					{Name: "/getExame", Open: true},
+					{Name: "/getRezultate/:exam", Open: true},
+					{Name: "/fileContestatie", Open: true},
+				},
+			},
+		},
+	}
+}
+
+// startStudentWebServer serves the group as if the access token belonged to the student
+func startStudentWebServer(group *studentGroup, student uint) *gin.Engine {
+	ws := gin.New()
+	ws.Use(cors.Default())
+	ws.Use(func(c *gin.Context) {
+		c.Set(authentication.RoluriKey, []string{authentication.RolStudent})
+		c.Set(authentication.StudentKey, student)
+	})
+	routes := ws.Group("student")
+	group.RegisterRoutes(routes, getStudentRoutesConfig())
+	return ws
+}
+
+func TestNewStudentGroup(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil facade should error", func(t *testing.T) {
+		t.Parallel()
+
+		sg, err := NewStudentGroup(nil, &database.DatabaseHandlerStub{})
+		assert.NotNil(t, err)
+		assert.True(t, check.IfNil(sg))
+	})
+	t.Run("nil database handler should error", func(t *testing.T) {
+		t.Parallel()
+
+		sg, err := NewStudentGroup(&facade.FacadeStub{}, nil)
+		assert.True(t, errors.Is(err, ErrNilDatabaseHandler))
+		assert.True(t, check.IfNil(sg))
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		sg, err := NewStudentGroup(&facade.FacadeStub{}, &database.DatabaseHandlerStub{})
+		assert.Nil(t, err)
+		assert.False(t, check.IfNil(sg))
+	})
+}
+
+func TestStudentGroup_getRezultate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("profesor token should error", func(t *testing.T) {
+		t.Parallel()
+
+		sg, _ := NewStudentGroup(&facade.FacadeStub{}, &database.DatabaseHandlerStub{})
+		ws := startWebServerWithRoles(sg, "student", getStudentRoutesConfig(), authentication.RolAdmin)
+
+		req, _ := http.NewRequest("GET", "/student/getRezultate/simulare", nil)
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusForbidden, resp.Code)
+	})
+	t.Run("token without student should error", func(t *testing.T) {
+		t.Parallel()
+
+		sg, _ := NewStudentGroup(&facade.FacadeStub{}, &database.DatabaseHandlerStub{})
+		ws := startWebServerWithRoles(sg, "student", getStudentRoutesConfig(), authentication.RolStudent)
+
+		req, _ := http.NewRequest("GET", "/student/getRezultate/simulare", nil)
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusForbidden, resp.Code)
+		assert.True(t, strings.Contains(resp.Body.String(), ErrNotStudentToken.Error()))
+	})
+	t.Run("unreleased results should error", func(t *testing.T) {
+		t.Parallel()
+
+		dbHandler := &database.DatabaseHandlerStub{
+			GetStudentRezultateCalled: func(studentId uint, exam string) (*core.StudentRezultate, error) {
+				return nil, core.ErrRezultateNepublicate
+			},
+		}
+		sg, _ := NewStudentGroup(&facade.FacadeStub{}, dbHandler)
+		ws := startStudentWebServer(sg, 7)
+
+		req, _ := http.NewRequest("GET", "/student/getRezultate/simulare", nil)
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusForbidden, resp.Code)
+	})
+	t.Run("should return the results of the student of the token", func(t *testing.T) {
+		t.Parallel()
+
+		var studentCerut uint
+		dbHandler := &database.DatabaseHandlerStub{
+			GetStudentRezultateCalled: func(studentId uint, exam string) (*core.StudentRezultate, error) {
+				studentCerut = studentId
+				return &core.StudentRezultate{Exam: exam}, nil
+			},
+		}
+		sg, _ := NewStudentGroup(&facade.FacadeStub{}, dbHandler)
+		ws := startStudentWebServer(sg, 7)
+
+		req, _ := http.NewRequest("GET", "/student/getRezultate/simulare", nil)
+		resp := httptest.NewRecorder()
+		ws.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, uint(7), studentCerut)
+		assert.True(t, strings.Contains(resp.Body.String(), `"exam":"simulare"`))
+	})
+}
+
+func TestStudentGroup_fileContestatie(t *testing.T) {
+	t.Parallel()
+
+	var studentCerut uint
+	dbHandler := &database.DatabaseHandlerStub{
+		FileStudentContestatieCalled: func(studentId uint, request *core.StudentContestatieRequest) (*authentication.Contestatie, error) {
+			studentCerut = studentId
+			return &authentication.Contestatie{Student: studentId, Exam: request.Exam, Materie: request.Materie}, nil
+		},
+	}
+	sg, _ := NewStudentGroup(&facade.FacadeStub{}, dbHandler)
+	ws := startStudentWebServer(sg, 7)
+
+	request := core.StudentContestatieRequest{Exam: "simulare", Materie: "matematica", Motiv: "ex. 2"}
+	req, _ := http.NewRequest("POST", "/student/fileContestatie", requestToReader(request))
+	resp := httptest.NewRecorder()
+	ws.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, uint(7), studentCerut)
+}
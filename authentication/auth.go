@@ -1,6 +1,7 @@
 package authentication
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -9,9 +10,14 @@ import (
 const (
 	UsernameKey = "username"
 	EmailKey    = "email"
+	SesiuneKey  = "sesiune"
+	RoluriKey   = "roluri"
+	StudentKey  = "student"
 )
 
-func Auth() gin.HandlerFunc {
+// Auth rejects the requests without a valid access token or whose session was revoked, and stores the user of
+// the token in the context
+func Auth(tokenHandler TokenHandler, sessions SessionChecker) gin.HandlerFunc {
 	return func(context *gin.Context) {
 		tokenString := strings.Split(context.Request.Header.Get("Authorization"), "Bearer ")
 		if len(tokenString) != 2 {
@@ -19,14 +25,42 @@ func Auth() gin.HandlerFunc {
 			context.Abort()
 			return
 		}
-		token, err := ValidateToken(tokenString[1])
+		token, err := tokenHandler.ValidateToken(tokenString[1])
 		if err != nil {
 			context.JSON(401, gin.H{"error": err.Error()})
 			context.Abort()
 			return
 		}
+		active, err := sessions.IsSesiuneActiva(token.Sesiune)
+		if err != nil {
+			context.JSON(500, gin.H{"error": err.Error()})
+			context.Abort()
+			return
+		}
+		if !active {
+			context.JSON(401, gin.H{"error": ErrSessionRevoked.Error()})
+			context.Abort()
+			return
+		}
 		context.Set(UsernameKey, token.Username)
+		context.Set(SesiuneKey, token.Sesiune)
+		context.Set(RoluriKey, token.Roluri)
 		context.Set(EmailKey, token.Email)
+		context.Set(StudentKey, token.Student)
+		context.Next()
+	}
+}
+
+// RequirePermission rejects the requests whose access token carries no role granting the permission. It must run
+// after Auth, which stores the roles of the token in the context
+func RequirePermission(permisiune string) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		roluri := context.GetStringSlice(RoluriKey)
+		if !HasPermission(roluri, permisiune) {
+			context.JSON(403, gin.H{"error": fmt.Sprintf("%s: %s", ErrPermissionDenied.Error(), permisiune)})
+			context.Abort()
+			return
+		}
 		context.Next()
 	}
 }
@@ -0,0 +1,39 @@
+package authentication
+
+import "errors"
+
+// ErrInvalidTokenLifetime signals that the configured lifetime of the access or refresh tokens is not positive
+var ErrInvalidTokenLifetime = errors.New("invalid token lifetime")
+
+// ErrMissingKeyID signals that a configured key has no ID
+var ErrMissingKeyID = errors.New("missing key ID")
+
+// ErrDuplicateKeyID signals that two configured keys share the same ID
+var ErrDuplicateKeyID = errors.New("duplicate key ID")
+
+// ErrMissingSigningKey signals that the signing key is not configured or cannot sign tokens
+var ErrMissingSigningKey = errors.New("missing signing key")
+
+// ErrUnsupportedAlgorithm signals that a configured key uses an algorithm that is not supported
+var ErrUnsupportedAlgorithm = errors.New("unsupported algorithm")
+
+// ErrWeakSecret signals that a HS256 secret is too short
+var ErrWeakSecret = errors.New("secret must have at least 32 bytes")
+
+// ErrInvalidKeyFile signals that a key file does not hold a key usable with the configured algorithm
+var ErrInvalidKeyFile = errors.New("invalid key file")
+
+// ErrUnknownKeyID signals that a token was signed by a key that is not accepted anymore
+var ErrUnknownKeyID = errors.New("token signed by an unknown key")
+
+// ErrInvalidSignature signals that the signature of a token does not match its content
+var ErrInvalidSignature = errors.New("invalid token signature")
+
+// ErrSessionRevoked signals that the session of an access token was closed or revoked
+var ErrSessionRevoked = errors.New("session revoked")
+
+// ErrPermissionDenied signals that the roles of the user do not grant the permission required by a route
+var ErrPermissionDenied = errors.New("permission denied")
+
+// ErrInvalidInvitation signals that a token is not an invitation
+var ErrInvalidInvitation = errors.New("invalid invitation")
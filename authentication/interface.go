@@ -0,0 +1,20 @@
+package authentication
+
+import "time"
+
+// TokenHandler issues and verifies the access tokens of the API
+type TokenHandler interface {
+	GenerateJWT(email string, username string, sesiune string, roluri []string) (string, error)
+	ValidateToken(signedToken string) (*JWTClaim, error)
+	GenerateStudentJWT(student uint, email string, username string, sesiune string) (string, error)
+	RefreshTokenLifetime() time.Duration
+	GenerateInvitation(id string, email string, rol string, expiresAt time.Time) (string, error)
+	ValidateInvitation(signedInvitation string) (*InvitationClaim, error)
+	IsInterfaceNil() bool
+}
+
+// SessionChecker tells whether the session of an access token is still active
+type SessionChecker interface {
+	IsSesiuneActiva(id string) (bool, error)
+	IsInterfaceNil() bool
+}
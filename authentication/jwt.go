@@ -2,56 +2,196 @@ package authentication
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/dgrijalva/jwt-go"
+	"github.com/dragos-rebegea/evaluare-tool/config"
 )
 
-var jwtKey = []byte("supersecretkey")
+const (
+	keyIDHeader   = "kid"
+	scopInvitatie = "invitatie"
+)
 
 type JWTClaim struct {
-	Username string `json:"username"`
-	Email    string `json:"email"`
+	Username string   `json:"username"`
+	Email    string   `json:"email"`
+	Sesiune  string   `json:"sid"`
+	Roluri   []string `json:"roles"`
+	Student  uint     `json:"student,omitempty"`
+	jwt.StandardClaims
+}
+
+// InvitationClaim is the content of a signed invitation. The ID of the invitation is the jti claim
+type InvitationClaim struct {
+	Email string `json:"email"`
+	Rol   string `json:"rol"`
+	Scop  string `json:"scop"`
 	jwt.StandardClaims
 }
 
-func GenerateJWT(email string, username string) (tokenString string, err error) {
-	expirationTime := time.Now().Add(24 * 30 * time.Hour)
+type jwtHandler struct {
+	signingKey      *jwtKey
+	keys            map[string]*jwtKey
+	lifetime        time.Duration
+	refreshLifetime time.Duration
+}
+
+// NewJWTHandler loads the configured keys and returns a handler signing tokens with the signing key and accepting
+// the tokens signed by any of the keys, so the signing key can be rotated without invalidating the issued tokens
+func NewJWTHandler(cfg config.JWTConfig) (*jwtHandler, error) {
+	if cfg.AccessTokenLifetimeInSec <= 0 || cfg.RefreshTokenLifetimeInSec <= 0 {
+		return nil, ErrInvalidTokenLifetime
+	}
+
+	handler := &jwtHandler{
+		keys:            make(map[string]*jwtKey, len(cfg.Keys)),
+		lifetime:        time.Duration(cfg.AccessTokenLifetimeInSec) * time.Second,
+		refreshLifetime: time.Duration(cfg.RefreshTokenLifetimeInSec) * time.Second,
+	}
+	for _, keyConfig := range cfg.Keys {
+		if len(keyConfig.ID) == 0 {
+			return nil, ErrMissingKeyID
+		}
+		_, found := handler.keys[keyConfig.ID]
+		if found {
+			return nil, fmt.Errorf("%w %s", ErrDuplicateKeyID, keyConfig.ID)
+		}
+		key, err := loadKey(keyConfig)
+		if err != nil {
+			return nil, err
+		}
+		handler.keys[key.id] = key
+	}
+
+	handler.signingKey = handler.keys[cfg.SigningKeyID]
+	if handler.signingKey == nil || handler.signingKey.signKey == nil {
+		return nil, fmt.Errorf("%w %q", ErrMissingSigningKey, cfg.SigningKeyID)
+	}
+	return handler, nil
+}
+
+// GenerateJWT returns an access token for the provided user, session and roles, signed with the signing key
+func (handler *jwtHandler) GenerateJWT(email string, username string, sesiune string, roluri []string) (string, error) {
+	now := time.Now()
 	claims := &JWTClaim{
 		Email:    email,
 		Username: username,
+		Sesiune:  sesiune,
+		Roluri:   roluri,
 		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: expirationTime.Unix(),
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(handler.lifetime).Unix(),
 		},
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err = token.SignedString(jwtKey)
-	return
+	return handler.sign(claims)
 }
 
-func ValidateToken(signedToken string) (t *JWTClaim, err error) {
-	token, err := jwt.ParseWithClaims(
-		signedToken,
-		&JWTClaim{},
-		func(token *jwt.Token) (interface{}, error) {
-			return []byte(jwtKey), nil
+// GenerateStudentJWT returns an access token scoped to one student, carrying only the student role
+func (handler *jwtHandler) GenerateStudentJWT(student uint, email string, username string, sesiune string) (string, error) {
+	now := time.Now()
+	claims := &JWTClaim{
+		Email:    email,
+		Username: username,
+		Sesiune:  sesiune,
+		Roluri:   []string{RolStudent},
+		Student:  student,
+		StandardClaims: jwt.StandardClaims{
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(handler.lifetime).Unix(),
 		},
-	)
+	}
+	return handler.sign(claims)
+}
 
+// ValidateToken checks the signature and the expiry of a token and returns its claims. The token must name in its
+// header a configured key using the same algorithm as the token
+func (handler *jwtHandler) ValidateToken(signedToken string) (*JWTClaim, error) {
+	token, err := jwt.ParseWithClaims(signedToken, &JWTClaim{}, handler.verificationKey)
+	validationErr, ok := err.(*jwt.ValidationError)
+	if ok && validationErr.Inner != nil {
+		return nil, validationErr.Inner
+	}
 	if err != nil {
-		return
+		return nil, err
 	}
 
 	claims, ok := token.Claims.(*JWTClaim)
 	if !ok {
-		err = errors.New("couldn't parse claims")
-		return nil, err
+		return nil, errors.New("couldn't parse claims")
 	}
 
 	if claims.ExpiresAt < time.Now().Local().Unix() {
-		err = errors.New("token expired")
+		return nil, errors.New("token expired")
+	}
+
+	return claims, nil
+}
+
+// GenerateInvitation returns a signed invitation allowing the provided email to register with the role until
+// expiresAt
+func (handler *jwtHandler) GenerateInvitation(id string, email string, rol string, expiresAt time.Time) (string, error) {
+	claims := &InvitationClaim{
+		Email: email,
+		Rol:   rol,
+		Scop:  scopInvitatie,
+		StandardClaims: jwt.StandardClaims{
+			Id:        id,
+			IssuedAt:  time.Now().Unix(),
+			ExpiresAt: expiresAt.Unix(),
+		},
+	}
+	return handler.sign(claims)
+}
+
+// ValidateInvitation checks the signature and the expiry of an invitation and returns its claims. Access tokens
+// are not accepted as invitations
+func (handler *jwtHandler) ValidateInvitation(signedInvitation string) (*InvitationClaim, error) {
+	token, err := jwt.ParseWithClaims(signedInvitation, &InvitationClaim{}, handler.verificationKey)
+	validationErr, ok := err.(*jwt.ValidationError)
+	if ok && validationErr.Inner != nil {
+		return nil, validationErr.Inner
+	}
+	if err != nil {
 		return nil, err
 	}
 
+	claims, ok := token.Claims.(*InvitationClaim)
+	if !ok || claims.Scop != scopInvitatie || len(claims.Id) == 0 {
+		return nil, ErrInvalidInvitation
+	}
+	if claims.ExpiresAt < time.Now().Unix() {
+		return nil, errors.New("invitation expired")
+	}
+
 	return claims, nil
 }
+
+func (handler *jwtHandler) sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(handler.signingKey.method, claims)
+	token.Header[keyIDHeader] = handler.signingKey.id
+	return token.SignedString(handler.signingKey.signKey)
+}
+
+func (handler *jwtHandler) verificationKey(token *jwt.Token) (interface{}, error) {
+	id, _ := token.Header[keyIDHeader].(string)
+	key, found := handler.keys[id]
+	if !found {
+		return nil, ErrUnknownKeyID
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("%w: key %s does not use %s", ErrUnsupportedAlgorithm, id, token.Method.Alg())
+	}
+	return key.verifyKey, nil
+}
+
+// RefreshTokenLifetime returns how long a session can be refreshed after its last refresh
+func (handler *jwtHandler) RefreshTokenLifetime() time.Duration {
+	return handler.refreshLifetime
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (handler *jwtHandler) IsInterfaceNil() bool {
+	return handler == nil
+}
@@ -0,0 +1,305 @@
+package authentication
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/dragos-rebegea/evaluare-tool/config"
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testSecret   = "0123456789abcdef0123456789abcdef"
+	gracePemFile = "../factory/testdata/grace.pem"
+)
+
+func createMockJWTConfig() config.JWTConfig {
+	return config.JWTConfig{
+		SigningKeyID:              "hs-1",
+		AccessTokenLifetimeInSec:  3600,
+		RefreshTokenLifetimeInSec: 7200,
+		Keys: []config.JWTKeyConfig{
+			{ID: "hs-1", Algorithm: "HS256", Secret: testSecret},
+		},
+	}
+}
+
+func writePemFile(t *testing.T, blockType string, content []byte) string {
+	file := filepath.Join(t.TempDir(), "key.pem")
+	err := ioutil.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: content}), 0600)
+	require.Nil(t, err)
+	return file
+}
+
+func requireRoundTrip(t *testing.T, handler *jwtHandler) {
+	signed, err := handler.GenerateJWT("ana@scoala.ro", "ana", "s1", []string{RolProfesor})
+	require.Nil(t, err)
+
+	claims, err := handler.ValidateToken(signed)
+	require.Nil(t, err)
+	assert.Equal(t, "ana@scoala.ro", claims.Email)
+	assert.Equal(t, "ana", claims.Username)
+	assert.Equal(t, "s1", claims.Sesiune)
+	assert.Equal(t, []string{RolProfesor}, claims.Roluri)
+}
+
+func TestNewJWTHandler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid lifetime should error", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := createMockJWTConfig()
+		cfg.RefreshTokenLifetimeInSec = 0
+		handler, err := NewJWTHandler(cfg)
+		assert.Equal(t, ErrInvalidTokenLifetime, err)
+		assert.True(t, check.IfNil(handler))
+	})
+	t.Run("short secret should error", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := createMockJWTConfig()
+		cfg.Keys[0].Secret = "supersecretkey"
+		handler, err := NewJWTHandler(cfg)
+		assert.True(t, errors.Is(err, ErrWeakSecret))
+		assert.True(t, check.IfNil(handler))
+	})
+	t.Run("duplicate key should error", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := createMockJWTConfig()
+		cfg.Keys = append(cfg.Keys, cfg.Keys[0])
+		_, err := NewJWTHandler(cfg)
+		assert.True(t, errors.Is(err, ErrDuplicateKeyID))
+	})
+	t.Run("unknown algorithm should error", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := createMockJWTConfig()
+		cfg.Keys[0].Algorithm = "none"
+		_, err := NewJWTHandler(cfg)
+		assert.True(t, errors.Is(err, ErrUnsupportedAlgorithm))
+	})
+	t.Run("missing signing key should error", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := createMockJWTConfig()
+		cfg.SigningKeyID = "hs-2"
+		_, err := NewJWTHandler(cfg)
+		assert.True(t, errors.Is(err, ErrMissingSigningKey))
+	})
+	t.Run("public key cannot sign", func(t *testing.T) {
+		t.Parallel()
+
+		publicKey, _, _ := ed25519.GenerateKey(rand.Reader)
+		encoded, _ := x509.MarshalPKIXPublicKey(publicKey)
+		cfg := createMockJWTConfig()
+		cfg.SigningKeyID = "ed-1"
+		cfg.Keys = append(cfg.Keys, config.JWTKeyConfig{ID: "ed-1", Algorithm: "EdDSA", File: writePemFile(t, "PUBLIC KEY", encoded)})
+		_, err := NewJWTHandler(cfg)
+		assert.True(t, errors.Is(err, ErrMissingSigningKey))
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		handler, err := NewJWTHandler(createMockJWTConfig())
+		assert.Nil(t, err)
+		assert.False(t, check.IfNil(handler))
+	})
+}
+
+func TestJWTHandler_Algorithms(t *testing.T) {
+	t.Parallel()
+
+	t.Run("HS256 secret from file", func(t *testing.T) {
+		t.Parallel()
+
+		file := filepath.Join(t.TempDir(), "secret")
+		require.Nil(t, ioutil.WriteFile(file, []byte(testSecret+"\n"), 0600))
+		cfg := createMockJWTConfig()
+		cfg.Keys[0].Secret = ""
+		cfg.Keys[0].File = file
+		handler, err := NewJWTHandler(cfg)
+		require.Nil(t, err)
+		requireRoundTrip(t, handler)
+	})
+	t.Run("RS256", func(t *testing.T) {
+		t.Parallel()
+
+		privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.Nil(t, err)
+		cfg := createMockJWTConfig()
+		cfg.SigningKeyID = "rs-1"
+		cfg.Keys = []config.JWTKeyConfig{
+			{ID: "rs-1", Algorithm: "RS256", File: writePemFile(t, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(privateKey))},
+		}
+		handler, err := NewJWTHandler(cfg)
+		require.Nil(t, err)
+		requireRoundTrip(t, handler)
+	})
+	t.Run("EdDSA PKCS8", func(t *testing.T) {
+		t.Parallel()
+
+		_, privateKey, _ := ed25519.GenerateKey(rand.Reader)
+		encoded, err := x509.MarshalPKCS8PrivateKey(privateKey)
+		require.Nil(t, err)
+		cfg := createMockJWTConfig()
+		cfg.SigningKeyID = "ed-1"
+		cfg.Keys = []config.JWTKeyConfig{{ID: "ed-1", Algorithm: "EdDSA", File: writePemFile(t, "PRIVATE KEY", encoded)}}
+		handler, err := NewJWTHandler(cfg)
+		require.Nil(t, err)
+		requireRoundTrip(t, handler)
+	})
+	t.Run("EdDSA wallet pem", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := createMockJWTConfig()
+		cfg.SigningKeyID = "grace"
+		cfg.Keys = []config.JWTKeyConfig{{ID: "grace", Algorithm: "EdDSA", File: gracePemFile}}
+		handler, err := NewJWTHandler(cfg)
+		require.Nil(t, err)
+		requireRoundTrip(t, handler)
+
+		signed, _ := handler.GenerateJWT("ana@scoala.ro", "ana", "s1", []string{RolProfesor})
+		token, _, err := new(jwt.Parser).ParseUnverified(signed, &JWTClaim{})
+		require.Nil(t, err)
+		assert.Equal(t, "EdDSA", token.Method.Alg())
+		assert.Equal(t, "grace", token.Header["kid"])
+	})
+}
+
+func TestJWTHandler_ValidateToken(t *testing.T) {
+	t.Parallel()
+
+	t.Run("student token carries only the student role", func(t *testing.T) {
+		t.Parallel()
+
+		handler, _ := NewJWTHandler(createMockJWTConfig())
+		signed, err := handler.GenerateStudentJWT(7, "ion@scoala.ro", "Popescu_Ion", "s1")
+		require.Nil(t, err)
+
+		claims, err := handler.ValidateToken(signed)
+		require.Nil(t, err)
+		assert.Equal(t, uint(7), claims.Student)
+		assert.Equal(t, []string{RolStudent}, claims.Roluri)
+		assert.False(t, HasPermission(claims.Roluri, PermClassRead))
+	})
+
+	t.Run("rotated key is still accepted", func(t *testing.T) {
+		t.Parallel()
+
+		oldHandler, err := NewJWTHandler(createMockJWTConfig())
+		require.Nil(t, err)
+		signed, err := oldHandler.GenerateJWT("ana@scoala.ro", "ana", "s1", []string{RolProfesor})
+		require.Nil(t, err)
+
+		cfg := createMockJWTConfig()
+		cfg.SigningKeyID = "grace"
+		cfg.Keys = append(cfg.Keys, config.JWTKeyConfig{ID: "grace", Algorithm: "EdDSA", File: gracePemFile})
+		newHandler, err := NewJWTHandler(cfg)
+		require.Nil(t, err)
+
+		claims, err := newHandler.ValidateToken(signed)
+		require.Nil(t, err)
+		assert.Equal(t, "ana@scoala.ro", claims.Email)
+
+		cfg.Keys = cfg.Keys[1:]
+		retiredHandler, err := NewJWTHandler(cfg)
+		require.Nil(t, err)
+		_, err = retiredHandler.ValidateToken(signed)
+		assert.True(t, errors.Is(err, ErrUnknownKeyID))
+	})
+	t.Run("token without kid should error", func(t *testing.T) {
+		t.Parallel()
+
+		handler, _ := NewJWTHandler(createMockJWTConfig())
+		signed, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, &JWTClaim{Email: "ana@scoala.ro"}).SignedString([]byte(testSecret))
+		_, err := handler.ValidateToken(signed)
+		assert.True(t, errors.Is(err, ErrUnknownKeyID))
+	})
+	t.Run("algorithm of the token must match the key", func(t *testing.T) {
+		t.Parallel()
+
+		publicKey, _, _ := ed25519.GenerateKey(rand.Reader)
+		encoded, _ := x509.MarshalPKIXPublicKey(publicKey)
+		cfg := createMockJWTConfig()
+		cfg.Keys = append(cfg.Keys, config.JWTKeyConfig{ID: "ed-1", Algorithm: "EdDSA", File: writePemFile(t, "PUBLIC KEY", encoded)})
+		handler, err := NewJWTHandler(cfg)
+		require.Nil(t, err)
+
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, &JWTClaim{Email: "ana@scoala.ro"})
+		token.Header["kid"] = "ed-1"
+		signed, _ := token.SignedString([]byte(encoded))
+		_, err = handler.ValidateToken(signed)
+		assert.True(t, errors.Is(err, ErrUnsupportedAlgorithm))
+	})
+	t.Run("tampered token should error", func(t *testing.T) {
+		t.Parallel()
+
+		handler, _ := NewJWTHandler(createMockJWTConfig())
+		signed, _ := handler.GenerateJWT("ana@scoala.ro", "ana", "s1", []string{RolProfesor})
+		parts := strings.Split(signed, ".")
+		forged, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, &JWTClaim{Email: "admin@scoala.ro"}).SigningString()
+		_, err := handler.ValidateToken(forged + "." + parts[2])
+		assert.NotNil(t, err)
+	})
+	t.Run("expired token should error", func(t *testing.T) {
+		t.Parallel()
+
+		handler, _ := NewJWTHandler(createMockJWTConfig())
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, &JWTClaim{
+			Email:          "ana@scoala.ro",
+			StandardClaims: jwt.StandardClaims{ExpiresAt: 1},
+		})
+		token.Header["kid"] = "hs-1"
+		signed, _ := token.SignedString([]byte(testSecret))
+		_, err := handler.ValidateToken(signed)
+		assert.NotNil(t, err)
+	})
+}
+
+func TestJWTHandler_ValidateInvitation(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should round trip", func(t *testing.T) {
+		t.Parallel()
+
+		handler, _ := NewJWTHandler(createMockJWTConfig())
+		signed, err := handler.GenerateInvitation("inv-1", "ana@scoala.ro", RolAdmin, time.Now().Add(time.Hour))
+		require.Nil(t, err)
+
+		claims, err := handler.ValidateInvitation(signed)
+		require.Nil(t, err)
+		assert.Equal(t, "inv-1", claims.Id)
+		assert.Equal(t, "ana@scoala.ro", claims.Email)
+		assert.Equal(t, RolAdmin, claims.Rol)
+	})
+	t.Run("access token is not an invitation", func(t *testing.T) {
+		t.Parallel()
+
+		handler, _ := NewJWTHandler(createMockJWTConfig())
+		signed, _ := handler.GenerateJWT("ana@scoala.ro", "ana", "s1", []string{RolAdmin})
+		_, err := handler.ValidateInvitation(signed)
+		assert.Equal(t, ErrInvalidInvitation, err)
+	})
+	t.Run("expired invitation should error", func(t *testing.T) {
+		t.Parallel()
+
+		handler, _ := NewJWTHandler(createMockJWTConfig())
+		signed, _ := handler.GenerateInvitation("inv-1", "ana@scoala.ro", RolAdmin, time.Now().Add(-time.Minute))
+		_, err := handler.ValidateInvitation(signed)
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,146 @@
+package authentication
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/dragos-rebegea/evaluare-tool/config"
+)
+
+const (
+	minSecretLength = 32
+	walletPemPrefix = "PRIVATE KEY for "
+)
+
+// jwtKey is a configured key. signKey is nil for keys that only verify tokens
+type jwtKey struct {
+	id        string
+	method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+// loadKey reads the material of a configured key
+func loadKey(cfg config.JWTKeyConfig) (*jwtKey, error) {
+	key := &jwtKey{id: cfg.ID}
+	var err error
+	switch cfg.Algorithm {
+	case jwt.SigningMethodHS256.Alg():
+		key.method = jwt.SigningMethodHS256
+		err = loadSecret(key, cfg)
+	case jwt.SigningMethodRS256.Alg():
+		key.method = jwt.SigningMethodRS256
+		err = loadRSAKey(key, cfg.File)
+	case SigningMethodEdDSA.Alg():
+		key.method = SigningMethodEdDSA
+		err = loadEd25519Key(key, cfg.File)
+	default:
+		return nil, fmt.Errorf("%w %q for key %s", ErrUnsupportedAlgorithm, cfg.Algorithm, cfg.ID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w for key %s", err, cfg.ID)
+	}
+	return key, nil
+}
+
+func loadSecret(key *jwtKey, cfg config.JWTKeyConfig) error {
+	secret := []byte(cfg.Secret)
+	if len(secret) == 0 && len(cfg.File) > 0 {
+		content, err := ioutil.ReadFile(cfg.File)
+		if err != nil {
+			return err
+		}
+		secret = bytes.TrimSpace(content)
+	}
+	if len(secret) < minSecretLength {
+		return ErrWeakSecret
+	}
+
+	key.signKey = secret
+	key.verifyKey = secret
+	return nil
+}
+
+func loadRSAKey(key *jwtKey, file string) error {
+	block, content, err := readPemFile(file)
+	if err != nil {
+		return err
+	}
+	if strings.Contains(block.Type, "PRIVATE KEY") {
+		privateKey, errParse := jwt.ParseRSAPrivateKeyFromPEM(content)
+		if errParse != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidKeyFile, errParse)
+		}
+		key.signKey = privateKey
+		key.verifyKey = &privateKey.PublicKey
+		return nil
+	}
+
+	publicKey, err := jwt.ParseRSAPublicKeyFromPEM(content)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidKeyFile, err)
+	}
+	key.verifyKey = publicKey
+	return nil
+}
+
+// loadEd25519Key accepts PKCS #8 private keys, PKIX public keys and the wallet PEM files, which hold the
+// hex encoded seed followed by the public key
+func loadEd25519Key(key *jwtKey, file string) error {
+	block, _, err := readPemFile(file)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case strings.HasPrefix(block.Type, walletPemPrefix):
+		decoded, errDecode := hex.DecodeString(string(block.Bytes))
+		if errDecode != nil || len(decoded) < ed25519.SeedSize {
+			return ErrInvalidKeyFile
+		}
+		privateKey := ed25519.NewKeyFromSeed(decoded[:ed25519.SeedSize])
+		publicKey := privateKey.Public().(ed25519.PublicKey)
+		if len(decoded) == ed25519.PrivateKeySize && !bytes.Equal(decoded[ed25519.SeedSize:], publicKey) {
+			return fmt.Errorf("%w: the public key does not match the seed", ErrInvalidKeyFile)
+		}
+		key.signKey = privateKey
+		key.verifyKey = publicKey
+	case block.Type == "PRIVATE KEY":
+		parsed, errParse := x509.ParsePKCS8PrivateKey(block.Bytes)
+		privateKey, ok := parsed.(ed25519.PrivateKey)
+		if errParse != nil || !ok {
+			return ErrInvalidKeyFile
+		}
+		key.signKey = privateKey
+		key.verifyKey = privateKey.Public().(ed25519.PublicKey)
+	case block.Type == "PUBLIC KEY":
+		parsed, errParse := x509.ParsePKIXPublicKey(block.Bytes)
+		publicKey, ok := parsed.(ed25519.PublicKey)
+		if errParse != nil || !ok {
+			return ErrInvalidKeyFile
+		}
+		key.verifyKey = publicKey
+	default:
+		return fmt.Errorf("%w: unexpected PEM block %q", ErrInvalidKeyFile, block.Type)
+	}
+	return nil
+}
+
+func readPemFile(file string) (*pem.Block, []byte, error) {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, nil, fmt.Errorf("%w: no PEM block in %s", ErrInvalidKeyFile, file)
+	}
+	return block, content, nil
+}
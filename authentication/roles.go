@@ -0,0 +1,83 @@
+package authentication
+
+import "sort"
+
+// Roles that can be assigned to a user. The diriginte role is assigned for one class
+const (
+	RolAdmin     = "admin"
+	RolDirector  = "director"
+	RolDiriginte = "diriginte"
+	RolProfesor  = "profesor"
+	RolEvaluator = "evaluator"
+	RolStudent   = "student"
+	RolParinte   = "parinte"
+)
+
+// Permissions checked by the API routes. Besides the permission of a route, the data a user can see is limited
+// to the classes they teach, grade or lead, unless they hold PermClassReadAll
+const (
+	PermClassRead       = "class:read"
+	PermClassReadAll    = "class:read:all"
+	PermClassWrite      = "class:write"
+	PermGradeRead       = "grade:read"
+	PermGradeWrite      = "grade:write"
+	PermExamRead        = "exam:read"
+	PermExamWrite       = "exam:write"
+	PermGradingManage   = "grading:manage"
+	PermUserWrite       = "user:write"
+	PermResultsOwn      = "results:own"
+	PermContestatieFile = "contestatie:file"
+)
+
+var rolePermissions = map[string][]string{
+	RolAdmin: {
+		PermClassRead, PermClassReadAll, PermClassWrite, PermGradeRead, PermGradeWrite, PermExamRead,
+		PermExamWrite, PermGradingManage, PermUserWrite,
+	},
+	RolDirector:  {PermClassRead, PermClassReadAll, PermGradeRead, PermExamRead},
+	RolDiriginte: {PermClassRead, PermGradeRead, PermExamRead},
+	RolProfesor:  {PermClassRead, PermGradeRead, PermGradeWrite, PermExamRead},
+	RolEvaluator: {PermClassRead, PermGradeRead, PermGradeWrite, PermExamRead},
+	RolStudent:   {PermResultsOwn, PermContestatieFile},
+	RolParinte:   {PermResultsOwn},
+}
+
+// IsValidRol returns true if the role is known
+func IsValidRol(rol string) bool {
+	_, found := rolePermissions[rol]
+	return found
+}
+
+// IsProfesorRol returns true if the role can be held by a profesor account. The student role comes only with
+// a student account
+func IsProfesorRol(rol string) bool {
+	return IsValidRol(rol) && rol != RolStudent
+}
+
+// HasPermission returns true if any of the roles grants the permission
+func HasPermission(roluri []string, permisiune string) bool {
+	for _, rol := range roluri {
+		for _, granted := range rolePermissions[rol] {
+			if granted == permisiune {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RoleNames returns the sorted names of the assigned roles, without repetitions
+func RoleNames(roluri []RolUtilizator) []string {
+	seen := make(map[string]struct{}, len(roluri))
+	names := make([]string, 0, len(roluri))
+	for _, rol := range roluri {
+		_, found := seen[rol.Rol]
+		if found {
+			continue
+		}
+		seen[rol.Rol] = struct{}{}
+		names = append(names, rol.Rol)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,29 @@
+package authentication
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHasPermission(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, HasPermission([]string{RolAdmin}, PermUserWrite))
+	assert.True(t, HasPermission([]string{RolStudent, RolProfesor}, PermGradeWrite))
+	assert.False(t, HasPermission([]string{RolDirector}, PermGradeWrite))
+	assert.False(t, HasPermission([]string{RolProfesor}, PermClassReadAll))
+	assert.False(t, HasPermission([]string{"portar"}, PermClassRead))
+	assert.False(t, HasPermission(nil, PermClassRead))
+}
+
+func TestRoleNames(t *testing.T) {
+	t.Parallel()
+
+	roluri := []RolUtilizator{
+		{Email: "a@test.ro", Rol: RolProfesor},
+		{Email: "a@test.ro", Rol: RolDiriginte, Clasa: "8A"},
+		{Email: "a@test.ro", Rol: RolDiriginte, Clasa: "8B"},
+	}
+	assert.Equal(t, []string{RolDiriginte, RolProfesor}, RoleNames(roluri))
+}
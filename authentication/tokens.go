@@ -0,0 +1,42 @@
+package authentication
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+const (
+	tokenBytes     = 32
+	sessionIDBytes = 16
+)
+
+// NewToken returns a random refresh or setup token and the hash under which it is stored
+func NewToken() (string, string, error) {
+	token, err := randomHex(tokenBytes)
+	if err != nil {
+		return "", "", err
+	}
+	return token, HashToken(token), nil
+}
+
+// HashToken returns the hash under which a refresh or setup token is stored, so a leaked database does not leak
+// usable tokens
+func HashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// NewID returns a random ID for a new session or invitation
+func NewID() (string, error) {
+	return randomHex(sessionIDBytes)
+}
+
+func randomHex(size int) (string, error) {
+	buff := make([]byte, size)
+	_, err := rand.Read(buff)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buff), nil
+}
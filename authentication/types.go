@@ -1,6 +1,8 @@
 package authentication
 
 import (
+	"time"
+
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
@@ -43,20 +45,114 @@ type Clasa struct {
 
 type Student struct {
 	User
-	Absent      bool   `json:"absent"`
 	Clasa       string `gorm:"foreignkey" json:"clasa"`
 	ExamStiinta string `gorm:"foreignkey" json:"exam_stiinta"`
 	ExamLimba   string `gorm:"foreignkey" json:"exam_limba"`
 }
 
+// Absenta records that a student missed an exam, why, and the admin that recorded it
+type Absenta struct {
+	Student   uint      `gorm:"primarykey;autoIncrement:false" json:"student_id"`
+	Exam      string    `gorm:"primarykey" json:"exam"`
+	Motiv     string    `json:"motiv"`
+	Admin     string    `json:"admin"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 type Calificativ struct {
-	Student   uint   `gorm:"primarykey" json:"student_id"`
+	Student   uint   `gorm:"primarykey;autoIncrement:false" json:"student_id"`
 	Profesor  uint   `json:"profesor_id"`
 	Exam      string `gorm:"primarykey" json:"exam"`
-	Exercitiu int    `gorm:"primarykey" json:"exercitiu"`
+	Exercitiu string `gorm:"primarykey" json:"exercitiu"`
+	Slot      uint8  `gorm:"primarykey;autoIncrement:false;default:1" json:"slot"`
 	Varianta  string `json:"varianta"`
 }
 
+// Comentariu is the feedback of a profesor on one exercise of a paper, or on the whole paper when Exercitiu
+// is empty
+type Comentariu struct {
+	Student   uint      `gorm:"primarykey;autoIncrement:false" json:"student_id"`
+	Exam      string    `gorm:"primarykey" json:"exam"`
+	Exercitiu string    `gorm:"primarykey" json:"exercitiu"`
+	Profesor  uint      `gorm:"primarykey;autoIncrement:false" json:"profesor_id"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Sesiune is a login of a profesor, or of a student when Student is set. Its access tokens carry the ID and its
+// refresh token is stored only as a hash, so revoking the session invalidates both
+type Sesiune struct {
+	ID          string     `gorm:"primarykey;size:64" json:"id"`
+	Email       string     `gorm:"index;size:255" json:"email"`
+	Student     uint       `gorm:"default:0" json:"student_id,omitempty"`
+	RefreshHash string     `gorm:"uniqueIndex;size:64" json:"-"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	RevokedAt   *time.Time `json:"revoked_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// Invitatie is a one-time grant to register a user with a role: a setup token for the first admin, identified
+// by the hash of the token, or an invitation sent by an admin, identified by the ID of the signed link
+type Invitatie struct {
+	ID        string     `gorm:"primarykey;size:64" json:"id"`
+	Email     string     `gorm:"size:255" json:"email,omitempty"`
+	Rol       string     `gorm:"size:32" json:"rol"`
+	Admin     string     `gorm:"size:255" json:"admin,omitempty"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// RolUtilizator assigns a role to a user. Clasa is set only for the diriginte role
+type RolUtilizator struct {
+	Email     string    `gorm:"primarykey;size:255" json:"email"`
+	Rol       string    `gorm:"primarykey;size:32" json:"rol"`
+	Clasa     string    `gorm:"primarykey;size:64" json:"clasa,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Evaluator assigns a profesor to a grading slot for the papers of a class on one subject of an exam
+type Evaluator struct {
+	Clasa    string `gorm:"primarykey" json:"clasa"`
+	Exam     string `gorm:"primarykey" json:"exam"`
+	Materie  string `gorm:"primarykey" json:"materie"`
+	Slot     uint8  `gorm:"primarykey;autoIncrement:false" json:"slot"`
+	Profesor uint   `json:"profesor_id"`
+}
+
+// Arbitraj is opened when the two correctors of a paper disagree by more than the threshold of the exam
+type Arbitraj struct {
+	Student   uint      `gorm:"primarykey;autoIncrement:false" json:"student_id"`
+	Exam      string    `gorm:"primarykey" json:"exam"`
+	Materie   string    `gorm:"primarykey" json:"materie"`
+	Profesor  uint      `json:"profesor_id"`
+	Nota1     float64   `json:"nota_corector1"`
+	Nota2     float64   `json:"nota_corector2"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Contestatie records the appeal of a student against the grade of one subject of an exam, together with the
+// grades before and after the re-evaluation
+type Contestatie struct {
+	Student      uint      `gorm:"primarykey;autoIncrement:false" json:"student_id"`
+	Exam         string    `gorm:"primarykey" json:"exam"`
+	Materie      string    `gorm:"primarykey" json:"materie"`
+	DepusaDe     string    `json:"depusa_de"`
+	Motiv        string    `json:"motiv"`
+	Profesor     uint      `json:"profesor_id"`
+	Status       string    `json:"status"`
+	NotaInitiala float64   `json:"nota_initiala"`
+	NotaNoua     *float64  `json:"nota_noua"`
+	NotaFinala   *float64  `json:"nota_finala"`
+	Aplicata     bool      `json:"aplicata"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
 func NewStudent(nume, prenume, clasa, email, password, examStiinta, examLimba string) *Student {
 	return &Student{
 		User: User{
@@ -67,7 +163,6 @@ func NewStudent(nume, prenume, clasa, email, password, examStiinta, examLimba st
 			Password: password,
 			Type:     "student",
 		},
-		Absent:      false,
 		Clasa:       clasa,
 		ExamStiinta: examStiinta,
 		ExamLimba:   examLimba,
@@ -76,19 +171,79 @@ func NewStudent(nume, prenume, clasa, email, password, examStiinta, examLimba st
 
 type Profesor struct {
 	User
-	IsAdmin bool   `json:"is_admin"`
 	Materie string `json:"materie"`
 }
 
 type Exam struct {
-	Nume string `gorm:"primarykey" json:"nume"`
+	Nume            string     `gorm:"primarykey" json:"nume"`
+	PuncteOficiu    float64    `json:"puncte_oficiu"`
+	Rotunjire       string     `gorm:"default:trunchiere" json:"rotunjire"`
+	PragArbitraj    float64    `gorm:"default:1" json:"prag_arbitraj"`
+	PragContestatie float64    `gorm:"default:0.5" json:"prag_contestatie"`
+	Status          string     `gorm:"default:ciorna" json:"status"`
+	InceputNotare   *time.Time `json:"inceput_notare"`
+	SfarsitNotare   *time.Time `json:"sfarsit_notare"`
+}
+
+// TranzitieExam records who moved an exam from one state of its lifecycle to another
+type TranzitieExam struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	Exam      string    `json:"exam"`
+	DinStatus string    `json:"din_status"`
+	InStatus  string    `json:"in_status"`
+	Admin     string    `json:"admin"`
+	Motiv     string    `json:"motiv"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 type Exercitiu struct {
-	Numar    string `gorm:"primarykey" json:"numar"`
-	Variante string `json:"variante"`
-	Materie  string `json:"materie"`
-	Exam     string `gorm:"primarykey" json:"exam"`
+	Numar        string   `gorm:"primarykey" json:"numar"`
+	Materie      string   `json:"materie"`
+	Exam         string   `gorm:"primarykey" json:"exam"`
+	PunctajMaxim *float64 `json:"punctaj_maxim"`
+	Pondere      float64  `gorm:"default:1" json:"pondere"`
+	Sectiune     string   `json:"sectiune"`
+}
+
+type Sectiune struct {
+	Exam    string `gorm:"primarykey" json:"exam"`
+	Cod     string `gorm:"primarykey" json:"cod"`
+	Titlu   string `json:"titlu"`
+	Parinte string `json:"parinte"`
+	Ordine  int    `json:"ordine"`
+}
+
+// CompetentaExercitiu tags an exercise with a specific competency of the curriculum it assesses
+type CompetentaExercitiu struct {
+	Exam       string `gorm:"primarykey" json:"exam"`
+	Exercitiu  string `gorm:"primarykey" json:"exercitiu"`
+	Competenta string `gorm:"primarykey" json:"competenta"`
+}
+
+// Competenta describes a specific competency of the national curriculum of a subject
+type Competenta struct {
+	Materie   string `gorm:"primarykey" json:"materie"`
+	Cod       string `gorm:"primarykey" json:"cod"`
+	Descriere string `json:"descriere"`
+}
+
+type VariantaExercitiu struct {
+	Exam           string  `gorm:"primarykey" json:"exam"`
+	Exercitiu      string  `gorm:"primarykey" json:"exercitiu"`
+	Nume           string  `gorm:"primarykey" json:"nume"`
+	Puncte         float64 `json:"puncte"`
+	Ordine         int     `json:"ordine"`
+	Rubrica        string  `json:"rubrica"`
+	ExempluRaspuns string  `json:"exemplu_raspuns"`
+}
+
+type Scor struct {
+	Student      uint      `gorm:"primarykey;autoIncrement:false" json:"student_id"`
+	Exam         string    `gorm:"primarykey" json:"exam"`
+	Materie      string    `gorm:"primarykey" json:"materie"`
+	Punctaj      float64   `json:"punctaj"`
+	PunctajMaxim float64   `json:"punctaj_maxim"`
+	UpdatedAt    time.Time `json:"updated_at"`
 }
 
 func (user *User) HashPassword(password string) error {
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dragos-rebegea/evaluare-tool/core"
+	"github.com/dragos-rebegea/evaluare-tool/factory"
+	logger "github.com/multiversx/mx-chain-logger-go"
+	"github.com/urfave/cli"
+)
+
+var (
+	renameExercise = cli.StringSliceFlag{
+		Name:  "rename",
+		Usage: "Renames an exercise in the copy, as old=new. Can be repeated",
+	}
+	dropExercise = cli.StringSliceFlag{
+		Name:  "drop",
+		Usage: "Leaves an exercise out of the copy. Can be repeated",
+	}
+	moveStudents = cli.BoolFlag{
+		Name:  "move-students",
+		Usage: "Moves the students assigned to the source exam to the copy",
+	}
+)
+
+func getExamCommand() cli.Command {
+	return cli.Command{
+		Name:  "exam",
+		Usage: "Manages the exams stored in the database",
+		Subcommands: []cli.Command{
+			{
+				Name:      "clone",
+				Usage:     "Copies an exam, with its exercises, points and rubrics, under a new name",
+				ArgsUsage: "source destination",
+				Flags:     []cli.Flag{renameExercise, dropExercise, moveStudents},
+				Action:    cloneExam,
+			},
+		},
+	}
+}
+
+func createDatabaseHandler(ctx *cli.Context) (core.DatabaseHandler, error) {
+	flagsConfig := getFlagsConfig(ctx)
+	err := logger.SetLogLevel(flagsConfig.LogLevel)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := loadConfig(flagsConfig.ConfigurationFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return factory.CreateDatabaseHandler(cfg.Database)
+}
+
+func cloneExam(ctx *cli.Context) error {
+	if ctx.NArg() != 2 {
+		return errors.New("expected the source and the destination exam")
+	}
+	request := &core.CloneExamRequest{
+		Exam:       ctx.Args().Get(0),
+		Nume:       ctx.Args().Get(1),
+		Redenumiri: make(map[string]string),
+		Excluse:    ctx.StringSlice(dropExercise.Name),
+	}
+	for _, rename := range ctx.StringSlice(renameExercise.Name) {
+		parts := strings.SplitN(rename, "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("invalid rename %q, expected old=new", rename)
+		}
+		request.Redenumiri[parts[0]] = parts[1]
+	}
+
+	dbHandler, err := createDatabaseHandler(ctx)
+	if err != nil {
+		return err
+	}
+
+	exam, err := dbHandler.CloneExam(request)
+	if err != nil {
+		return err
+	}
+	log.Info("exam cloned", "source", request.Exam, "exam", exam.Nume, "exercises", len(exam.Exercitii))
+
+	if !ctx.Bool(moveStudents.Name) {
+		return nil
+	}
+	result, err := dbHandler.MoveExamStudents(&core.MoveStudentsRequest{Exam: request.Exam, NouExam: exam.Nume})
+	if err != nil {
+		return err
+	}
+	log.Info("students moved", "exam", exam.Nume, "assignments", result.Mutati)
+	return nil
+}
@@ -27,6 +27,11 @@ const (
 	logMaxSizeInMB      = 1024
 	issuer              = "ElrondNetwork" //TODO: add issuer & digits into config.toml
 	digits              = 6
+
+	dbHostEnvVariable     = "EVALUARE_DB_HOST"
+	dbUserEnvVariable     = "EVALUARE_DB_USER"
+	dbPasswordEnvVariable = "EVALUARE_DB_PASSWORD"
+	jwtSecretEnvVariable  = "EVALUARE_JWT_SECRET"
 )
 
 var log = logger.GetOrCreate("main")
@@ -54,6 +59,12 @@ func main() {
 		},
 	}
 
+	app.Commands = []cli.Command{
+		getMigrateCommand(),
+		getExamCommand(),
+		getSetupTokenCommand(),
+	}
+
 	app.Action = func(c *cli.Context) error {
 		return startService(c, app.Version)
 	}
@@ -133,9 +144,40 @@ func loadConfig(filepath string) (config.Config, error) {
 		return config.Config{}, err
 	}
 
+	applyDatabaseEnvOverrides(&cfg.Database)
+	applyJWTEnvOverrides(&cfg.JWT)
+
 	return cfg, nil
 }
 
+// applyDatabaseEnvOverrides replaces the database settings with the values from the environment, if set,
+// so credentials do not need to be written in config.toml
+func applyDatabaseEnvOverrides(cfg *config.DatabaseConfig) {
+	if host, ok := os.LookupEnv(dbHostEnvVariable); ok {
+		cfg.Host = host
+	}
+	if user, ok := os.LookupEnv(dbUserEnvVariable); ok {
+		cfg.User = user
+	}
+	if password, ok := os.LookupEnv(dbPasswordEnvVariable); ok {
+		cfg.Password = password
+	}
+}
+
+// applyJWTEnvOverrides replaces the secret of the signing key with the value from the environment, if set,
+// so the secret does not need to be written in config.toml
+func applyJWTEnvOverrides(cfg *config.JWTConfig) {
+	secret, ok := os.LookupEnv(jwtSecretEnvVariable)
+	if !ok {
+		return
+	}
+	for i := range cfg.Keys {
+		if cfg.Keys[i].ID == cfg.SigningKeyID {
+			cfg.Keys[i].Secret = secret
+		}
+	}
+}
+
 // LoadApiConfig returns a ApiRoutesConfig by reading the config file provided
 func loadApiConfig(filepath string) (config.ApiRoutesConfig, error) {
 	cfg := config.ApiRoutesConfig{}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/dragos-rebegea/evaluare-tool/core"
+	"github.com/dragos-rebegea/evaluare-tool/factory"
+	logger "github.com/multiversx/mx-chain-logger-go"
+	"github.com/urfave/cli"
+)
+
+func getMigrateCommand() cli.Command {
+	return cli.Command{
+		Name:  "migrate",
+		Usage: "Manages the versioned database schema migrations",
+		Subcommands: []cli.Command{
+			{
+				Name:   "status",
+				Usage:  "Lists all migrations and whether they have been applied",
+				Action: migrateStatus,
+			},
+			{
+				Name:   "up",
+				Usage:  "Applies all pending migrations",
+				Action: migrateUp,
+			},
+			{
+				Name:   "down",
+				Usage:  "Rolls back the last applied migration",
+				Action: migrateDown,
+			},
+			{
+				Name:      "to",
+				Usage:     "Applies or rolls back migrations until the schema reaches the given version",
+				ArgsUsage: "version",
+				Action:    migrateTo,
+			},
+		},
+	}
+}
+
+func createSchemaMigrator(ctx *cli.Context) (core.SchemaMigrator, error) {
+	flagsConfig := getFlagsConfig(ctx)
+	err := logger.SetLogLevel(flagsConfig.LogLevel)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := loadConfig(flagsConfig.ConfigurationFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return factory.CreateSchemaMigrator(cfg.Database)
+}
+
+func migrateStatus(ctx *cli.Context) error {
+	migrator, err := createSchemaMigrator(ctx)
+	if err != nil {
+		return err
+	}
+
+	statuses, err := migrator.Status()
+	if err != nil {
+		return err
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "VERSION\tNAME\tAPPLIED AT")
+	for _, status := range statuses {
+		appliedAt := "pending"
+		if status.Applied {
+			appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		_, _ = fmt.Fprintf(writer, "%d\t%s\t%s\n", status.Version, status.Name, appliedAt)
+	}
+
+	return writer.Flush()
+}
+
+func migrateUp(ctx *cli.Context) error {
+	migrator, err := createSchemaMigrator(ctx)
+	if err != nil {
+		return err
+	}
+
+	return logMigrationResult(migrator, migrator.Up())
+}
+
+func migrateDown(ctx *cli.Context) error {
+	migrator, err := createSchemaMigrator(ctx)
+	if err != nil {
+		return err
+	}
+
+	return logMigrationResult(migrator, migrator.Down())
+}
+
+func migrateTo(ctx *cli.Context) error {
+	version, err := strconv.ParseUint(ctx.Args().First(), 10, 32)
+	if err != nil {
+		return fmt.Errorf("%w while parsing the target schema version", err)
+	}
+
+	migrator, err := createSchemaMigrator(ctx)
+	if err != nil {
+		return err
+	}
+
+	return logMigrationResult(migrator, migrator.To(uint32(version)))
+}
+
+func logMigrationResult(migrator core.SchemaMigrator, migrationErr error) error {
+	if migrationErr != nil {
+		return migrationErr
+	}
+
+	version, err := migrator.CurrentVersion()
+	if err != nil {
+		return err
+	}
+
+	log.Info("database schema migrated", "version", version, "latest", migrator.LatestVersion())
+	return nil
+}
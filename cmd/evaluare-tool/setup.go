@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dragos-rebegea/evaluare-tool/factory"
+	logger "github.com/multiversx/mx-chain-logger-go"
+	"github.com/urfave/cli"
+)
+
+func getSetupTokenCommand() cli.Command {
+	return cli.Command{
+		Name:   "setup-token",
+		Usage:  "Creates a one-time token allowing the holder to register the first admin through /auth/register",
+		Action: createSetupToken,
+	}
+}
+
+func createSetupToken(ctx *cli.Context) error {
+	flagsConfig := getFlagsConfig(ctx)
+	err := logger.SetLogLevel(flagsConfig.LogLevel)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig(flagsConfig.ConfigurationFile)
+	if err != nil {
+		return err
+	}
+
+	dbHandler, err := factory.CreateDatabaseHandler(cfg.Database)
+	if err != nil {
+		return err
+	}
+
+	token, expiresAt, err := factory.CreateSetupToken(dbHandler, cfg.Registration)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("setup token: %s\nexpires at: %s\n", token, expiresAt.Format(time.RFC3339))
+	return nil
+}
@@ -9,10 +9,13 @@ type Configs struct {
 
 // Config general configuration struct
 type Config struct {
-	Guardian  GuardianConfig
-	Proxy     ProxyConfig
-	Logs      LogsConfig
-	Antiflood AntifloodConfig
+	Guardian     GuardianConfig
+	Proxy        ProxyConfig
+	Logs         LogsConfig
+	Antiflood    AntifloodConfig
+	Database     DatabaseConfig
+	JWT          JWTConfig
+	Registration RegistrationConfig
 }
 
 // ContextFlagsConfig the configuration for flags
@@ -83,3 +86,44 @@ type ProxyConfig struct {
 type LogsConfig struct {
 	LogFileLifeSpanInSec int
 }
+
+// DatabaseConfig will hold settings related to the storage backend
+type DatabaseConfig struct {
+	Driver                string
+	Host                  string
+	Port                  uint32
+	User                  string
+	Password              string
+	Name                  string
+	TLSMode               string
+	SQLiteFile            string
+	MaxOpenConnections    int
+	MaxIdleConnections    int
+	ConnMaxLifetimeInSec  int
+	ConnectTimeoutInSec   int
+	ReadWriteTimeoutInSec int
+	AutoMigrate           bool
+}
+
+// JWTConfig will hold settings related to the signing and the verification of the access tokens
+type JWTConfig struct {
+	SigningKeyID              string
+	AccessTokenLifetimeInSec  int
+	RefreshTokenLifetimeInSec int
+	Keys                      []JWTKeyConfig
+}
+
+// JWTKeyConfig holds a key able to verify access tokens. The key named by SigningKeyID also signs the new tokens
+type JWTKeyConfig struct {
+	ID        string
+	Algorithm string
+	Secret    string
+	File      string
+}
+
+// RegistrationConfig will hold settings related to the registration of admins
+type RegistrationConfig struct {
+	SetupTokenLifetimeInSec int
+	InvitationLifetimeInSec int
+	InvitationURL           string
+}
@@ -2,3 +2,67 @@ package core
 
 // WebServerOffString represents the constant used to switch off the web server
 const WebServerOffString = "off"
+
+const (
+	// MySQLDriver is the name of the MySQL storage backend
+	MySQLDriver = "mysql"
+
+	// SQLiteDriver is the name of the embedded SQLite storage backend
+	SQLiteDriver = "sqlite"
+)
+
+const (
+	// SlotCorector1 is the grading slot of the first corrector of a paper
+	SlotCorector1 uint8 = 1
+
+	// SlotCorector2 is the grading slot of the second corrector of a paper
+	SlotCorector2 uint8 = 2
+
+	// SlotArbitru is the grading slot of the arbiter, whose marks are final
+	SlotArbitru uint8 = 3
+
+	// SlotContestatie is the grading slot of the profesor that re-evaluates a contested paper
+	SlotContestatie uint8 = 4
+)
+
+const (
+	// ArbitrajInAsteptare marks an arbitration that has no arbiter yet
+	ArbitrajInAsteptare = "in_asteptare"
+
+	// ArbitrajAlocat marks an arbitration assigned to an arbiter that has not finished grading
+	ArbitrajAlocat = "alocat"
+
+	// ArbitrajRezolvat marks an arbitration whose arbiter graded every exercise
+	ArbitrajRezolvat = "rezolvat"
+)
+
+const (
+	// ContestatieDepusa marks an appeal that has no profesor assigned yet
+	ContestatieDepusa = "depusa"
+
+	// ContestatieAlocata marks an appeal assigned to a profesor that has not finished the re-evaluation
+	ContestatieAlocata = "alocata"
+
+	// ContestatieReevaluata marks an appeal whose profesor graded every exercise again
+	ContestatieReevaluata = "reevaluata"
+
+	// ContestatieRezolvata marks an appeal whose final grade has been decided
+	ContestatieRezolvata = "rezolvata"
+)
+
+const (
+	// ExamCiorna marks an exam that is still being prepared
+	ExamCiorna = "ciorna"
+
+	// ExamPublicat marks an exam whose structure is final, before grading starts
+	ExamPublicat = "publicat"
+
+	// ExamNotareDeschisa marks an exam whose papers can be graded, within the optional grading window
+	ExamNotareDeschisa = "notare_deschisa"
+
+	// ExamNotareInchisa marks an exam whose marks can only be changed by the re-evaluation of an appeal
+	ExamNotareInchisa = "notare_inchisa"
+
+	// ExamRezultatePublicate marks an exam whose results were released
+	ExamRezultatePublicate = "rezultate_publicate"
+)
@@ -2,39 +2,116 @@ package core
 
 import (
 	"errors"
+	"fmt"
 	"math/rand"
-	"strings"
 	"sync"
+	"time"
 
 	"github.com/dragos-rebegea/evaluare-tool/authentication"
+	"github.com/dragos-rebegea/evaluare-tool/migrations"
+	"github.com/dragos-rebegea/evaluare-tool/scoring"
 	logger "github.com/multiversx/mx-chain-logger-go"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-// DatabaseHandler is the interface that wraps the basic database operations
-
 var dbLogger = logger.GetOrCreate("dbHandler")
 
-type DatabaseHandler struct {
+// ArgsDatabaseHandler is the DTO used to create a new instance of databaseHandler
+type ArgsDatabaseHandler struct {
+	Driver             string
+	ConnectionString   string
+	MaxOpenConnections int
+	MaxIdleConnections int
+	ConnMaxLifetime    time.Duration
+	AutoMigrate        bool
+}
+
+type databaseHandler struct {
 	mutex    sync.RWMutex
 	database *gorm.DB
 }
 
-// NewDatabaseHandler returns a new DatabaseHandler instance
-func NewDatabaseHandler(connectionstring string) (*DatabaseHandler, error) {
-	db, err := authentication.Connect(connectionstring)
+// NewDatabaseHandler returns a new databaseHandler instance backed by the configured driver.
+// It refuses to work on a database whose schema does not match the latest migration
+func NewDatabaseHandler(args ArgsDatabaseHandler) (*databaseHandler, error) {
+	db, err := OpenDatabase(args)
+	if err != nil {
+		return nil, err
+	}
+
+	migrator, err := migrations.NewMigrator(db, migrations.All())
+	if err != nil {
+		return nil, err
+	}
+	if args.AutoMigrate {
+		err = migrator.Up()
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = migrator.CheckSchema()
+	if err != nil {
+		return nil, err
+	}
+
+	return &databaseHandler{database: db}, nil
+}
+
+// OpenDatabase connects to the configured database and checks that it is reachable
+func OpenDatabase(args ArgsDatabaseHandler) (*gorm.DB, error) {
+	dialector, err := createDialector(args)
 	if err != nil {
 		return nil, err
 	}
-	err = authentication.Migrate(db)
+
+	db, err := authentication.Connect(dialector)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseUnreachable, err)
+	}
+
+	sqlDB, err := db.DB()
 	if err != nil {
 		return nil, err
 	}
-	return &DatabaseHandler{database: db}, nil
+	sqlDB.SetMaxOpenConns(args.MaxOpenConnections)
+	sqlDB.SetMaxIdleConns(args.MaxIdleConnections)
+	sqlDB.SetConnMaxLifetime(args.ConnMaxLifetime)
+	if args.Driver == SQLiteDriver {
+		// sqlite allows a single writer, and every connection to :memory: opens a new database,
+		// so the one connection must never be recycled
+		sqlDB.SetMaxOpenConns(1)
+		sqlDB.SetMaxIdleConns(1)
+		sqlDB.SetConnMaxLifetime(0)
+	}
+
+	err = sqlDB.Ping()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseUnreachable, err)
+	}
+
+	return db, nil
+}
+
+func createDialector(args ArgsDatabaseHandler) (gorm.Dialector, error) {
+	if len(args.ConnectionString) == 0 {
+		return nil, ErrEmptyConnectionString
+	}
+
+	switch args.Driver {
+	case MySQLDriver:
+		return mysql.Open(args.ConnectionString), nil
+	case SQLiteDriver:
+		return sqlite.Open(args.ConnectionString), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownDatabaseDriver, args.Driver)
+	}
 }
 
 // GetStudentByID returns a student by id
-func (db *DatabaseHandler) GetStudentByID(id uint) (*authentication.Student, error) {
+func (db *databaseHandler) GetStudentByID(id uint) (*authentication.Student, error) {
 	var student authentication.Student
 	record := db.database.Where("id = ?", id).First(&student)
 	if record.Error != nil {
@@ -43,10 +120,10 @@ func (db *DatabaseHandler) GetStudentByID(id uint) (*authentication.Student, err
 	return &student, nil
 }
 
-// GetExamByName returns a student by name
-func (db *DatabaseHandler) GetExamByName(name string) (*authentication.Exam, error) {
+// GetExamByName returns an exam by name
+func (db *databaseHandler) GetExamByName(name string) (*authentication.Exam, error) {
 	var exam authentication.Exam
-	record := db.database.Where("name = ?", name).First(&exam)
+	record := db.database.Where("nume = ?", name).First(&exam)
 	if record.Error != nil {
 		return nil, record.Error
 	}
@@ -54,7 +131,7 @@ func (db *DatabaseHandler) GetExamByName(name string) (*authentication.Exam, err
 }
 
 // GetExercitiuByName returns a student by name
-func (db *DatabaseHandler) GetExercitiuStiintaByExamAndNumber(exam string, number string) (*authentication.Exercitiu, error) {
+func (db *databaseHandler) GetExercitiuStiintaByExamAndNumber(exam string, number string) (*authentication.Exercitiu, error) {
 	var exercitiu authentication.Exercitiu
 	record := db.database.Where("exam = ? AND numar = ?", exam, number).First(&exercitiu)
 	if record.Error != nil {
@@ -64,7 +141,7 @@ func (db *DatabaseHandler) GetExercitiuStiintaByExamAndNumber(exam string, numbe
 }
 
 // GetExercitiuByName returns a student by name
-func (db *DatabaseHandler) GetExercitiuLimbaByExamAndNumber(exam string, number string) (*authentication.Exercitiu, error) {
+func (db *databaseHandler) GetExercitiuLimbaByExamAndNumber(exam string, number string) (*authentication.Exercitiu, error) {
 	var exercitiu authentication.Exercitiu
 	record := db.database.Where("exam = ? AND numar = ?", exam, number).First(&exercitiu)
 	if record.Error != nil {
@@ -74,7 +151,7 @@ func (db *DatabaseHandler) GetExercitiuLimbaByExamAndNumber(exam string, number
 }
 
 // GetProfesorByEmail returns a profesor by email
-func (db *DatabaseHandler) GetProfesorByEmail(email string) (*authentication.Profesor, error) {
+func (db *databaseHandler) GetProfesorByEmail(email string) (*authentication.Profesor, error) {
 	var profesor authentication.Profesor
 	record := db.database.Where("email = ?", email).First(&profesor)
 	if record.Error != nil {
@@ -83,22 +160,42 @@ func (db *DatabaseHandler) GetProfesorByEmail(email string) (*authentication.Pro
 	return &profesor, nil
 }
 
-// GetStudentsByClass returns all the users from a class
-func (db *DatabaseHandler) GetStudentsByClass(clasa string) ([]authentication.Student, error) {
+// GetStudentsByClass returns all the users from a class, marking the ones absent from the provided exam
+func (db *databaseHandler) GetStudentsByClass(clasa string, exam string) ([]*StudentStatus, error) {
 	var students []authentication.Student
 	record := db.database.
 		Table("students").
 		Where("clasa = ? AND deleted_at IS NULL", clasa).
-		Select("id,nume,prenume,clasa,absent").
+		Select("id,nume,prenume,clasa,exam_stiinta,exam_limba").
 		Scan(&students)
 	if record.Error != nil {
 		return nil, record.Error
 	}
-	return students, nil
+
+	studentIds := make([]uint, 0, len(students))
+	for _, student := range students {
+		studentIds = append(studentIds, student.ID)
+	}
+	absente, err := loadAbsente(db.database, studentIds)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]*StudentStatus, 0, len(students))
+	for _, student := range students {
+		status := &StudentStatus{Student: student}
+		absenta, found := absente[absentaKey{student: student.ID, exam: exam}]
+		if found {
+			status.Absent = true
+			status.Motiv = absenta.Motiv
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
 }
 
-// GetAllClasses returns all the users from a class
-func (db *DatabaseHandler) GetAllClasses(profEmail string) ([]string, error) {
+// GetAllClasses returns the classes the profesor teaches or leads. Admins and directors get every class
+func (db *databaseHandler) GetAllClasses(profEmail string) ([]string, error) {
 	var classes []authentication.Clasa
 	record := db.database.Table("clasas").Find(&classes)
 	if record.Error != nil {
@@ -109,33 +206,44 @@ func (db *DatabaseHandler) GetAllClasses(profEmail string) ([]string, error) {
 	if err != nil {
 		return nil, err
 	}
+	all, diriginte, err := db.classScope(profEmail)
+	if err != nil {
+		return nil, err
+	}
 	var classList []string
 	for _, class := range classes {
-		err = db.checkProfesor(profesor, &class)
-		if err != nil {
-			continue
+		if !all && !diriginte[class.Nume] {
+			err = db.checkProfesor(profesor, &class)
+			if err != nil {
+				continue
+			}
 		}
 		classList = append(classList, class.Nume)
 	}
 	return classList, nil
 }
 
-// SetAbsent sets a student as absent
-func (db *DatabaseHandler) SetAbsent(status *AbsentStatus) error {
+// CreateProfesor creates a new profesor with the provided roles, or with the profesor role if none is provided
+func (db *databaseHandler) CreateProfesor(profesor *authentication.Profesor, roluri ...string) error {
 	db.mutex.Lock()
 	defer db.mutex.Unlock()
 
-	record := db.database.Table("students").Where("id = ?", status.Id).Update("absent", status.Absent)
-	if record.Error != nil {
-		return record.Error
-	}
-	return nil
+	return db.database.Transaction(func(tx *gorm.DB) error {
+		return createProfesor(tx, profesor, roluri)
+	})
 }
 
-// CreateProfesor creates a new profesor
-func (db *DatabaseHandler) CreateProfesor(profesor *authentication.Profesor) error {
-	db.mutex.Lock()
-	defer db.mutex.Unlock()
+// createProfesor stores a profesor and its roles inside the provided transaction. The generated password is
+// left in clear in the profesor, so it can be handed to the user
+func createProfesor(tx *gorm.DB, profesor *authentication.Profesor, roluri []string) error {
+	if len(roluri) == 0 {
+		roluri = []string{authentication.RolProfesor}
+	}
+	for _, rol := range roluri {
+		if !authentication.IsProfesorRol(rol) || rol == authentication.RolDiriginte {
+			return fmt.Errorf("%w: %s", ErrInvalidRol, rol)
+		}
+	}
 
 	profesor.Type = "profesor"
 	if profesor.Password == "" {
@@ -145,85 +253,152 @@ func (db *DatabaseHandler) CreateProfesor(profesor *authentication.Profesor) err
 	if err := profesor.HashPassword(password); err != nil {
 		return errors.New("error hashing password")
 	}
-	record := db.database.Create(profesor)
+	record := tx.Create(profesor)
 	if record.Error != nil {
 		return record.Error
 	}
+	for _, rol := range roluri {
+		record = tx.Create(&authentication.RolUtilizator{Email: profesor.Email, Rol: rol})
+		if record.Error != nil {
+			return record.Error
+		}
+	}
 
 	profesor.Password = password
 	return nil
 }
 
-// CreateClass creates a new class
-func (db *DatabaseHandler) CreateClass(class *Class) ([]*authentication.Student, error) {
-	db.mutex.Lock()
-	defer db.mutex.Unlock()
-
-	clasa := authentication.Clasa{
-		Nume:        class.Nume,
-		ProfMate:    class.ProfMate,
-		ProfBio:     class.ProfBio,
-		ProfFizica:  class.ProfFizica,
-		ProfRomana:  class.ProfRomana,
-		ProfEngleza: class.ProfEngleza,
-	}
-	record := db.database.Create(&clasa)
-	if record.Error != nil {
-		dbLogger.Error(record.Error.Error())
+// CreateClass creates a new class and its students in a single transaction. Students that cannot be
+// stored are reported as rejected; if the class asks for an all-or-nothing import, any rejection rolls
+// back the whole class
+func (db *databaseHandler) CreateClass(class *Class) (*CreateClassResult, error) {
+	result := &CreateClassResult{
+		Clasa:    class.Nume,
+		Created:  make([]*CreatedStudent, 0, len(class.Elevi)),
+		Rejected: make([]*RejectedStudent, 0),
 	}
 
-	students := make([]*authentication.Student, 0)
+	// hashing is slow, so it is done before taking the lock and opening the transaction
+	students := make([]*authentication.Student, 0, len(class.Elevi))
+	passwords := make([]string, 0, len(class.Elevi))
 	for _, student := range class.Elevi {
+		if len(student.Nume) == 0 || len(student.Prenume) == 0 {
+			result.Rejected = append(result.Rejected, newRejectedStudent(student, "missing nume or prenume"))
+			continue
+		}
+
 		// generate random password
 		password := GenerateRandomString(10)
 		studentDb := authentication.NewStudent(student.Nume, student.Prenume, class.Nume, student.Email, password, student.ExamStiinta, student.ExamLimba)
 		if err := studentDb.HashPassword(password); err != nil {
-			return nil, errors.New("error hashing password")
-		}
-		err := db.CreateStudent(studentDb)
-		if err != nil {
-			dbLogger.Error(err.Error())
+			result.Rejected = append(result.Rejected, newRejectedStudent(student, "error hashing password"))
 			continue
 		}
-		studentDb.Password = password
 		students = append(students, studentDb)
+		passwords = append(passwords, password)
 	}
-	return students, nil
-}
 
-// CreateStudent creates a new student
-func (db *DatabaseHandler) CreateStudent(student *authentication.Student) error {
-	record := db.database.Create(student)
-	if record.Error != nil {
-		return record.Error
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	err := db.database.Transaction(func(tx *gorm.DB) error {
+		clasa := authentication.Clasa{
+			Nume:        class.Nume,
+			ProfMate:    class.ProfMate,
+			ProfBio:     class.ProfBio,
+			ProfFizica:  class.ProfFizica,
+			ProfRomana:  class.ProfRomana,
+			ProfEngleza: class.ProfEngleza,
+		}
+		record := tx.Create(&clasa)
+		if record.Error != nil {
+			return record.Error
+		}
+
+		for idx, studentDb := range students {
+			rejected, err := createStudentInTransaction(tx, studentDb)
+			if err != nil {
+				return err
+			}
+			if rejected != nil {
+				result.Rejected = append(result.Rejected, &RejectedStudent{
+					Nume:    studentDb.Nume,
+					Prenume: studentDb.Prenume,
+					Email:   studentDb.Email,
+					Reason:  rejected.Error(),
+				})
+				continue
+			}
+
+			result.Created = append(result.Created, &CreatedStudent{
+				ID:       studentDb.ID,
+				Email:    studentDb.Email,
+				Username: studentDb.Username,
+				Password: passwords[idx],
+			})
+		}
+
+		if class.AllOrNothing && len(result.Rejected) > 0 {
+			return errClassImportRolledBack
+		}
+		return nil
+	})
+	if errors.Is(err, errClassImportRolledBack) {
+		result.Created = make([]*CreatedStudent, 0)
+		result.RolledBack = true
+		return result, nil
 	}
-	return nil
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
 }
 
-// IsAdmin returns true if the user is an admin
-func (db *DatabaseHandler) IsAdmin(email string) (bool, error) {
-	user, err := db.GetProfesorByEmail(email)
+// createStudentInTransaction stores a student behind a savepoint. It returns the reason the student was rejected,
+// or an error if the transaction itself can no longer be used
+func createStudentInTransaction(tx *gorm.DB, student *authentication.Student) (rejected error, err error) {
+	savePoint := "student"
+	err = tx.SavePoint(savePoint).Error
 	if err != nil {
-		return false, err
+		return nil, err
 	}
-	return user.IsAdmin, nil
-}
 
-// IsProfesor returns true if the user is a profesor
-func (db *DatabaseHandler) IsProfesor(email string) (bool, error) {
-	user, err := db.GetProfesorByEmail(email)
+	rejected = tx.Create(student).Error
+	if rejected == nil {
+		return nil, nil
+	}
+	err = tx.RollbackTo(savePoint).Error
 	if err != nil {
-		return false, err
+		return nil, fmt.Errorf("%w while rolling back the student rejected with: %v", err, rejected)
+	}
+	return rejected, nil
+}
+
+func newRejectedStudent(student ClassStudent, reason string) *RejectedStudent {
+	return &RejectedStudent{
+		Nume:    student.Nume,
+		Prenume: student.Prenume,
+		Email:   student.Email,
+		Reason:  reason,
+	}
+}
+
+// CreateStudent creates a new student
+func (db *databaseHandler) CreateStudent(student *authentication.Student) error {
+	record := db.database.Create(student)
+	if record.Error != nil {
+		return record.Error
 	}
-	return user.Type == "profesor", nil
+	return nil
 }
 
 // IsInterfaceNil returns true if there is no value under the interface
-func (db *DatabaseHandler) IsInterfaceNil() bool {
+func (db *databaseHandler) IsInterfaceNil() bool {
 	return db == nil
 }
 
-func (db *DatabaseHandler) DeleteStudent(u *uint) error {
+func (db *databaseHandler) DeleteStudent(u *uint) error {
 	db.mutex.Lock()
 	defer db.mutex.Unlock()
 
@@ -234,133 +409,326 @@ func (db *DatabaseHandler) DeleteStudent(u *uint) error {
 	return nil
 }
 
-func (db *DatabaseHandler) CreateExam(a *Exam) error {
+// CreateExam creates a new exam or updates the exercises of an existing one, together with the points of
+// their variants. The tree of sections is replaced when provided. The stored scores of the exam are
+// recomputed afterwards. An exam can only be updated while it is a draft
+func (db *databaseHandler) CreateExam(a *Exam) error {
+	err := checkExam(a)
+	if err != nil {
+		return err
+	}
+
 	db.mutex.Lock()
 	defer db.mutex.Unlock()
 
-	exam := authentication.Exam{Nume: a.Nume}
-	record := db.database.Create(&exam)
+	return db.database.Transaction(func(tx *gorm.DB) error {
+		err := checkExamUpdate(tx, a)
+		if err != nil {
+			return err
+		}
+		return saveExam(tx, a)
+	})
+}
+
+// checkExamUpdate checks that an existing exam is still a draft, and that the grading window stays valid once
+// the bounds missing from the update are taken from the stored exam
+func checkExamUpdate(tx *gorm.DB, a *Exam) error {
+	var existing authentication.Exam
+	record := tx.Where("nume = ?", a.Nume).Limit(1).Find(&existing)
 	if record.Error != nil {
-		dbLogger.Error(record.Error.Error())
+		return record.Error
+	}
+	if record.RowsAffected == 0 {
+		return nil
+	}
+	if existing.Status != ExamCiorna {
+		return ErrExamNotDraft
 	}
 
+	inceput, sfarsit := existing.InceputNotare, existing.SfarsitNotare
+	if a.InceputNotare != nil {
+		inceput = a.InceputNotare
+	}
+	if a.SfarsitNotare != nil {
+		sfarsit = a.SfarsitNotare
+	}
+	if inceput != nil && sfarsit != nil && !inceput.Before(*sfarsit) {
+		return ErrInvalidGradingWindow
+	}
+	return nil
+}
+
+// checkExam validates the grade rule, the grading window, the points and the sections of an exam
+func checkExam(a *Exam) error {
+	rotunjire := a.Rotunjire
+	if len(rotunjire) == 0 {
+		rotunjire = scoring.RotunjireTrunchiere
+	}
+	if !scoring.IsValidRotunjire(rotunjire) || a.PuncteOficiu < 0 {
+		return ErrInvalidGradeRule
+	}
+	if a.PragArbitraj != nil && *a.PragArbitraj < 0 {
+		return ErrInvalidGradeRule
+	}
+	if a.PragContestatie != nil && *a.PragContestatie < 0 {
+		return ErrInvalidGradeRule
+	}
+	if a.InceputNotare != nil && a.SfarsitNotare != nil && !a.InceputNotare.Before(*a.SfarsitNotare) {
+		return ErrInvalidGradingWindow
+	}
 	for _, ex := range a.Exercitii {
-		exercitiu := authentication.Exercitiu{
-			Numar:    ex.Numar,
-			Variante: strings.Join(ex.Variante, ";"),
-			Materie:  ex.Materie,
-			Exam:     exam.Nume,
+		err := checkExercitiuPunctaje(ex)
+		if err != nil {
+			return err
 		}
-		record = db.database.Create(&exercitiu)
-		if record.Error != nil {
-			dbLogger.Error(record.Error.Error())
+		err = scoring.ValidateCompetencies(ex.Competente)
+		if err != nil {
+			return err
 		}
 	}
-	return nil
+	return scoring.ValidateSections(toScoringSections(a.Sectiuni))
 }
 
-func (db *DatabaseHandler) AddCalificativ(profEmail string, calificativ *Calificativ) error {
-	db.mutex.Lock()
-	defer db.mutex.Unlock()
+// saveExam stores a validated exam and recomputes its scores
+func saveExam(tx *gorm.DB, a *Exam) error {
+	rotunjire := a.Rotunjire
+	if len(rotunjire) == 0 {
+		rotunjire = scoring.RotunjireTrunchiere
+	}
+
+	exam := authentication.Exam{
+		Nume:         a.Nume,
+		PuncteOficiu: a.PuncteOficiu,
+		Rotunjire:    rotunjire,
+	}
+	record := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "nume"}},
+		DoUpdates: clause.AssignmentColumns([]string{"puncte_oficiu", "rotunjire"}),
+	}).Create(&exam)
+	if record.Error != nil {
+		return record.Error
+	}
+	if a.PragArbitraj != nil {
+		record = tx.Model(&exam).Update("prag_arbitraj", *a.PragArbitraj)
+		if record.Error != nil {
+			return record.Error
+		}
+	}
+	if a.PragContestatie != nil {
+		record = tx.Model(&exam).Update("prag_contestatie", *a.PragContestatie)
+		if record.Error != nil {
+			return record.Error
+		}
+	}
+	if a.InceputNotare != nil {
+		record = tx.Model(&exam).Update("inceput_notare", *a.InceputNotare)
+		if record.Error != nil {
+			return record.Error
+		}
+	}
+	if a.SfarsitNotare != nil {
+		record = tx.Model(&exam).Update("sfarsit_notare", *a.SfarsitNotare)
+		if record.Error != nil {
+			return record.Error
+		}
+	}
+	if a.Sectiuni != nil {
+		err := replaceSectiuni(tx, exam.Nume, a.Sectiuni)
+		if err != nil {
+			return err
+		}
+	}
 
-	err := db.checkCalificativ(profEmail, calificativ)
+	for _, ex := range a.Exercitii {
+		pondere := ex.Pondere
+		if pondere == 0 {
+			pondere = 1
+		}
+		exercitiu := authentication.Exercitiu{
+			Numar:        ex.Numar,
+			Materie:      ex.Materie,
+			Exam:         exam.Nume,
+			PunctajMaxim: ex.PunctajMaxim,
+			Pondere:      pondere,
+			Sectiune:     ex.Sectiune,
+		}
+		record = tx.Save(&exercitiu)
+		if record.Error != nil {
+			return record.Error
+		}
+
+		existente, err := loadVariante(tx, exam.Nume, ex.Numar)
+		if err != nil {
+			return err
+		}
+		rubrici := make(map[string]*Rubrica, len(existente))
+		for _, varianta := range existente {
+			rubrici[varianta.Nume] = &Rubrica{
+				Descriere:      varianta.Rubrica,
+				ExempluRaspuns: varianta.ExempluRaspuns,
+			}
+		}
+		for nume, rubrica := range ex.Rubrici {
+			rubrici[nume] = rubrica
+		}
+
+		record = tx.Where("exam = ? AND exercitiu = ?", exam.Nume, ex.Numar).Delete(&authentication.VariantaExercitiu{})
+		if record.Error != nil {
+			return record.Error
+		}
+		for idx, nume := range ex.Variante {
+			varianta := authentication.VariantaExercitiu{
+				Exam:      exam.Nume,
+				Exercitiu: ex.Numar,
+				Nume:      nume,
+				Puncte:    ex.Punctaje[nume],
+				Ordine:    idx,
+			}
+			rubrica, ok := rubrici[nume]
+			if ok && rubrica != nil {
+				varianta.Rubrica = rubrica.Descriere
+				varianta.ExempluRaspuns = rubrica.ExempluRaspuns
+			}
+			record = tx.Create(&varianta)
+			if record.Error != nil {
+				return record.Error
+			}
+		}
+
+		if ex.Competente != nil {
+			err = replaceCompetente(tx, exam.Nume, ex.Numar, ex.Competente)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	err := checkExercitiuSectiuni(tx, exam.Nume)
 	if err != nil {
 		return err
 	}
+	return recomputeExamScores(tx, exam.Nume)
+}
 
-	record := db.database.Create(&calificativ)
-	if record.Error != nil {
-		dbLogger.Error(record.Error.Error())
+func checkExercitiuPunctaje(ex Exercitiu) error {
+	for nume, puncte := range ex.Punctaje {
+		if !contains(ex.Variante, nume) {
+			return fmt.Errorf("%w: exercitiul %s nu are varianta %s", ErrUnknownVarianta, ex.Numar, nume)
+		}
+		if puncte < 0 {
+			return fmt.Errorf("%w: exercitiul %s, varianta %s", ErrInvalidPunctaj, ex.Numar, nume)
+		}
+	}
+	for nume := range ex.Rubrici {
+		if !contains(ex.Variante, nume) {
+			return fmt.Errorf("%w: exercitiul %s nu are varianta %s", ErrUnknownVarianta, ex.Numar, nume)
+		}
+	}
+	if ex.PunctajMaxim != nil && *ex.PunctajMaxim < 0 {
+		return fmt.Errorf("%w: punctaj maxim negativ pentru exercitiul %s", ErrInvalidPunctaj, ex.Numar)
+	}
+	if ex.Pondere < 0 {
+		return fmt.Errorf("%w: pondere negativa pentru exercitiul %s", ErrInvalidPunctaj, ex.Numar)
 	}
 	return nil
 }
 
-func (db *DatabaseHandler) GetCalificativByStudentAndExercitiu(id uint, exercitiu uint) (*Calificativ, error) {
+// UpdateRubrica changes the marking scheme and the example answer of one variant of an exercise, while the exam
+// is still a draft
+func (db *databaseHandler) UpdateRubrica(update *RubricaUpdate) error {
 	db.mutex.Lock()
 	defer db.mutex.Unlock()
 
-	var calificativ Calificativ
-	record := db.database.Where("student = ? AND exercitiu = ?", id, exercitiu).First(&calificativ)
+	var exam authentication.Exam
+	record := db.database.Where("nume = ?", update.Exam).First(&exam)
 	if record.Error != nil {
-		return nil, record.Error
+		return record.Error
+	}
+	if exam.Status != ExamCiorna {
+		return ErrExamNotDraft
 	}
-	return &calificativ, nil
-}
-
-func (db *DatabaseHandler) UpdateCalificativ(profEmail string, calificativ *Calificativ) error {
-	db.mutex.Lock()
-	defer db.mutex.Unlock()
 
-	var calificativDb Calificativ
-	record := db.database.Where("student = ? AND exercitiu = ?", calificativ.Student, calificativ.Exercitiu).First(&calificativDb)
+	record = db.database.
+		Model(&authentication.VariantaExercitiu{}).
+		Where("exam = ? AND exercitiu = ? AND nume = ?", update.Exam, update.Exercitiu, update.Varianta).
+		Updates(map[string]interface{}{"rubrica": update.Descriere, "exemplu_raspuns": update.ExempluRaspuns})
 	if record.Error != nil {
 		return record.Error
 	}
 	if record.RowsAffected == 0 {
-		return errors.New("calificativ not found")
+		return errors.New("varianta not found")
 	}
+	return nil
+}
 
-	err := db.checkCalificativ(profEmail, calificativ)
+// AddCalificativ stores the variant chosen by a profesor and recomputes the student's score
+func (db *databaseHandler) AddCalificativ(profEmail string, calificativ *Calificativ) error {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	exercitiu, err := db.checkCalificativ(profEmail, calificativ)
 	if err != nil {
 		return err
 	}
-	record = db.database.Where("student = ? AND exercitiu = ?", calificativ.Student, calificativ.Exercitiu).Save(&calificativ)
-	if record.Error != nil {
-		return record.Error
-	}
-	return nil
 
+	return db.database.Transaction(func(tx *gorm.DB) error {
+		record := tx.Create(&calificativ)
+		if record.Error != nil {
+			return record.Error
+		}
+
+		return recomputeScore(tx, calificativ.Student, exercitiu.Exam, exercitiu.Materie)
+	})
 }
 
-func (db *DatabaseHandler) checkCalificativ(profEmail string, calificativ *Calificativ) error {
-	student, err := db.GetStudentByID(calificativ.Student)
-	if student == nil {
-		return errors.New("student not found")
+func (db *databaseHandler) GetCalificativByStudentAndExercitiu(id uint, exercitiu uint) (*Calificativ, error) {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	var calificativ Calificativ
+	record := db.database.Where("student = ? AND exercitiu = ?", id, exercitiu).First(&calificativ)
+	if record.Error != nil {
+		return nil, record.Error
 	}
+	return &calificativ, nil
+}
+
+// UpdateCalificativ changes the variant chosen by a profesor and recomputes the student's score
+func (db *databaseHandler) UpdateCalificativ(profEmail string, calificativ *Calificativ) error {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
 
-	prof, err := db.GetProfesorByEmail(profEmail)
+	exercitiu, err := db.checkCalificativ(profEmail, calificativ)
 	if err != nil {
 		return err
 	}
-	if prof == nil {
-		return errors.New("profesor not found")
-	}
-	tip := getTypeByMaterie(prof.Materie)
-	var exercitiu *authentication.Exercitiu
-	if tip == "stiinta" {
-		exercitiu, err = db.GetExercitiuStiintaByExamAndNumber(calificativ.Exam, calificativ.Exercitiu)
-		if err != nil {
-			return err
-		}
-		if exercitiu == nil {
-			return errors.New("exercitiu not found")
-		}
-	} else {
-		exercitiu, err = db.GetExercitiuLimbaByExamAndNumber(calificativ.Exam, calificativ.Exercitiu)
-		if err != nil {
-			return err
+
+	return db.database.Transaction(func(tx *gorm.DB) error {
+		record := tx.
+			Table("calificativs").
+			Where("student = ? AND exam = ? AND exercitiu = ? AND slot = ?", calificativ.Student, calificativ.Exam, calificativ.Exercitiu, calificativ.Slot).
+			Updates(map[string]interface{}{"profesor": calificativ.Profesor, "varianta": calificativ.Varianta})
+		if record.Error != nil {
+			return record.Error
 		}
-		if exercitiu == nil {
-			return errors.New("exercitiu not found")
+		if record.RowsAffected == 0 {
+			return errors.New("calificativ not found")
 		}
-	}
 
-	class, err := db.GetClassByID(calificativ.Student)
-	err = db.checkProfesor(prof, class)
-	if err != nil {
-		return err
-	}
-	calificativ.Profesor = prof.ID
+		return recomputeScore(tx, calificativ.Student, exercitiu.Exam, exercitiu.Materie)
+	})
+}
 
-	variante := strings.Split(exercitiu.Variante, ";")
-	if !contains(variante, calificativ.Varianta) {
-		return errors.New("varianta invalida")
+// checkCalificativ validates a mark and fills in the profesor and the grading slot it is written to
+func (db *databaseHandler) checkCalificativ(profEmail string, calificativ *Calificativ) (*authentication.Exercitiu, error) {
+	checker, err := db.newGradingChecker(profEmail)
+	if err != nil {
+		return nil, err
 	}
-
-	return nil
+	return checker.check(calificativ)
 }
 
-func (db *DatabaseHandler) GetCalificative(email string, student string) ([]*Calificativ, error) {
+func (db *databaseHandler) GetCalificative(email string, student string) ([]*Calificativ, error) {
 	prof, err := db.GetProfesorByEmail(email)
 	if err != nil {
 		return make([]*Calificativ, 0), err
@@ -381,10 +749,22 @@ func (db *DatabaseHandler) GetCalificative(email string, student string) ([]*Cal
 	if record.Error != nil {
 		return nil, record.Error
 	}
+
+	comentarii, err := loadComentarii(db.database.Where("student = ? AND profesor = ?", student, prof.ID))
+	if err != nil {
+		return nil, err
+	}
+	for _, calificativ := range calificative {
+		for _, comentariu := range comentarii {
+			if comentariu.Exam == calificativ.Exam && comentariu.Exercitiu == calificativ.Exercitiu {
+				calificativ.Comentariu = comentariu
+			}
+		}
+	}
 	return calificative, nil
 }
 
-func (db *DatabaseHandler) GetExercitiiForProfesorAndStudent(email string, studentId string) ([]*Exercitiu, error) {
+func (db *databaseHandler) GetExercitiiForProfesorAndStudent(email string, studentId string) ([]*Exercitiu, error) {
 	prof, err := db.GetProfesorByEmail(email)
 	if err != nil {
 		return make([]*Exercitiu, 0), err
@@ -403,46 +783,60 @@ func (db *DatabaseHandler) GetExercitiiForProfesorAndStudent(email string, stude
 		return nil, record.Error
 	}
 
-	var class authentication.Clasa
-	record = db.database.Where("nume = ?", student.Clasa).First(&class)
-	if record.Error != nil {
-		return nil, record.Error
+	exam := student.ExamLimba
+	if getTypeByMaterie(prof.Materie) == "stiinta" {
+		exam = student.ExamStiinta
 	}
-
-	err = db.checkProfesor(prof, &class)
+	_, err = db.gradingSlot(prof, &student, exam, prof.Materie)
 	if err != nil {
 		return make([]*Exercitiu, 0), err
 	}
-	var exercitii []authentication.Exercitiu
 
-	tip := getTypeByMaterie(prof.Materie)
-	if tip == "stiinta" {
-		record = db.database.Where("materie = ? AND exam = ?", prof.Materie, student.ExamStiinta).Find(&exercitii)
-		if record.Error != nil {
-			return nil, record.Error
-		}
-	} else {
-		record = db.database.Where("materie = ? AND exam = ?", prof.Materie, student.ExamLimba).Find(&exercitii)
-		if record.Error != nil {
-			return nil, record.Error
-		}
+	var exercitii []authentication.Exercitiu
+	record = db.database.Where("materie = ? AND exam = ?", prof.Materie, exam).Find(&exercitii)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+	competente, err := loadExercitiuCompetente(db.database, exam)
+	if err != nil {
+		return nil, err
 	}
 
 	exercitiiReturn := make([]*Exercitiu, 0)
 	for _, exercitiu := range exercitii {
-		variante := strings.Split(exercitiu.Variante, ";")
+		variante, err := loadVariante(db.database, exercitiu.Exam, exercitiu.Numar)
+		if err != nil {
+			return nil, err
+		}
+		nume := make([]string, 0, len(variante))
+		punctaje := make(map[string]float64, len(variante))
+		rubrici := make(map[string]*Rubrica, len(variante))
+		for _, varianta := range variante {
+			nume = append(nume, varianta.Nume)
+			punctaje[varianta.Nume] = varianta.Puncte
+			rubrici[varianta.Nume] = &Rubrica{
+				Descriere:      varianta.Rubrica,
+				ExempluRaspuns: varianta.ExempluRaspuns,
+			}
+		}
 		exercitiuReturn := &Exercitiu{
-			Numar:    exercitiu.Numar,
-			Variante: variante,
-			Materie:  exercitiu.Materie,
-			Exam:     exercitiu.Exam,
+			Numar:        exercitiu.Numar,
+			Variante:     nume,
+			Punctaje:     punctaje,
+			Rubrici:      rubrici,
+			PunctajMaxim: exercitiu.PunctajMaxim,
+			Pondere:      exercitiu.Pondere,
+			Sectiune:     exercitiu.Sectiune,
+			Competente:   competente[exercitiu.Numar],
+			Materie:      exercitiu.Materie,
+			Exam:         exercitiu.Exam,
 		}
 		exercitiiReturn = append(exercitiiReturn, exercitiuReturn)
 	}
 	return exercitiiReturn, nil
 }
 
-func (db *DatabaseHandler) GetClassByID(studentId uint) (*authentication.Clasa, error) {
+func (db *databaseHandler) GetClassByID(studentId uint) (*authentication.Clasa, error) {
 	var student authentication.Student
 	record := db.database.Where("id = ?", studentId).First(&student)
 	if record.Error != nil {
@@ -457,7 +851,7 @@ func (db *DatabaseHandler) GetClassByID(studentId uint) (*authentication.Clasa,
 	return &class, nil
 }
 
-func (db *DatabaseHandler) checkProfesor(prof *authentication.Profesor, class *authentication.Clasa) error {
+func (db *databaseHandler) checkProfesor(prof *authentication.Profesor, class *authentication.Clasa) error {
 	if prof.Username == class.ProfMate {
 		return nil
 	}
@@ -0,0 +1,60 @@
+package core
+
+import (
+	"github.com/dragos-rebegea/evaluare-tool/authentication"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type absentaKey struct {
+	student uint
+	exam    string
+}
+
+// SetAbsent records or removes the absence of a student from one of their exams. The admin that made the
+// change is kept along with the reason
+func (db *databaseHandler) SetAbsent(email string, status *AbsentStatus) error {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	student, err := db.GetStudentByID(status.Id)
+	if err != nil {
+		return err
+	}
+	if len(status.Exam) == 0 || (student.ExamStiinta != status.Exam && student.ExamLimba != status.Exam) {
+		return ErrStudentNotInExam
+	}
+
+	if !status.Absent {
+		return db.database.Delete(&authentication.Absenta{}, "student = ? AND exam = ?", status.Id, status.Exam).Error
+	}
+
+	absenta := authentication.Absenta{
+		Student: status.Id,
+		Exam:    status.Exam,
+		Motiv:   status.Motiv,
+		Admin:   email,
+	}
+	return db.database.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "student"}, {Name: "exam"}},
+		DoUpdates: clause.AssignmentColumns([]string{"motiv", "admin"}),
+	}).Create(&absenta).Error
+}
+
+// loadAbsente returns the absences of the provided students, indexed by student and exam
+func loadAbsente(tx *gorm.DB, studentIds []uint) (map[absentaKey]*authentication.Absenta, error) {
+	absente := make(map[absentaKey]*authentication.Absenta)
+	if len(studentIds) == 0 {
+		return absente, nil
+	}
+
+	var records []authentication.Absenta
+	record := tx.Where("student IN ?", studentIds).Find(&records)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+	for i := range records {
+		absente[absentaKey{student: records[i].Student, exam: records[i].Exam}] = &records[i]
+	}
+	return absente, nil
+}
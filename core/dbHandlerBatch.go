@@ -0,0 +1,203 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dragos-rebegea/evaluare-tool/authentication"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type markKey struct {
+	student   uint
+	exam      string
+	exercitiu string
+	slot      uint8
+}
+
+func newMarkKey(calificativ *Calificativ) markKey {
+	return markKey{
+		student:   calificativ.Student,
+		exam:      calificativ.Exam,
+		exercitiu: calificativ.Exercitiu,
+		slot:      calificativ.Slot,
+	}
+}
+
+type pendingCalificativ struct {
+	calificativ *Calificativ
+	exercitiu   *authentication.Exercitiu
+	result      *CalificativResult
+}
+
+// AddCalificative writes many marks of a profesor in one transaction. Every entry is validated on its own and
+// the rejected ones do not stop the others, unless AllOrNothing is set. The score of each graded paper is
+// recomputed once, after all its marks were written
+func (db *databaseHandler) AddCalificative(profEmail string, batch *CalificativBatch) (*CalificativBatchResult, error) {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	checker, err := db.newGradingChecker(profEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &CalificativBatchResult{
+		Rezultate: make([]*CalificativResult, 0, len(batch.Calificative)),
+	}
+	pending := make([]*pendingCalificativ, 0, len(batch.Calificative))
+	seen := make(map[markKey]bool, len(batch.Calificative))
+	for _, calificativ := range batch.Calificative {
+		entry := &CalificativResult{
+			Student:   calificativ.Student,
+			Exam:      calificativ.Exam,
+			Exercitiu: calificativ.Exercitiu,
+			Varianta:  calificativ.Varianta,
+		}
+		result.Rezultate = append(result.Rezultate, entry)
+
+		exercitiu, errCheck := checker.check(calificativ)
+		if errCheck != nil {
+			entry.Reason = errCheck.Error()
+			continue
+		}
+		entry.Slot = calificativ.Slot
+
+		key := newMarkKey(calificativ)
+		if seen[key] {
+			entry.Reason = "calificativ duplicat in cerere"
+			continue
+		}
+		seen[key] = true
+		pending = append(pending, &pendingCalificativ{calificativ: calificativ, exercitiu: exercitiu, result: entry})
+	}
+
+	err = db.database.Transaction(func(tx *gorm.DB) error {
+		existing, errExisting := loadExistingCalificative(tx, pending)
+		if errExisting != nil {
+			return errExisting
+		}
+
+		papers := make([]paperKey, 0)
+		graded := make(map[paperKey]bool)
+		for _, entry := range pending {
+			calificativ := entry.calificativ
+			key := newMarkKey(calificativ)
+			if existing[key] && !batch.Upsert {
+				entry.result.Reason = "calificativ already exists"
+				continue
+			}
+
+			rejected, errSave := saveCalificativInTransaction(tx, calificativ)
+			if errSave != nil {
+				return errSave
+			}
+			if rejected != nil {
+				entry.result.Reason = rejected.Error()
+				continue
+			}
+			entry.result.Salvat = true
+
+			paper := paperKey{student: calificativ.Student, exam: entry.exercitiu.Exam, materie: entry.exercitiu.Materie}
+			if !graded[paper] {
+				graded[paper] = true
+				papers = append(papers, paper)
+			}
+		}
+
+		for _, paper := range papers {
+			errScore := recomputeScore(tx, paper.student, paper.exam, paper.materie)
+			if errScore != nil {
+				return errScore
+			}
+		}
+
+		if batch.AllOrNothing && hasRejected(result.Rezultate) {
+			return errCalificativeRolledBack
+		}
+		return nil
+	})
+	if errors.Is(err, errCalificativeRolledBack) {
+		for _, entry := range result.Rezultate {
+			entry.Salvat = false
+			if len(entry.Reason) == 0 {
+				entry.Reason = "lot anulat"
+			}
+		}
+		result.RolledBack = true
+	} else if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range result.Rezultate {
+		if entry.Salvat {
+			result.Salvate++
+		} else {
+			result.Respinse++
+		}
+	}
+	return result, nil
+}
+
+// loadExistingCalificative returns which of the pending marks are already recorded
+func loadExistingCalificative(tx *gorm.DB, pending []*pendingCalificativ) (map[markKey]bool, error) {
+	existing := make(map[markKey]bool)
+	if len(pending) == 0 {
+		return existing, nil
+	}
+
+	students := make([]uint, 0, len(pending))
+	exams := make([]string, 0, 1)
+	for _, entry := range pending {
+		students = append(students, entry.calificativ.Student)
+		if !contains(exams, entry.calificativ.Exam) {
+			exams = append(exams, entry.calificativ.Exam)
+		}
+	}
+
+	var calificative []*Calificativ
+	record := tx.
+		Table("calificativs").
+		Where("student IN ? AND exam IN ?", students, exams).
+		Scan(&calificative)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+	for _, calificativ := range calificative {
+		existing[newMarkKey(calificativ)] = true
+	}
+	return existing, nil
+}
+
+// saveCalificativInTransaction records a mark under a savepoint. It returns the reason the mark was rejected,
+// or an error if the transaction cannot go on
+func saveCalificativInTransaction(tx *gorm.DB, calificativ *Calificativ) (rejected error, err error) {
+	savePoint := "calificativ"
+	err = tx.SavePoint(savePoint).Error
+	if err != nil {
+		return nil, err
+	}
+
+	rejected = tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "student"}, {Name: "exam"}, {Name: "exercitiu"}, {Name: "slot"}},
+		DoUpdates: clause.AssignmentColumns([]string{"profesor", "varianta"}),
+	}).Create(calificativ).Error
+	if rejected == nil {
+		return nil, nil
+	}
+	err = tx.RollbackTo(savePoint).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w while rolling back the calificativ rejected with: %v", err, rejected)
+	}
+	return rejected, nil
+}
+
+func hasRejected(results []*CalificativResult) bool {
+	for _, entry := range results {
+		if !entry.Salvat {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,194 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/dragos-rebegea/evaluare-tool/authentication"
+	"gorm.io/gorm"
+)
+
+// CloneExam copies an exam, with its sections, exercises, points and rubrics, under a new name. The copy starts
+// as a draft without a grading window and without any marks
+func (db *databaseHandler) CloneExam(request *CloneExamRequest) (*Exam, error) {
+	if len(request.Nume) == 0 {
+		return nil, fmt.Errorf("%w: numele examenului este gol", ErrInvalidCloneRequest)
+	}
+
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	var clone *Exam
+	err := db.database.Transaction(func(tx *gorm.DB) error {
+		var existing int64
+		record := tx.Model(&authentication.Exam{}).Where("nume = ?", request.Nume).Count(&existing)
+		if record.Error != nil {
+			return record.Error
+		}
+		if existing > 0 {
+			return ErrExamExists
+		}
+
+		source, err := loadExamDefinition(tx, request.Exam)
+		if err != nil {
+			return err
+		}
+		clone, err = cloneExamDefinition(source, request)
+		if err != nil {
+			return err
+		}
+		err = checkExam(clone)
+		if err != nil {
+			return err
+		}
+
+		return saveExam(tx, clone)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return clone, nil
+}
+
+// MoveExamStudents moves the science and language exam assignments of students from one exam to another
+func (db *databaseHandler) MoveExamStudents(request *MoveStudentsRequest) (*MoveStudentsResult, error) {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	result := &MoveStudentsResult{}
+	err := db.database.Transaction(func(tx *gorm.DB) error {
+		var exam authentication.Exam
+		record := tx.Where("nume = ?", request.NouExam).First(&exam)
+		if record.Error != nil {
+			return record.Error
+		}
+
+		for _, column := range []string{"exam_stiinta", "exam_limba"} {
+			query := tx.Model(&authentication.Student{}).Where(column+" = ?", request.Exam)
+			if len(request.Clase) > 0 {
+				query = query.Where("clasa IN ?", request.Clase)
+			}
+			record = query.Update(column, exam.Nume)
+			if record.Error != nil {
+				return record.Error
+			}
+			result.Mutati += record.RowsAffected
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// loadExamDefinition returns an exam in the format accepted by CreateExam
+func loadExamDefinition(tx *gorm.DB, nume string) (*Exam, error) {
+	var exam authentication.Exam
+	record := tx.Where("nume = ?", nume).First(&exam)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+
+	pragArbitraj := exam.PragArbitraj
+	pragContestatie := exam.PragContestatie
+	result := &Exam{
+		Nume:            exam.Nume,
+		PuncteOficiu:    exam.PuncteOficiu,
+		Rotunjire:       exam.Rotunjire,
+		PragArbitraj:    &pragArbitraj,
+		PragContestatie: &pragContestatie,
+		Sectiuni:        make([]Sectiune, 0),
+		Exercitii:       make([]Exercitiu, 0),
+	}
+
+	sections, err := loadSections(tx, nume)
+	if err != nil {
+		return nil, err
+	}
+	for _, section := range sections {
+		result.Sectiuni = append(result.Sectiuni, Sectiune{
+			Cod:     section.Cod,
+			Titlu:   section.Titlu,
+			Parinte: section.Parinte,
+		})
+	}
+
+	var exercitii []authentication.Exercitiu
+	record = tx.Where("exam = ?", nume).Order("materie, numar").Find(&exercitii)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+	competente, err := loadExercitiuCompetente(tx, nume)
+	if err != nil {
+		return nil, err
+	}
+	for _, exercitiu := range exercitii {
+		variante, errVariante := loadVariante(tx, nume, exercitiu.Numar)
+		if errVariante != nil {
+			return nil, errVariante
+		}
+
+		ex := Exercitiu{
+			Numar:        exercitiu.Numar,
+			Variante:     make([]string, 0, len(variante)),
+			Punctaje:     make(map[string]float64, len(variante)),
+			Rubrici:      make(map[string]*Rubrica, len(variante)),
+			PunctajMaxim: exercitiu.PunctajMaxim,
+			Pondere:      exercitiu.Pondere,
+			Sectiune:     exercitiu.Sectiune,
+			Competente:   competente[exercitiu.Numar],
+			Materie:      exercitiu.Materie,
+			Exam:         exercitiu.Exam,
+		}
+		for _, varianta := range variante {
+			ex.Variante = append(ex.Variante, varianta.Nume)
+			ex.Punctaje[varianta.Nume] = varianta.Puncte
+			ex.Rubrici[varianta.Nume] = &Rubrica{
+				Descriere:      varianta.Rubrica,
+				ExempluRaspuns: varianta.ExempluRaspuns,
+			}
+		}
+		result.Exercitii = append(result.Exercitii, ex)
+	}
+	return result, nil
+}
+
+// cloneExamDefinition renames an exam definition and applies the renamed and dropped exercises of the request
+func cloneExamDefinition(source *Exam, request *CloneExamRequest) (*Exam, error) {
+	numere := make(map[string]bool, len(source.Exercitii))
+	for _, ex := range source.Exercitii {
+		numere[ex.Numar] = true
+	}
+	for numar := range request.Redenumiri {
+		if !numere[numar] {
+			return nil, fmt.Errorf("%w: exercitiul %s nu exista", ErrInvalidCloneRequest, numar)
+		}
+	}
+	for _, numar := range request.Excluse {
+		if !numere[numar] {
+			return nil, fmt.Errorf("%w: exercitiul %s nu exista", ErrInvalidCloneRequest, numar)
+		}
+	}
+
+	clone := *source
+	clone.Nume = request.Nume
+	clone.Exercitii = make([]Exercitiu, 0, len(source.Exercitii))
+	folosite := make(map[string]bool, len(source.Exercitii))
+	for _, ex := range source.Exercitii {
+		if contains(request.Excluse, ex.Numar) {
+			continue
+		}
+		numar, redenumit := request.Redenumiri[ex.Numar]
+		if redenumit {
+			ex.Numar = numar
+		}
+		if len(ex.Numar) == 0 || folosite[ex.Numar] {
+			return nil, fmt.Errorf("%w: numarul %q apare de doua ori", ErrInvalidCloneRequest, ex.Numar)
+		}
+		folosite[ex.Numar] = true
+
+		ex.Exam = request.Nume
+		clone.Exercitii = append(clone.Exercitii, ex)
+	}
+	return &clone, nil
+}
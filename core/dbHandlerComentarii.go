@@ -0,0 +1,129 @@
+package core
+
+import (
+	"strings"
+
+	"github.com/dragos-rebegea/evaluare-tool/authentication"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SetComentariu stores the comment of a profesor on a paper. The profesor must grade the commented exercise, or
+// their subject of the exam for a comment on the whole paper. Comments can be changed until the results of the
+// exam are released. It returns nil when the comment was removed
+func (db *databaseHandler) SetComentariu(email string, request *ComentariuRequest) (*Comentariu, error) {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	prof, err := db.GetProfesorByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	student, err := db.GetStudentByID(request.Student)
+	if err != nil {
+		return nil, err
+	}
+	if len(request.Exam) == 0 || (student.ExamStiinta != request.Exam && student.ExamLimba != request.Exam) {
+		return nil, ErrStudentNotInExam
+	}
+	exam, err := db.GetExamByName(request.Exam)
+	if err != nil {
+		return nil, err
+	}
+	if exam.Status == ExamRezultatePublicate {
+		return nil, ErrComentariiInchise
+	}
+
+	materie := prof.Materie
+	if len(request.Exercitiu) > 0 {
+		var exercitiu authentication.Exercitiu
+		record := db.database.Where("exam = ? AND numar = ?", request.Exam, request.Exercitiu).First(&exercitiu)
+		if record.Error != nil {
+			return nil, record.Error
+		}
+		materie = exercitiu.Materie
+	}
+	_, err = db.gradingSlot(prof, student, request.Exam, materie)
+	if err != nil {
+		return nil, err
+	}
+
+	comentariu := authentication.Comentariu{
+		Student:   request.Student,
+		Exam:      request.Exam,
+		Exercitiu: request.Exercitiu,
+		Profesor:  prof.ID,
+	}
+	query := db.database.Where(
+		"student = ? AND exam = ? AND exercitiu = ? AND profesor = ?",
+		comentariu.Student, comentariu.Exam, comentariu.Exercitiu, comentariu.Profesor,
+	)
+	text := strings.TrimSpace(request.Text)
+	if len(text) == 0 {
+		record := query.Delete(&authentication.Comentariu{})
+		return nil, record.Error
+	}
+
+	comentariu.Text = text
+	record := db.database.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "student"}, {Name: "exam"}, {Name: "exercitiu"}, {Name: "profesor"}},
+		DoUpdates: clause.AssignmentColumns([]string{"text", "updated_at"}),
+	}).Create(&comentariu)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+
+	comentarii, err := loadComentarii(query)
+	if err != nil {
+		return nil, err
+	}
+	if len(comentarii) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return comentarii[0], nil
+}
+
+// loadComentarii returns the comments matching the provided query, with the email of their author, ordered by
+// exam and exercise. The comments on the whole paper come first
+func loadComentarii(query *gorm.DB) ([]*Comentariu, error) {
+	var records []authentication.Comentariu
+	record := query.Order("exam, exercitiu, created_at").Find(&records)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+	if len(records) == 0 {
+		return make([]*Comentariu, 0), nil
+	}
+
+	ids := make([]uint, 0, len(records))
+	for _, comentariu := range records {
+		ids = append(ids, comentariu.Profesor)
+	}
+	var profesori []authentication.Profesor
+	record = query.Session(&gorm.Session{NewDB: true}).Where("id IN ?", ids).Find(&profesori)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+	emails := make(map[uint]string, len(profesori))
+	for _, prof := range profesori {
+		emails[prof.ID] = prof.Email
+	}
+
+	comentarii := make([]*Comentariu, 0, len(records))
+	for _, comentariu := range records {
+		comentarii = append(comentarii, &Comentariu{
+			Exam:      comentariu.Exam,
+			Exercitiu: comentariu.Exercitiu,
+			Autor:     emails[comentariu.Profesor],
+			Text:      comentariu.Text,
+			CreatedAt: comentariu.CreatedAt,
+			UpdatedAt: comentariu.UpdatedAt,
+		})
+	}
+	return comentarii, nil
+}
+
+// loadComentariiGenerale returns the comments on the whole paper of a student on an exam
+func loadComentariiGenerale(tx *gorm.DB, studentId uint, exam string) ([]*Comentariu, error) {
+	return loadComentarii(tx.Where("student = ? AND exam = ? AND exercitiu = ?", studentId, exam, ""))
+}
@@ -0,0 +1,234 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/dragos-rebegea/evaluare-tool/authentication"
+	"github.com/dragos-rebegea/evaluare-tool/scoring"
+	"github.com/dragos-rebegea/evaluare-tool/statistics"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type competentaKey struct {
+	materie string
+	cod     string
+}
+
+// SetCompetente adds the provided competencies to the curriculum, replacing the description of the existing ones
+func (db *databaseHandler) SetCompetente(competente []*Competenta) error {
+	for _, competenta := range competente {
+		if len(competenta.Materie) == 0 || len(competenta.Cod) == 0 {
+			return fmt.Errorf("%w: materia si codul sunt obligatorii", scoring.ErrInvalidCompetency)
+		}
+	}
+
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	return db.database.Transaction(func(tx *gorm.DB) error {
+		for _, competenta := range competente {
+			record := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "materie"}, {Name: "cod"}},
+				DoUpdates: clause.AssignmentColumns([]string{"descriere"}),
+			}).Create(&authentication.Competenta{
+				Materie:   competenta.Materie,
+				Cod:       competenta.Cod,
+				Descriere: competenta.Descriere,
+			})
+			if record.Error != nil {
+				return record.Error
+			}
+		}
+		return nil
+	})
+}
+
+// GetCompetente returns the competencies of the curriculum. An empty materie selects all subjects
+func (db *databaseHandler) GetCompetente(materie string) ([]*Competenta, error) {
+	query := db.database.Order("materie, cod")
+	if len(materie) > 0 {
+		query = query.Where("materie = ?", materie)
+	}
+	var records []authentication.Competenta
+	record := query.Find(&records)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+
+	competente := make([]*Competenta, 0, len(records))
+	for _, competenta := range records {
+		competente = append(competente, &Competenta{
+			Materie:   competenta.Materie,
+			Cod:       competenta.Cod,
+			Descriere: competenta.Descriere,
+		})
+	}
+	return competente, nil
+}
+
+// GetCompetencyReport returns the mastery of the competencies assessed by an exam for a class and for each of
+// its present students, if the profesor teaches the class. The points of an exercise are the ones counted in
+// the final score, so the report agrees with the grades. Admins can access every class. Unless the profesor
+// manages the grading, only the final papers are profiled
+func (db *databaseHandler) GetCompetencyReport(email string, clasa string, exam string) (*CompetencyReport, error) {
+	err := db.checkClassAccess(email, clasa)
+	if err != nil {
+		return nil, err
+	}
+	visibility, err := db.newScoreVisibility(email)
+	if err != nil {
+		return nil, err
+	}
+
+	var examRecord authentication.Exam
+	record := db.database.Where("nume = ?", exam).First(&examRecord)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+	ids, err := loadExamStudents(db.database.Where("clasa = ?", clasa), exam)
+	if err != nil {
+		return nil, err
+	}
+	var students []authentication.Student
+	record = db.database.Where("id IN ?", ids).Order("nume, prenume, id").Find(&students)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+	schemes, err := loadExerciseSchemes(db.database, exam, "")
+	if err != nil {
+		return nil, err
+	}
+	descrieri, err := loadCompetentaDescrieri(db.database)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &CompetencyReport{
+		Clasa:      clasa,
+		Exam:       exam,
+		Elevi:      len(students),
+		Competente: make([]*CompetentaStatistics, 0),
+		Profiluri:  make([]*StudentCompetente, 0, len(students)),
+	}
+	stapaniri := make(map[competentaKey][]float64)
+	for _, student := range students {
+		var scores []authentication.Scor
+		record = db.database.Where("student = ? AND exam = ?", student.ID, exam).Find(&scores)
+		if record.Error != nil {
+			return nil, record.Error
+		}
+		final, errFinal := visibility.finalPaper(scores)
+		if errFinal != nil {
+			return nil, errFinal
+		}
+		if !final {
+			continue
+		}
+		puncte, errPuncte := loadFinalPuncte(db.database, student.ID, exam, schemes)
+		if errPuncte != nil {
+			return nil, errPuncte
+		}
+
+		profil := &StudentCompetente{
+			Student:    student.ID,
+			Nume:       student.Nume,
+			Prenume:    student.Prenume,
+			Competente: scoring.ComputeCompetencyTotals(schemes, puncte),
+		}
+		if len(scores) > 0 {
+			profil.Nota = examGrade(&examRecord, scores)
+		}
+		comentarii, errComentarii := loadComentariiGenerale(db.database, student.ID, exam)
+		if errComentarii != nil {
+			return nil, errComentarii
+		}
+		profil.Comentarii = visibility.comentarii(&examRecord, comentarii)
+		for _, total := range profil.Competente {
+			key := competentaKey{materie: total.Materie, cod: total.Cod}
+			stapaniri[key] = append(stapaniri[key], total.Stapanire)
+		}
+		sortWeakestFirst(profil.Competente)
+		report.Profiluri = append(report.Profiluri, profil)
+	}
+
+	toate := make(map[string]float64, len(schemes))
+	for _, scheme := range schemes {
+		toate[scheme.Numar] = 0
+	}
+	for _, total := range scoring.ComputeCompetencyTotals(schemes, toate) {
+		key := competentaKey{materie: total.Materie, cod: total.Cod}
+		report.Competente = append(report.Competente, &CompetentaStatistics{
+			Materie:      total.Materie,
+			Cod:          total.Cod,
+			Descriere:    descrieri[key],
+			Exercitii:    total.Exercitii,
+			PunctajMaxim: total.PunctajMaxim,
+			Stapanire:    statistics.Summarize(stapaniri[key]),
+		})
+	}
+	sort.SliceStable(report.Competente, func(i, j int) bool {
+		left, right := report.Competente[i].Stapanire, report.Competente[j].Stapanire
+		if (left.Count == 0) != (right.Count == 0) {
+			return right.Count == 0
+		}
+		return left.Media < right.Media
+	})
+	return report, nil
+}
+
+// sortWeakestFirst orders the competencies of a student from the lowest mastery to the highest
+func sortWeakestFirst(totals []*scoring.CompetencyTotal) {
+	sort.SliceStable(totals, func(i, j int) bool {
+		return totals[i].Stapanire < totals[j].Stapanire
+	})
+}
+
+// replaceCompetente stores the competencies assessed by an exercise
+func replaceCompetente(tx *gorm.DB, exam string, exercitiu string, competente []string) error {
+	record := tx.Where("exam = ? AND exercitiu = ?", exam, exercitiu).Delete(&authentication.CompetentaExercitiu{})
+	if record.Error != nil {
+		return record.Error
+	}
+	for _, cod := range competente {
+		record = tx.Create(&authentication.CompetentaExercitiu{
+			Exam:       exam,
+			Exercitiu:  exercitiu,
+			Competenta: cod,
+		})
+		if record.Error != nil {
+			return record.Error
+		}
+	}
+	return nil
+}
+
+// loadExercitiuCompetente returns the competencies assessed by the exercises of an exam, indexed by exercise number
+func loadExercitiuCompetente(tx *gorm.DB, exam string) (map[string][]string, error) {
+	var records []authentication.CompetentaExercitiu
+	record := tx.Where("exam = ?", exam).Order("exercitiu, competenta").Find(&records)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+
+	competente := make(map[string][]string)
+	for _, competenta := range records {
+		competente[competenta.Exercitiu] = append(competente[competenta.Exercitiu], competenta.Competenta)
+	}
+	return competente, nil
+}
+
+func loadCompetentaDescrieri(tx *gorm.DB) (map[competentaKey]string, error) {
+	var records []authentication.Competenta
+	record := tx.Find(&records)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+
+	descrieri := make(map[competentaKey]string, len(records))
+	for _, competenta := range records {
+		descrieri[competentaKey{materie: competenta.Materie, cod: competenta.Cod}] = competenta.Descriere
+	}
+	return descrieri, nil
+}
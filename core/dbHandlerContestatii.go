@@ -0,0 +1,221 @@
+package core
+
+import (
+	"errors"
+
+	"github.com/dragos-rebegea/evaluare-tool/authentication"
+	"github.com/dragos-rebegea/evaluare-tool/scoring"
+	"gorm.io/gorm"
+)
+
+// FileContestatie records an appeal against the grade of a student on one subject of an exam, once the results
+// were released. The current grade is stored as the grade before the re-evaluation
+func (db *databaseHandler) FileContestatie(email string, request *ContestatieRequest) (*authentication.Contestatie, error) {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	var exam authentication.Exam
+	record := db.database.Where("nume = ?", request.Exam).First(&exam)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+	if exam.Status != ExamRezultatePublicate {
+		return nil, ErrRezultateNepublicate
+	}
+
+	var scor authentication.Scor
+	record = db.database.Where("student = ? AND exam = ? AND materie = ?", request.Student, request.Exam, request.Materie).Limit(1).Find(&scor)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+	if record.RowsAffected == 0 {
+		return nil, errors.New("lucrarea nu a fost notata")
+	}
+
+	var arbitraj authentication.Arbitraj
+	record = db.database.
+		Where("student = ? AND exam = ? AND materie = ? AND status <> ?", request.Student, request.Exam, request.Materie, ArbitrajRezolvat).
+		Limit(1).
+		Find(&arbitraj)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+	if record.RowsAffected > 0 {
+		return nil, errors.New("lucrarea asteapta arbitraj")
+	}
+
+	existing, err := findContestatie(db.database, request.Student, request.Exam, request.Materie)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, errors.New("contestatie already filed")
+	}
+
+	rule := scoring.GradeRule{
+		PuncteOficiu: exam.PuncteOficiu,
+		Rotunjire:    exam.Rotunjire,
+	}
+	contestatie := &authentication.Contestatie{
+		Student:      request.Student,
+		Exam:         request.Exam,
+		Materie:      request.Materie,
+		DepusaDe:     email,
+		Motiv:        request.Motiv,
+		Status:       ContestatieDepusa,
+		NotaInitiala: scoring.ComputeGrade(scor.Punctaj, scor.PunctajMaxim, rule),
+	}
+	record = db.database.Create(contestatie)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+	return contestatie, nil
+}
+
+// AssignContestatie assigns a profesor that did not grade the paper before to re-evaluate it
+func (db *databaseHandler) AssignContestatie(assignment *ContestatieAssignment) error {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	prof, err := db.GetProfesorByEmail(assignment.Profesor)
+	if err != nil {
+		return err
+	}
+
+	contestatie, err := findContestatie(db.database, assignment.Student, assignment.Exam, assignment.Materie)
+	if err != nil {
+		return err
+	}
+	if contestatie == nil {
+		return ErrContestatieNotFound
+	}
+	if contestatie.Status != ContestatieDepusa && contestatie.Status != ContestatieAlocata {
+		return ErrContestatieStatus
+	}
+
+	var evaluatori []uint
+	record := db.database.
+		Table("calificativs").
+		Where("student = ? AND exam = ? AND slot <> ?", assignment.Student, assignment.Exam, SlotContestatie).
+		Where("exercitiu IN (?)", exercitiiMaterie(db.database, assignment.Exam, assignment.Materie)).
+		Distinct().
+		Pluck("profesor", &evaluatori)
+	if record.Error != nil {
+		return record.Error
+	}
+	for _, evaluator := range evaluatori {
+		if evaluator == prof.ID {
+			return errors.New("profesorul a evaluat deja lucrarea")
+		}
+	}
+
+	contestatie.Profesor = prof.ID
+	contestatie.Status = ContestatieAlocata
+	return db.database.Save(contestatie).Error
+}
+
+// ResolveContestatie decides the final grade of a re-evaluated paper, using the threshold of the exam, and
+// recomputes the stored score of the student
+func (db *databaseHandler) ResolveContestatie(request *ContestatieRequest) (*authentication.Contestatie, error) {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	var contestatie *authentication.Contestatie
+	err := db.database.Transaction(func(tx *gorm.DB) error {
+		var err error
+		contestatie, err = findContestatie(tx, request.Student, request.Exam, request.Materie)
+		if err != nil {
+			return err
+		}
+		if contestatie == nil {
+			return ErrContestatieNotFound
+		}
+		if contestatie.Status != ContestatieReevaluata || contestatie.NotaNoua == nil {
+			return ErrContestatieStatus
+		}
+
+		var exam authentication.Exam
+		record := tx.Where("nume = ?", contestatie.Exam).First(&exam)
+		if record.Error != nil {
+			return record.Error
+		}
+
+		notaFinala := scoring.ApplyContestatie(contestatie.NotaInitiala, *contestatie.NotaNoua, exam.PragContestatie)
+		contestatie.NotaFinala = &notaFinala
+		contestatie.Aplicata = notaFinala != contestatie.NotaInitiala
+		contestatie.Status = ContestatieRezolvata
+		record = tx.Save(contestatie)
+		if record.Error != nil {
+			return record.Error
+		}
+
+		return recomputeScore(tx, contestatie.Student, contestatie.Exam, contestatie.Materie)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return contestatie, nil
+}
+
+// GetContestatii returns the appeals with the provided status, or all of them if the status is empty
+func (db *databaseHandler) GetContestatii(status string) ([]authentication.Contestatie, error) {
+	query := db.database.Order("exam, materie, student")
+	if len(status) > 0 {
+		query = query.Where("status = ?", status)
+	}
+
+	var contestatii []authentication.Contestatie
+	record := query.Find(&contestatii)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+	return contestatii, nil
+}
+
+// isContestata returns true if an appeal was filed for the paper of a student on one subject of an exam
+func (db *databaseHandler) isContestata(studentId uint, exam string, materie string) (bool, error) {
+	contestatie, err := findContestatie(db.database, studentId, exam, materie)
+	if err != nil {
+		return false, err
+	}
+	return contestatie != nil, nil
+}
+
+func findContestatie(tx *gorm.DB, studentId uint, exam string, materie string) (*authentication.Contestatie, error) {
+	var contestatie authentication.Contestatie
+	record := tx.Where("student = ? AND exam = ? AND materie = ?", studentId, exam, materie).Limit(1).Find(&contestatie)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+	if record.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &contestatie, nil
+}
+
+// updateContestatie stores the new grade once the re-evaluation covers every exercise. It returns true if the
+// appeal was resolved in favour of the new grade, which then replaces the total of the correctors
+func updateContestatie(
+	tx *gorm.DB,
+	studentId uint,
+	exam string,
+	materie string,
+	reevaluare *scoring.SubjectTotal,
+	rule scoring.GradeRule,
+) (bool, error) {
+	contestatie, err := findContestatie(tx, studentId, exam, materie)
+	if err != nil || contestatie == nil {
+		return false, err
+	}
+	if contestatie.Status == ContestatieRezolvata {
+		return contestatie.Aplicata && scoring.IsComplete(reevaluare), nil
+	}
+	if contestatie.Status == ContestatieDepusa || !scoring.IsComplete(reevaluare) {
+		return false, nil
+	}
+
+	notaNoua := scoring.ComputeGrade(reevaluare.Punctaj, reevaluare.PunctajMaxim, rule)
+	contestatie.NotaNoua = &notaNoua
+	contestatie.Status = ContestatieReevaluata
+	return false, tx.Save(contestatie).Error
+}
@@ -0,0 +1,304 @@
+package core
+
+import (
+	"sort"
+	"time"
+
+	"github.com/dragos-rebegea/evaluare-tool/authentication"
+	"github.com/dragos-rebegea/evaluare-tool/statistics"
+	"gorm.io/gorm"
+)
+
+// GetStudentEvolution returns every exam a student was graded on, with the grade, the points per subject and
+// per section, and the change since the previous exam. Only the profesori of the class and admins can access it.
+// Exams with a total hidden from the profesor are left out, and the sections are shown only on final papers
+func (db *databaseHandler) GetStudentEvolution(email string, studentId string) (*StudentEvolution, error) {
+	id, err := db.checkStudentAccess(email, studentId)
+	if err != nil {
+		return nil, err
+	}
+	visibility, err := db.newScoreVisibility(email)
+	if err != nil {
+		return nil, err
+	}
+
+	var student authentication.Student
+	record := db.database.Where("id = ?", id).First(&student)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+	scores, err := loadScoresByExam(db.database, []uint{id})
+	if err != nil {
+		return nil, err
+	}
+	finale := make(map[string]bool, len(scores))
+	for exam, byStudent := range scores {
+		visible, final, errPaper := visibility.paper(byStudent[id])
+		if errPaper != nil {
+			return nil, errPaper
+		}
+		if visible == nil {
+			delete(scores, exam)
+			continue
+		}
+		byStudent[id] = visible
+		finale[exam] = final
+	}
+	exams, err := loadExamsInOrder(db.database, scores)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &StudentEvolution{
+		Student: id,
+		Nume:    student.Nume,
+		Prenume: student.Prenume,
+		Clasa:   student.Clasa,
+		Exame:   make([]*ExamEvolution, 0, len(exams)),
+	}
+	for _, exam := range exams {
+		evolution := newExamEvolution(exam, scores[exam.exam.Nume][id])
+		if finale[exam.exam.Nume] {
+			evolution.Sectiuni, err = computeStudentSections(db.database, id, exam.exam.Nume)
+			if err != nil {
+				return nil, err
+			}
+		}
+		result.Exame = append(result.Exame, evolution)
+	}
+	setEvolutionDeltas(result.Exame)
+	return result, nil
+}
+
+// GetClassEvolution returns the average results of the current students of a class on every exam they were
+// graded on, and the change since the previous exam, if the profesor teaches the class. Admins can access every
+// class. Unless the profesor manages the grading, only the final papers are averaged
+func (db *databaseHandler) GetClassEvolution(email string, clasa string) (*ClassEvolution, error) {
+	err := db.checkClassAccess(email, clasa)
+	if err != nil {
+		return nil, err
+	}
+	visibility, err := db.newScoreVisibility(email)
+	if err != nil {
+		return nil, err
+	}
+
+	var students []uint
+	record := db.database.Model(&authentication.Student{}).Where("clasa = ?", clasa).Order("id").Pluck("id", &students)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+	scores, err := loadScoresByExam(db.database, students)
+	if err != nil {
+		return nil, err
+	}
+	for exam, byStudent := range scores {
+		for student, studentScores := range byStudent {
+			final, errFinal := visibility.finalPaper(studentScores)
+			if errFinal != nil {
+				return nil, errFinal
+			}
+			if !final {
+				delete(byStudent, student)
+			}
+		}
+		if len(byStudent) == 0 {
+			delete(scores, exam)
+		}
+	}
+	exams, err := loadExamsInOrder(db.database, scores)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ClassEvolution{
+		Clasa: clasa,
+		Exame: make([]*ExamEvolution, 0, len(exams)),
+	}
+	for _, exam := range exams {
+		evolutions := make([]*ExamEvolution, 0, len(scores[exam.exam.Nume]))
+		for _, student := range students {
+			studentScores, graded := scores[exam.exam.Nume][student]
+			if graded {
+				evolutions = append(evolutions, newExamEvolution(exam, studentScores))
+			}
+		}
+		result.Exame = append(result.Exame, averageExamEvolution(exam, evolutions))
+	}
+	setEvolutionDeltas(result.Exame)
+	return result, nil
+}
+
+type datedExam struct {
+	exam *authentication.Exam
+	data *time.Time
+}
+
+// loadScoresByExam returns the stored totals of the provided students, indexed by exam and student
+func loadScoresByExam(tx *gorm.DB, students []uint) (map[string]map[uint][]authentication.Scor, error) {
+	var scores []authentication.Scor
+	record := tx.Where("student IN ?", students).Order("exam, student, materie").Find(&scores)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+
+	result := make(map[string]map[uint][]authentication.Scor)
+	for _, score := range scores {
+		_, ok := result[score.Exam]
+		if !ok {
+			result[score.Exam] = make(map[uint][]authentication.Scor)
+		}
+		result[score.Exam][score.Student] = append(result[score.Exam][score.Student], score)
+	}
+	return result, nil
+}
+
+// loadExamsInOrder returns the exams of the provided scores from the oldest to the newest. An exam is dated by
+// the start of its grading window or, without one, by the first change of its state. Exams that have neither
+// predate both and come first, by name
+func loadExamsInOrder(tx *gorm.DB, scores map[string]map[uint][]authentication.Scor) ([]*datedExam, error) {
+	nume := make([]string, 0, len(scores))
+	for exam := range scores {
+		nume = append(nume, exam)
+	}
+	if len(nume) == 0 {
+		return make([]*datedExam, 0), nil
+	}
+
+	var exams []authentication.Exam
+	record := tx.Where("nume IN ?", nume).Find(&exams)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+	var tranzitii []authentication.TranzitieExam
+	record = tx.Where("exam IN ?", nume).Order("created_at, id").Find(&tranzitii)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+	primaTranzitie := make(map[string]time.Time, len(exams))
+	for _, tranzitie := range tranzitii {
+		_, found := primaTranzitie[tranzitie.Exam]
+		if !found {
+			primaTranzitie[tranzitie.Exam] = tranzitie.CreatedAt
+		}
+	}
+
+	result := make([]*datedExam, 0, len(exams))
+	for i := range exams {
+		exam := &datedExam{exam: &exams[i], data: exams[i].InceputNotare}
+		data, found := primaTranzitie[exams[i].Nume]
+		if exam.data == nil && found {
+			exam.data = &data
+		}
+		result = append(result, exam)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		left, right := result[i].data, result[j].data
+		if (left == nil) != (right == nil) {
+			return left == nil
+		}
+		if left != nil && !left.Equal(*right) {
+			return left.Before(*right)
+		}
+		return result[i].exam.Nume < result[j].exam.Nume
+	})
+	return result, nil
+}
+
+// newExamEvolution converts the totals of a student on an exam into a grade and percentages per subject
+func newExamEvolution(exam *datedExam, scores []authentication.Scor) *ExamEvolution {
+	grade := examGrade(exam.exam, scores)
+	evolution := &ExamEvolution{
+		Exam:         exam.exam.Nume,
+		Data:         exam.data,
+		Nota:         grade.Nota,
+		Punctaj:      grade.Punctaj,
+		PunctajMaxim: grade.PunctajMaxim,
+		Materii:      make([]*MaterieEvolution, 0, len(scores)),
+	}
+	for _, score := range scores {
+		evolution.Materii = append(evolution.Materii, &MaterieEvolution{
+			Materie:      score.Materie,
+			Punctaj:      score.Punctaj,
+			PunctajMaxim: score.PunctajMaxim,
+			Procent:      percentOf(score.Punctaj, score.PunctajMaxim),
+		})
+	}
+	return evolution
+}
+
+// averageExamEvolution averages the results of many students on the same exam
+func averageExamEvolution(exam *datedExam, evolutions []*ExamEvolution) *ExamEvolution {
+	note := make([]float64, 0, len(evolutions))
+	puncte := make([]float64, 0, len(evolutions))
+	maxime := make([]float64, 0, len(evolutions))
+	materii := make([]string, 0)
+	byMaterie := make(map[string][]*MaterieEvolution)
+	for _, evolution := range evolutions {
+		note = append(note, evolution.Nota)
+		puncte = append(puncte, evolution.Punctaj)
+		maxime = append(maxime, evolution.PunctajMaxim)
+		for _, materie := range evolution.Materii {
+			_, found := byMaterie[materie.Materie]
+			if !found {
+				materii = append(materii, materie.Materie)
+			}
+			byMaterie[materie.Materie] = append(byMaterie[materie.Materie], materie)
+		}
+	}
+	sort.Strings(materii)
+
+	result := &ExamEvolution{
+		Exam:         exam.exam.Nume,
+		Data:         exam.data,
+		Elevi:        len(evolutions),
+		Nota:         statistics.Round(statistics.Mean(note)),
+		Punctaj:      statistics.Round(statistics.Mean(puncte)),
+		PunctajMaxim: statistics.Round(statistics.Mean(maxime)),
+		Materii:      make([]*MaterieEvolution, 0, len(materii)),
+	}
+	for _, materie := range materii {
+		puncteMaterie := make([]float64, 0, len(byMaterie[materie]))
+		maximeMaterie := make([]float64, 0, len(byMaterie[materie]))
+		procente := make([]float64, 0, len(byMaterie[materie]))
+		for _, evolution := range byMaterie[materie] {
+			puncteMaterie = append(puncteMaterie, evolution.Punctaj)
+			maximeMaterie = append(maximeMaterie, evolution.PunctajMaxim)
+			procente = append(procente, evolution.Procent)
+		}
+		result.Materii = append(result.Materii, &MaterieEvolution{
+			Materie:      materie,
+			Punctaj:      statistics.Round(statistics.Mean(puncteMaterie)),
+			PunctajMaxim: statistics.Round(statistics.Mean(maximeMaterie)),
+			Procent:      statistics.Round(statistics.Mean(procente)),
+		})
+	}
+	return result
+}
+
+// setEvolutionDeltas fills in the change of the grade since the previous exam, and the change of the percentage
+// of every subject since the last exam that had the subject
+func setEvolutionDeltas(exams []*ExamEvolution) {
+	procente := make(map[string]float64)
+	for i, exam := range exams {
+		if i > 0 {
+			delta := statistics.Round(exam.Nota - exams[i-1].Nota)
+			exam.DeltaNota = &delta
+		}
+		for _, materie := range exam.Materii {
+			procent, found := procente[materie.Materie]
+			if found {
+				delta := statistics.Round(materie.Procent - procent)
+				materie.Delta = &delta
+			}
+			procente[materie.Materie] = materie.Procent
+		}
+	}
+}
+
+func percentOf(punctaj float64, punctajMaxim float64) float64 {
+	if punctajMaxim <= 0 {
+		return 0
+	}
+	return statistics.Round(punctaj * 100 / punctajMaxim)
+}
@@ -0,0 +1,95 @@
+package core
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dragos-rebegea/evaluare-tool/authentication"
+	"gorm.io/gorm"
+)
+
+// examTransitions lists the states an exam can move to from each state of its lifecycle. Grading can be
+// reopened until the results are released
+var examTransitions = map[string][]string{
+	ExamCiorna:             {ExamPublicat},
+	ExamPublicat:           {ExamNotareDeschisa},
+	ExamNotareDeschisa:     {ExamNotareInchisa},
+	ExamNotareInchisa:      {ExamNotareDeschisa, ExamRezultatePublicate},
+	ExamRezultatePublicate: {},
+}
+
+// SetExamStatus moves an exam to another state of its lifecycle and records who made the change
+func (db *databaseHandler) SetExamStatus(email string, request *ExamStatusRequest) (*authentication.TranzitieExam, error) {
+	_, known := examTransitions[request.Status]
+	if !known {
+		return nil, ErrInvalidExamStatus
+	}
+
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	var tranzitie *authentication.TranzitieExam
+	err := db.database.Transaction(func(tx *gorm.DB) error {
+		var exam authentication.Exam
+		record := tx.Where("nume = ?", request.Exam).First(&exam)
+		if record.Error != nil {
+			return record.Error
+		}
+		if !contains(examTransitions[exam.Status], request.Status) {
+			return fmt.Errorf("%w: %s -> %s", ErrInvalidExamTransition, exam.Status, request.Status)
+		}
+
+		dinStatus := exam.Status
+		record = tx.Model(&exam).Update("status", request.Status)
+		if record.Error != nil {
+			return record.Error
+		}
+		tranzitie = &authentication.TranzitieExam{
+			Exam:      exam.Nume,
+			DinStatus: dinStatus,
+			InStatus:  request.Status,
+			Admin:     email,
+			Motiv:     request.Motiv,
+		}
+		return tx.Create(tranzitie).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tranzitie, nil
+}
+
+// GetExamTranzitii returns the state changes of an exam, oldest first
+func (db *databaseHandler) GetExamTranzitii(exam string) ([]authentication.TranzitieExam, error) {
+	var tranzitii []authentication.TranzitieExam
+	record := db.database.Where("exam = ?", exam).Order("id").Find(&tranzitii)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+	return tranzitii, nil
+}
+
+// checkGradingWindow returns ErrGradingClosed if marks cannot be written in the provided slot at the given time.
+// Correctors and arbiters grade while grading is open and within the grading window, if one is set. The
+// re-evaluation of an appeal is accepted in any state once grading was opened
+func checkGradingWindow(exam *authentication.Exam, slot uint8, now time.Time) error {
+	if slot == SlotContestatie {
+		switch exam.Status {
+		case ExamNotareDeschisa, ExamNotareInchisa, ExamRezultatePublicate:
+			return nil
+		default:
+			return ErrGradingClosed
+		}
+	}
+
+	if exam.Status != ExamNotareDeschisa {
+		return ErrGradingClosed
+	}
+	if exam.InceputNotare != nil && now.Before(*exam.InceputNotare) {
+		return ErrGradingClosed
+	}
+	if exam.SfarsitNotare != nil && now.After(*exam.SfarsitNotare) {
+		return ErrGradingClosed
+	}
+	return nil
+}
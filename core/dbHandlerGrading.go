@@ -0,0 +1,222 @@
+package core
+
+import (
+	"errors"
+
+	"github.com/dragos-rebegea/evaluare-tool/authentication"
+	"github.com/dragos-rebegea/evaluare-tool/scoring"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AssignEvaluator assigns a profesor as first or second corrector for the papers of a class on one subject of
+// an exam. A profesor can hold only one of the two slots
+func (db *databaseHandler) AssignEvaluator(assignment *EvaluatorAssignment) error {
+	if assignment.Slot != SlotCorector1 && assignment.Slot != SlotCorector2 {
+		return ErrInvalidSlot
+	}
+
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	prof, err := db.GetProfesorByEmail(assignment.Profesor)
+	if err != nil {
+		return err
+	}
+
+	var class authentication.Clasa
+	record := db.database.Where("nume = ?", assignment.Clasa).First(&class)
+	if record.Error != nil {
+		return record.Error
+	}
+	var exam authentication.Exam
+	record = db.database.Where("nume = ?", assignment.Exam).First(&exam)
+	if record.Error != nil {
+		return record.Error
+	}
+
+	var other authentication.Evaluator
+	record = db.database.
+		Where("clasa = ? AND exam = ? AND materie = ? AND slot <> ?", assignment.Clasa, assignment.Exam, assignment.Materie, assignment.Slot).
+		Limit(1).
+		Find(&other)
+	if record.Error != nil {
+		return record.Error
+	}
+	if record.RowsAffected > 0 && other.Profesor == prof.ID {
+		return errors.New("profesorul este deja corector pentru aceasta clasa")
+	}
+
+	evaluator := authentication.Evaluator{
+		Clasa:    assignment.Clasa,
+		Exam:     assignment.Exam,
+		Materie:  assignment.Materie,
+		Slot:     assignment.Slot,
+		Profesor: prof.ID,
+	}
+	return db.database.Clauses(clause.OnConflict{UpdateAll: true}).Create(&evaluator).Error
+}
+
+// AssignArbiter assigns a profesor, other than the two correctors, to grade a paper awaiting arbitration
+func (db *databaseHandler) AssignArbiter(assignment *ArbiterAssignment) error {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	prof, err := db.GetProfesorByEmail(assignment.Profesor)
+	if err != nil {
+		return err
+	}
+
+	var arbitraj authentication.Arbitraj
+	record := db.database.
+		Where("student = ? AND exam = ? AND materie = ?", assignment.Student, assignment.Exam, assignment.Materie).
+		Limit(1).
+		Find(&arbitraj)
+	if record.Error != nil {
+		return record.Error
+	}
+	if record.RowsAffected == 0 || arbitraj.Status == ArbitrajRezolvat {
+		return ErrArbitrajNotFound
+	}
+
+	var corectori []uint
+	record = db.database.
+		Table("calificativs").
+		Where("student = ? AND exam = ? AND slot IN ?", assignment.Student, assignment.Exam, []int{int(SlotCorector1), int(SlotCorector2)}).
+		Where("exercitiu IN (?)", exercitiiMaterie(db.database, assignment.Exam, assignment.Materie)).
+		Distinct().
+		Pluck("profesor", &corectori)
+	if record.Error != nil {
+		return record.Error
+	}
+	for _, corector := range corectori {
+		if corector == prof.ID {
+			return errors.New("arbitrul trebuie sa fie diferit de corectori")
+		}
+	}
+
+	arbitraj.Profesor = prof.ID
+	arbitraj.Status = ArbitrajAlocat
+	return db.database.Save(&arbitraj).Error
+}
+
+// GetArbitraje returns the papers awaiting arbitration, either unassigned or not yet graded by their arbiter
+func (db *databaseHandler) GetArbitraje() ([]authentication.Arbitraj, error) {
+	var arbitraje []authentication.Arbitraj
+	record := db.database.
+		Where("status <> ?", ArbitrajRezolvat).
+		Order("exam, materie, student").
+		Find(&arbitraje)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+	return arbitraje, nil
+}
+
+// gradingSlot returns the slot in which the profesor grades the paper of a student on one subject of an exam.
+// Without an explicit assignment, the profesor of the class teaching the subject is the first corrector
+func (db *databaseHandler) gradingSlot(prof *authentication.Profesor, student *authentication.Student, exam string, materie string) (uint8, error) {
+	contestatie, err := findContestatie(db.database, student.ID, exam, materie)
+	if err != nil {
+		return 0, err
+	}
+	if contestatie != nil && contestatie.Profesor == prof.ID &&
+		(contestatie.Status == ContestatieAlocata || contestatie.Status == ContestatieReevaluata) {
+		return SlotContestatie, nil
+	}
+
+	var arbitraj authentication.Arbitraj
+	record := db.database.
+		Where("student = ? AND exam = ? AND materie = ? AND profesor = ?", student.ID, exam, materie, prof.ID).
+		Limit(1).
+		Find(&arbitraj)
+	if record.Error != nil {
+		return 0, record.Error
+	}
+	if record.RowsAffected > 0 && arbitraj.Status != ArbitrajInAsteptare {
+		return SlotArbitru, nil
+	}
+
+	var evaluators []authentication.Evaluator
+	record = db.database.Where("clasa = ? AND exam = ? AND materie = ?", student.Clasa, exam, materie).Find(&evaluators)
+	if record.Error != nil {
+		return 0, record.Error
+	}
+	corector1Assigned := false
+	for _, evaluator := range evaluators {
+		if evaluator.Profesor == prof.ID {
+			return evaluator.Slot, nil
+		}
+		if evaluator.Slot == SlotCorector1 {
+			corector1Assigned = true
+		}
+	}
+	if corector1Assigned {
+		return 0, ErrNotAnEvaluator
+	}
+
+	var class authentication.Clasa
+	record = db.database.Where("nume = ?", student.Clasa).First(&class)
+	if record.Error != nil {
+		return 0, record.Error
+	}
+	err = db.checkProfesor(prof, &class)
+	if err != nil || prof.Materie != materie {
+		return 0, ErrNotAnEvaluator
+	}
+	return SlotCorector1, nil
+}
+
+// exercitiiMaterie selects the numbers of the exercises of an exam on one subject, to be used as a subquery
+func exercitiiMaterie(tx *gorm.DB, exam string, materie string) *gorm.DB {
+	return tx.Model(&authentication.Exercitiu{}).Select("numar").Where("exam = ? AND materie = ?", exam, materie)
+}
+
+// updateArbitraj opens an arbitration when both correctors finished a subject and their grades differ by more
+// than the threshold of the exam, drops it while unassigned if they agree again, and resolves it once the
+// arbiter graded every exercise
+func updateArbitraj(
+	tx *gorm.DB,
+	studentId uint,
+	exam *authentication.Exam,
+	materie string,
+	corector1 *scoring.SubjectTotal,
+	corector2 *scoring.SubjectTotal,
+	arbitru *scoring.SubjectTotal,
+	rule scoring.GradeRule,
+) error {
+	if !scoring.IsComplete(corector1) || !scoring.IsComplete(corector2) {
+		return nil
+	}
+
+	var arbitraj authentication.Arbitraj
+	record := tx.Where("student = ? AND exam = ? AND materie = ?", studentId, exam.Nume, materie).Limit(1).Find(&arbitraj)
+	if record.Error != nil {
+		return record.Error
+	}
+	exists := record.RowsAffected > 0
+
+	nota1 := scoring.ComputeGrade(corector1.Punctaj, corector1.PunctajMaxim, rule)
+	nota2 := scoring.ComputeGrade(corector2.Punctaj, corector2.PunctajMaxim, rule)
+	if !scoring.NeedsArbitraj(nota1, nota2, exam.PragArbitraj) && (!exists || arbitraj.Status == ArbitrajInAsteptare) {
+		if !exists {
+			return nil
+		}
+		return tx.Delete(&arbitraj).Error
+	}
+
+	if !exists {
+		arbitraj = authentication.Arbitraj{
+			Student: studentId,
+			Exam:    exam.Nume,
+			Materie: materie,
+			Status:  ArbitrajInAsteptare,
+		}
+	}
+	arbitraj.Nota1 = nota1
+	arbitraj.Nota2 = nota2
+	if arbitraj.Status == ArbitrajAlocat && scoring.IsComplete(arbitru) {
+		arbitraj.Status = ArbitrajRezolvat
+	}
+	return tx.Save(&arbitraj).Error
+}
@@ -0,0 +1,89 @@
+package core
+
+import (
+	"time"
+
+	"github.com/dragos-rebegea/evaluare-tool/authentication"
+	"gorm.io/gorm"
+)
+
+// HasAdmin returns true if at least one user holds the admin role
+func (db *databaseHandler) HasAdmin() (bool, error) {
+	return hasAdmin(db.database)
+}
+
+func hasAdmin(tx *gorm.DB) (bool, error) {
+	var admins int64
+	record := tx.Model(&authentication.RolUtilizator{}).Where("rol = ?", authentication.RolAdmin).Count(&admins)
+	if record.Error != nil {
+		return false, record.Error
+	}
+	return admins > 0, nil
+}
+
+// CreateFirstAdmin registers an admin without an invitation. It works only while no admin exists, so the open
+// registration closes as soon as the school has an admin
+func (db *databaseHandler) CreateFirstAdmin(profesor *authentication.Profesor) error {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	return db.database.Transaction(func(tx *gorm.DB) error {
+		exists, err := hasAdmin(tx)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return ErrAdminExists
+		}
+		return createProfesor(tx, profesor, []string{authentication.RolAdmin})
+	})
+}
+
+// CreateInvitatie stores an invitation, which can be redeemed once before it expires
+func (db *databaseHandler) CreateInvitatie(invitatie *authentication.Invitatie) error {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	return db.database.Create(invitatie).Error
+}
+
+// RedeemInvitatie creates the profesor with the role of the invitation and marks the invitation as used. An
+// invitation issued for an email can only be redeemed by that email. A setup token, stored as an admin
+// invitation without an email, is refused once an admin exists, so later admins must be invited
+func (db *databaseHandler) RedeemInvitatie(id string, profesor *authentication.Profesor) error {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	return db.database.Transaction(func(tx *gorm.DB) error {
+		var invitatie authentication.Invitatie
+		record := tx.Where("id = ? AND used_at IS NULL AND expires_at > ?", id, time.Now()).Limit(1).Find(&invitatie)
+		if record.Error != nil {
+			return record.Error
+		}
+		if record.RowsAffected == 0 {
+			return ErrInvalidInvitatie
+		}
+		if len(invitatie.Email) > 0 && invitatie.Email != profesor.Email {
+			return ErrInvalidInvitatie
+		}
+		if len(invitatie.Email) == 0 && invitatie.Rol == authentication.RolAdmin {
+			exists, err := hasAdmin(tx)
+			if err != nil {
+				return err
+			}
+			if exists {
+				return ErrAdminExists
+			}
+		}
+
+		now := time.Now()
+		record = tx.Model(&invitatie).Where("used_at IS NULL").Update("used_at", &now)
+		if record.Error != nil {
+			return record.Error
+		}
+		if record.RowsAffected == 0 {
+			return ErrInvalidInvitatie
+		}
+		return createProfesor(tx, profesor, []string{invitatie.Rol})
+	})
+}
@@ -0,0 +1,89 @@
+package core
+
+import (
+	"github.com/dragos-rebegea/evaluare-tool/scoring"
+	"github.com/dragos-rebegea/evaluare-tool/statistics"
+)
+
+// itemAnalysisGroupFraction is the share of the students placed in the upper and in the lower group when
+// computing the discrimination index
+const itemAnalysisGroupFraction = 0.27
+
+// GetItemAnalysis returns the item analysis of an exam, computed from the final corrections of the present students.
+// Each exercise is compared with the total on its subject: the discrimination index ranks students by that
+// total, while the point-biserial correlation uses the total without the exercise itself
+func (db *databaseHandler) GetItemAnalysis(exam string) (*ItemAnalysis, error) {
+	students, err := loadExamStudents(db.database, exam)
+	if err != nil {
+		return nil, err
+	}
+	schemes, numeVariante, err := loadExamStructure(db.database, exam)
+	if err != nil {
+		return nil, err
+	}
+	finale, err := loadFinalExercitii(db.database, exam, students, schemes)
+	if err != nil {
+		return nil, err
+	}
+
+	totals := make(map[string]map[uint]float64)
+	for _, student := range students {
+		for _, scheme := range schemes {
+			exercitiu, graded := finale[student][scheme.Numar]
+			if !graded {
+				continue
+			}
+			_, ok := totals[scheme.Materie]
+			if !ok {
+				totals[scheme.Materie] = make(map[uint]float64)
+			}
+			totals[scheme.Materie][student] += exercitiu.puncte
+		}
+	}
+
+	result := &ItemAnalysis{
+		Exam:      exam,
+		Elevi:     len(students),
+		Exercitii: make([]*ItemStatistics, 0, len(schemes)),
+	}
+	for _, scheme := range schemes {
+		counts := make(map[string]int)
+		notate := 0
+		puncte := make([]float64, 0, len(totals[scheme.Materie]))
+		totaluri := make([]float64, 0, len(totals[scheme.Materie]))
+		restul := make([]float64, 0, len(totals[scheme.Materie]))
+		for _, student := range students {
+			total, ok := totals[scheme.Materie][student]
+			if !ok {
+				continue
+			}
+
+			points := 0.0
+			exercitiu, graded := finale[student][scheme.Numar]
+			if graded {
+				for _, varianta := range exercitiu.variante {
+					counts[varianta]++
+				}
+				notate++
+				points = exercitiu.puncte
+			}
+			puncte = append(puncte, points)
+			totaluri = append(totaluri, total)
+			restul = append(restul, total-points)
+		}
+
+		maxim := scoring.MaxPoints(scheme)
+		result.Exercitii = append(result.Exercitii, &ItemStatistics{
+			Numar:         scheme.Numar,
+			Materie:       scheme.Materie,
+			Sectiune:      scheme.Sectiune,
+			Elevi:         len(puncte),
+			PunctajMaxim:  maxim,
+			Facilitate:    statistics.Round(statistics.FacilityIndex(puncte, maxim)),
+			Discriminare:  statistics.Round(statistics.DiscriminationIndex(puncte, totaluri, maxim, itemAnalysisGroupFraction)),
+			PunctBiserial: statistics.Round(statistics.Correlation(puncte, restul)),
+			Variante:      variantaShares(numeVariante[scheme.Numar], counts, notate),
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,441 @@
+package core
+
+import (
+	"sort"
+
+	"github.com/dragos-rebegea/evaluare-tool/authentication"
+)
+
+// gradingTask is the paper of a student on one subject of an exam that a profesor grades in one slot
+type gradingTask struct {
+	profesor  uint
+	student   *authentication.Student
+	exam      string
+	materie   string
+	slot      uint8
+	exercitii []string
+	notate    map[string]bool
+}
+
+type calificativKey struct {
+	student uint
+	exam    string
+	slot    uint8
+}
+
+type evaluatorKey struct {
+	exam    string
+	materie string
+	slot    uint8
+}
+
+type paperKey struct {
+	student uint
+	exam    string
+	materie string
+}
+
+// GetClassProgress returns the grading progress of a class on an exam, per profesor, per exercise and the
+// papers that still have ungraded exercises. Absent students are skipped
+func (db *databaseHandler) GetClassProgress(clasa string, exam string) (*GradingProgress, error) {
+	var class authentication.Clasa
+	record := db.database.Where("nume = ?", clasa).First(&class)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+
+	tasks, err := db.classGradingTasks(&class)
+	if err != nil {
+		return nil, err
+	}
+	emails, err := db.loadProfesorEmails()
+	if err != nil {
+		return nil, err
+	}
+
+	examTasks := make([]*gradingTask, 0, len(tasks))
+	for _, task := range tasks {
+		if task.exam == exam {
+			examTasks = append(examTasks, task)
+		}
+	}
+
+	return &GradingProgress{
+		Clasa:             clasa,
+		Exam:              exam,
+		Profesori:         aggregateProfesorProgress(examTasks, emails, class.Nume),
+		Exercitii:         aggregateExercitiuProgress(examTasks),
+		LucrariIncomplete: incompleteTasks(examTasks, emails),
+	}, nil
+}
+
+// GetProfesorProgress returns the grading progress of a profesor in every class and slot they grade
+func (db *databaseHandler) GetProfesorProgress(email string) ([]*ProfesorProgress, error) {
+	prof, err := db.GetProfesorByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+
+	var classes []authentication.Clasa
+	record := db.database.Order("nume").Find(&classes)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+	emails := map[uint]string{prof.ID: prof.Email}
+
+	progress := make([]*ProfesorProgress, 0)
+	for i := range classes {
+		tasks, errTasks := db.classGradingTasks(&classes[i])
+		if errTasks != nil {
+			return nil, errTasks
+		}
+
+		profTasks := make([]*gradingTask, 0)
+		for _, task := range tasks {
+			if task.profesor == prof.ID {
+				profTasks = append(profTasks, task)
+			}
+		}
+		progress = append(progress, aggregateProfesorProgress(profTasks, emails, classes[i].Nume)...)
+	}
+	return progress, nil
+}
+
+// classGradingTasks returns the papers of the students of a class that each profesor has to grade, following
+// the same rules used to decide the grading slot of a profesor
+func (db *databaseHandler) classGradingTasks(class *authentication.Clasa) ([]*gradingTask, error) {
+	var students []authentication.Student
+	record := db.database.Where("clasa = ?", class.Nume).Order("id").Find(&students)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+	if len(students) == 0 {
+		return make([]*gradingTask, 0), nil
+	}
+	studentIds := make([]uint, 0, len(students))
+	for _, student := range students {
+		studentIds = append(studentIds, student.ID)
+	}
+
+	profesoriClasa, err := db.loadClassProfesori(class)
+	if err != nil {
+		return nil, err
+	}
+	evaluatori, err := db.loadEvaluatori(class.Nume)
+	if err != nil {
+		return nil, err
+	}
+	notate, err := db.loadNotate(studentIds)
+	if err != nil {
+		return nil, err
+	}
+	reevaluatori, err := db.loadReevaluatori(studentIds)
+	if err != nil {
+		return nil, err
+	}
+	absente, err := loadAbsente(db.database, studentIds)
+	if err != nil {
+		return nil, err
+	}
+
+	exercitiiByExam := make(map[string]map[string][]string)
+	tasks := make([]*gradingTask, 0)
+	for i := range students {
+		student := &students[i]
+		for _, examTip := range [][2]string{{student.ExamStiinta, "stiinta"}, {student.ExamLimba, "limba"}} {
+			exam, tip := examTip[0], examTip[1]
+			if len(exam) == 0 || absente[absentaKey{student: student.ID, exam: exam}] != nil {
+				continue
+			}
+
+			exercitii, ok := exercitiiByExam[exam]
+			if !ok {
+				exercitii, err = db.loadExercitiiByMaterie(exam)
+				if err != nil {
+					return nil, err
+				}
+				exercitiiByExam[exam] = exercitii
+			}
+
+			for materie, numere := range exercitii {
+				if getTypeByMaterie(materie) != tip {
+					continue
+				}
+
+				responsabili := make(map[uint8][]uint)
+				corector1, assigned := evaluatori[evaluatorKey{exam: exam, materie: materie, slot: SlotCorector1}]
+				if assigned {
+					responsabili[SlotCorector1] = []uint{corector1}
+				} else {
+					responsabili[SlotCorector1] = profesoriClasa[materie]
+				}
+				corector2, assigned := evaluatori[evaluatorKey{exam: exam, materie: materie, slot: SlotCorector2}]
+				if assigned {
+					responsabili[SlotCorector2] = []uint{corector2}
+				}
+				for slot, profesor := range reevaluatori[paperKey{student: student.ID, exam: exam, materie: materie}] {
+					responsabili[slot] = []uint{profesor}
+				}
+
+				for slot, profesori := range responsabili {
+					for _, profesor := range profesori {
+						tasks = append(tasks, &gradingTask{
+							profesor:  profesor,
+							student:   student,
+							exam:      exam,
+							materie:   materie,
+							slot:      slot,
+							exercitii: numere,
+							notate:    notate[calificativKey{student: student.ID, exam: exam, slot: slot}],
+						})
+					}
+				}
+			}
+		}
+	}
+	return tasks, nil
+}
+
+// loadClassProfesori returns the ids of the profesori of a class, indexed by the subject they teach
+func (db *databaseHandler) loadClassProfesori(class *authentication.Clasa) (map[string][]uint, error) {
+	usernames := []string{class.ProfMate, class.ProfFizica, class.ProfBio, class.ProfRomana, class.ProfEngleza}
+	var profesori []authentication.Profesor
+	record := db.database.Where("username IN ?", usernames).Find(&profesori)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+
+	result := make(map[string][]uint)
+	for _, prof := range profesori {
+		result[prof.Materie] = append(result[prof.Materie], prof.ID)
+	}
+	return result, nil
+}
+
+func (db *databaseHandler) loadEvaluatori(clasa string) (map[evaluatorKey]uint, error) {
+	var evaluatori []authentication.Evaluator
+	record := db.database.Where("clasa = ?", clasa).Find(&evaluatori)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+
+	result := make(map[evaluatorKey]uint, len(evaluatori))
+	for _, evaluator := range evaluatori {
+		result[evaluatorKey{exam: evaluator.Exam, materie: evaluator.Materie, slot: evaluator.Slot}] = evaluator.Profesor
+	}
+	return result, nil
+}
+
+func (db *databaseHandler) loadNotate(studentIds []uint) (map[calificativKey]map[string]bool, error) {
+	var calificative []authentication.Calificativ
+	record := db.database.Where("student IN ?", studentIds).Find(&calificative)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+
+	result := make(map[calificativKey]map[string]bool)
+	for _, calificativ := range calificative {
+		key := calificativKey{student: calificativ.Student, exam: calificativ.Exam, slot: calificativ.Slot}
+		_, ok := result[key]
+		if !ok {
+			result[key] = make(map[string]bool)
+		}
+		result[key][calificativ.Exercitiu] = true
+	}
+	return result, nil
+}
+
+// loadReevaluatori returns the arbiters and the profesori re-evaluating appeals, indexed by paper and slot
+func (db *databaseHandler) loadReevaluatori(studentIds []uint) (map[paperKey]map[uint8]uint, error) {
+	result := make(map[paperKey]map[uint8]uint)
+	add := func(key paperKey, slot uint8, profesor uint) {
+		_, ok := result[key]
+		if !ok {
+			result[key] = make(map[uint8]uint)
+		}
+		result[key][slot] = profesor
+	}
+
+	var arbitraje []authentication.Arbitraj
+	record := db.database.Where("student IN ? AND status <> ?", studentIds, ArbitrajInAsteptare).Find(&arbitraje)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+	for _, arbitraj := range arbitraje {
+		add(paperKey{student: arbitraj.Student, exam: arbitraj.Exam, materie: arbitraj.Materie}, SlotArbitru, arbitraj.Profesor)
+	}
+
+	var contestatii []authentication.Contestatie
+	record = db.database.Where("student IN ? AND status <> ?", studentIds, ContestatieDepusa).Find(&contestatii)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+	for _, contestatie := range contestatii {
+		add(paperKey{student: contestatie.Student, exam: contestatie.Exam, materie: contestatie.Materie}, SlotContestatie, contestatie.Profesor)
+	}
+	return result, nil
+}
+
+// loadExercitiiByMaterie returns the exercise numbers of an exam, indexed by subject
+func (db *databaseHandler) loadExercitiiByMaterie(exam string) (map[string][]string, error) {
+	var exercitii []authentication.Exercitiu
+	record := db.database.Where("exam = ?", exam).Order("numar").Find(&exercitii)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+
+	result := make(map[string][]string)
+	for _, exercitiu := range exercitii {
+		result[exercitiu.Materie] = append(result[exercitiu.Materie], exercitiu.Numar)
+	}
+	return result, nil
+}
+
+func (db *databaseHandler) loadProfesorEmails() (map[uint]string, error) {
+	var profesori []authentication.Profesor
+	record := db.database.Find(&profesori)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+
+	result := make(map[uint]string, len(profesori))
+	for _, prof := range profesori {
+		result[prof.ID] = prof.Email
+	}
+	return result, nil
+}
+
+func (task *gradingTask) missing() []string {
+	lipsa := make([]string, 0)
+	for _, numar := range task.exercitii {
+		if !task.notate[numar] {
+			lipsa = append(lipsa, numar)
+		}
+	}
+	return lipsa
+}
+
+func aggregateProfesorProgress(tasks []*gradingTask, emails map[uint]string, clasa string) []*ProfesorProgress {
+	type progressKey struct {
+		profesor uint
+		exam     string
+		materie  string
+		slot     uint8
+	}
+
+	byKey := make(map[progressKey]*ProfesorProgress)
+	for _, task := range tasks {
+		key := progressKey{profesor: task.profesor, exam: task.exam, materie: task.materie, slot: task.slot}
+		progress, ok := byKey[key]
+		if !ok {
+			progress = &ProfesorProgress{
+				Profesor: emails[task.profesor],
+				Clasa:    clasa,
+				Exam:     task.exam,
+				Materie:  task.materie,
+				Slot:     task.slot,
+			}
+			byKey[key] = progress
+		}
+
+		lipsa := len(task.missing())
+		progress.Elevi++
+		if lipsa == 0 {
+			progress.EleviFinalizati++
+		}
+		progress.ExercitiiTotal += len(task.exercitii)
+		progress.ExercitiiNotate += len(task.exercitii) - lipsa
+	}
+
+	result := make([]*ProfesorProgress, 0, len(byKey))
+	for _, progress := range byKey {
+		result = append(result, progress)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Exam != result[j].Exam {
+			return result[i].Exam < result[j].Exam
+		}
+		if result[i].Materie != result[j].Materie {
+			return result[i].Materie < result[j].Materie
+		}
+		if result[i].Slot != result[j].Slot {
+			return result[i].Slot < result[j].Slot
+		}
+		return result[i].Profesor < result[j].Profesor
+	})
+	return result
+}
+
+func aggregateExercitiuProgress(tasks []*gradingTask) []*ExercitiuProgress {
+	type progressKey struct {
+		exam  string
+		numar string
+	}
+
+	byKey := make(map[progressKey]*ExercitiuProgress)
+	for _, task := range tasks {
+		for _, numar := range task.exercitii {
+			key := progressKey{exam: task.exam, numar: numar}
+			progress, ok := byKey[key]
+			if !ok {
+				progress = &ExercitiuProgress{
+					Exam:    task.exam,
+					Numar:   numar,
+					Materie: task.materie,
+				}
+				byKey[key] = progress
+			}
+
+			progress.Total++
+			if task.notate[numar] {
+				progress.Notate++
+			}
+		}
+	}
+
+	result := make([]*ExercitiuProgress, 0, len(byKey))
+	for _, progress := range byKey {
+		result = append(result, progress)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Exam != result[j].Exam {
+			return result[i].Exam < result[j].Exam
+		}
+		if result[i].Materie != result[j].Materie {
+			return result[i].Materie < result[j].Materie
+		}
+		return result[i].Numar < result[j].Numar
+	})
+	return result
+}
+
+func incompleteTasks(tasks []*gradingTask, emails map[uint]string) []*StudentProgress {
+	result := make([]*StudentProgress, 0)
+	for _, task := range tasks {
+		lipsa := task.missing()
+		if len(lipsa) == 0 {
+			continue
+		}
+
+		result = append(result, &StudentProgress{
+			Student:  task.student.ID,
+			Nume:     task.student.Nume,
+			Prenume:  task.student.Prenume,
+			Exam:     task.exam,
+			Materie:  task.materie,
+			Slot:     task.slot,
+			Profesor: emails[task.profesor],
+			Lipsa:    lipsa,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Student != result[j].Student {
+			return result[i].Student < result[j].Student
+		}
+		if result[i].Materie != result[j].Materie {
+			return result[i].Materie < result[j].Materie
+		}
+		return result[i].Slot < result[j].Slot
+	})
+	return result
+}
@@ -0,0 +1,118 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/dragos-rebegea/evaluare-tool/authentication"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetRoluri returns the roles assigned to a user. An empty email selects the roles of every user
+func (db *databaseHandler) GetRoluri(email string) ([]authentication.RolUtilizator, error) {
+	query := db.database.Order("email, rol, clasa")
+	if len(email) > 0 {
+		query = query.Where("email = ?", email)
+	}
+	roluri := make([]authentication.RolUtilizator, 0)
+	record := query.Find(&roluri)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+	return roluri, nil
+}
+
+// AssignRol assigns a role to a profesor. Assigning a role the profesor already holds has no effect. The new
+// role is carried by the access tokens issued after the next login or refresh
+func (db *databaseHandler) AssignRol(request *RolRequest) error {
+	err := db.checkRolRequest(request)
+	if err != nil {
+		return err
+	}
+
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	record := db.database.Clauses(clause.OnConflict{DoNothing: true}).Create(&authentication.RolUtilizator{
+		Email: request.Email,
+		Rol:   request.Rol,
+		Clasa: request.Clasa,
+	})
+	return record.Error
+}
+
+// RevokeRol removes a role of a profesor. The last admin cannot be revoked, so the school is never left without
+// someone able to assign roles
+func (db *databaseHandler) RevokeRol(request *RolRequest) error {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	return db.database.Transaction(func(tx *gorm.DB) error {
+		if request.Rol == authentication.RolAdmin {
+			var admins int64
+			record := tx.Model(&authentication.RolUtilizator{}).
+				Where("rol = ? AND email <> ?", authentication.RolAdmin, request.Email).
+				Count(&admins)
+			if record.Error != nil {
+				return record.Error
+			}
+			if admins == 0 {
+				return ErrLastAdmin
+			}
+		}
+
+		record := tx.Where("email = ? AND rol = ? AND clasa = ?", request.Email, request.Rol, request.Clasa).
+			Delete(&authentication.RolUtilizator{})
+		if record.Error != nil {
+			return record.Error
+		}
+		if record.RowsAffected == 0 {
+			return ErrRolNotFound
+		}
+		return nil
+	})
+}
+
+func (db *databaseHandler) checkRolRequest(request *RolRequest) error {
+	if !authentication.IsProfesorRol(request.Rol) {
+		return fmt.Errorf("%w: %s", ErrInvalidRol, request.Rol)
+	}
+	if (request.Rol == authentication.RolDiriginte) != (len(request.Clasa) > 0) {
+		return fmt.Errorf("%w: clasa se precizeaza doar pentru diriginte", ErrInvalidRol)
+	}
+
+	_, err := db.GetProfesorByEmail(request.Email)
+	if err != nil {
+		return err
+	}
+	if len(request.Clasa) == 0 {
+		return nil
+	}
+	var class authentication.Clasa
+	return db.database.Where("nume = ?", request.Clasa).First(&class).Error
+}
+
+// canAccessClass returns true if the roles of the user grant access to the data of every class, or if the user
+// is the diriginte of the provided class
+func (db *databaseHandler) canAccessClass(email string, clasa string) (bool, error) {
+	all, diriginte, err := db.classScope(email)
+	return all || diriginte[clasa], err
+}
+
+// classScope returns whether the roles of the user grant access to the data of every class, and the classes the
+// user is diriginte of
+func (db *databaseHandler) classScope(email string) (bool, map[string]bool, error) {
+	roluri, err := db.GetRoluri(email)
+	if err != nil {
+		return false, nil, err
+	}
+
+	all := authentication.HasPermission(authentication.RoleNames(roluri), authentication.PermClassReadAll)
+	diriginte := make(map[string]bool)
+	for _, rol := range roluri {
+		if rol.Rol == authentication.RolDiriginte {
+			diriginte[rol.Clasa] = true
+		}
+	}
+	return all, diriginte, nil
+}
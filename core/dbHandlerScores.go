@@ -0,0 +1,370 @@
+package core
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/dragos-rebegea/evaluare-tool/authentication"
+	"github.com/dragos-rebegea/evaluare-tool/scoring"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetStudentScore returns the stored totals of a student, together with their subtotals on the sections of each
+// exam, if the profesor teaches the student's class. Until a paper is final, a corrector sees only the total of
+// their own slot and the other profesori do not see it at all, unless they manage the grading. Likewise, the
+// comments of the other profesori are shown only once the results of the exam are released
+func (db *databaseHandler) GetStudentScore(email string, studentId string) (*StudentScore, error) {
+	id, err := db.checkStudentAccess(email, studentId)
+	if err != nil {
+		return nil, err
+	}
+	visibility, err := db.newScoreVisibility(email)
+	if err != nil {
+		return nil, err
+	}
+
+	var scores []authentication.Scor
+	record := db.database.Where("student = ?", id).Order("exam, materie").Find(&scores)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+	scores, partial, err := visibility.filter(scores)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &StudentScore{
+		Student: id,
+		Materii: make([]*SubjectScore, 0, len(scores)),
+	}
+	lastExam := ""
+	for _, score := range scores {
+		result.Materii = append(result.Materii, &SubjectScore{
+			Exam:         score.Exam,
+			Materie:      score.Materie,
+			Punctaj:      score.Punctaj,
+			PunctajMaxim: score.PunctajMaxim,
+		})
+
+		if score.Exam == lastExam || partial[score.Exam] {
+			continue
+		}
+		lastExam = score.Exam
+		sectiuni, errSectiuni := computeStudentSections(db.database, id, score.Exam)
+		if errSectiuni != nil {
+			return nil, errSectiuni
+		}
+		if sectiuni != nil {
+			result.Sectiuni = append(result.Sectiuni, &ExamSections{Exam: score.Exam, Sectiuni: sectiuni})
+		}
+	}
+
+	query := db.database.Where("student = ?", id)
+	if !visibility.all {
+		released := db.database.Model(&authentication.Exam{}).Select("nume").Where("status = ?", ExamRezultatePublicate)
+		query = query.Where("(profesor = ? OR exam IN (?))", visibility.prof.ID, released)
+	}
+	result.Comentarii, err = loadComentarii(query)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetStudentGrades returns the grades of a student for the exams assigned to them, and their average once
+// every exam has been graded. The grade of an exam is left out while the profesor cannot see the total of
+// one of its subjects
+func (db *databaseHandler) GetStudentGrades(email string, studentId string) (*StudentGrades, error) {
+	id, err := db.checkStudentAccess(email, studentId)
+	if err != nil {
+		return nil, err
+	}
+	visibility, err := db.newScoreVisibility(email)
+	if err != nil {
+		return nil, err
+	}
+
+	return computeStudentGrades(db.database, id, visibility)
+}
+
+// checkStudentAccess parses the student id and checks that the profesor teaches or leads the student's class.
+// Admins and directors can access every student
+func (db *databaseHandler) checkStudentAccess(email string, studentId string) (uint, error) {
+	prof, err := db.GetProfesorByEmail(email)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := strconv.ParseUint(studentId, 10, 64)
+	if err != nil {
+		return 0, errors.New("student invalid")
+	}
+	class, err := db.GetClassByID(uint(id))
+	if err != nil {
+		return 0, err
+	}
+	allowed, err := db.canAccessClass(email, class.Nume)
+	if err != nil {
+		return 0, err
+	}
+	if !allowed {
+		err = db.checkProfesor(prof, class)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return uint(id), nil
+}
+
+// computeStudentGrades converts the stored totals of a student into grades, using the rule of each exam.
+// The average is truncated unless every exam asks for mathematical rounding
+func computeStudentGrades(tx *gorm.DB, studentId uint, visibility *scoreVisibility) (*StudentGrades, error) {
+	var student authentication.Student
+	record := tx.Where("id = ?", studentId).First(&student)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+
+	result := &StudentGrades{
+		Student: studentId,
+		Note:    make([]*ExamGrade, 0, 2),
+	}
+	exams := studentExamNames(&student)
+
+	note := make([]float64, 0, len(exams))
+	rotunjireMedie := scoring.RotunjireMatematica
+	for _, examName := range exams {
+		var exam authentication.Exam
+		record = tx.Where("nume = ?", examName).First(&exam)
+		if record.Error != nil {
+			return nil, record.Error
+		}
+
+		var scores []authentication.Scor
+		record = tx.Where("student = ? AND exam = ?", studentId, examName).Find(&scores)
+		if record.Error != nil {
+			return nil, record.Error
+		}
+		if len(scores) == 0 {
+			continue
+		}
+		scores, _, err := visibility.paper(scores)
+		if err != nil {
+			return nil, err
+		}
+		if scores == nil {
+			continue
+		}
+
+		grade := examGrade(&exam, scores)
+		comentarii, err := loadComentariiGenerale(tx, studentId, examName)
+		if err != nil {
+			return nil, err
+		}
+		grade.Comentarii = visibility.comentarii(&exam, comentarii)
+		result.Note = append(result.Note, grade)
+
+		note = append(note, grade.Nota)
+		if exam.Rotunjire != scoring.RotunjireMatematica {
+			rotunjireMedie = scoring.RotunjireTrunchiere
+		}
+	}
+
+	if len(exams) > 0 && len(note) == len(exams) {
+		media := scoring.AverageGrade(note, rotunjireMedie)
+		result.Media = &media
+	}
+	return result, nil
+}
+
+// examGrade sums the totals of a student on the subjects of an exam and converts them into a grade
+func examGrade(exam *authentication.Exam, scores []authentication.Scor) *ExamGrade {
+	grade := &ExamGrade{
+		Exam:         exam.Nume,
+		PuncteOficiu: exam.PuncteOficiu,
+	}
+	for _, score := range scores {
+		grade.Punctaj += score.Punctaj
+		grade.PunctajMaxim += score.PunctajMaxim
+	}
+	rule := scoring.GradeRule{
+		PuncteOficiu: exam.PuncteOficiu,
+		Rotunjire:    exam.Rotunjire,
+	}
+	grade.Nota = scoring.ComputeGrade(grade.Punctaj, grade.PunctajMaxim, rule)
+	return grade
+}
+
+// loadExerciseSchemes returns the scoring schemes of the exercises of an exam. An empty materie selects all subjects
+func loadExerciseSchemes(tx *gorm.DB, exam string, materie string) ([]scoring.ExerciseScheme, error) {
+	query := tx.Where("exam = ?", exam)
+	if len(materie) > 0 {
+		query = query.Where("materie = ?", materie)
+	}
+	var exercitii []authentication.Exercitiu
+	record := query.Find(&exercitii)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+
+	var variante []authentication.VariantaExercitiu
+	record = tx.Where("exam = ?", exam).Find(&variante)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+	puncte := make(map[string]map[string]float64)
+	for _, varianta := range variante {
+		_, ok := puncte[varianta.Exercitiu]
+		if !ok {
+			puncte[varianta.Exercitiu] = make(map[string]float64)
+		}
+		puncte[varianta.Exercitiu][varianta.Nume] = varianta.Puncte
+	}
+
+	competente, err := loadExercitiuCompetente(tx, exam)
+	if err != nil {
+		return nil, err
+	}
+
+	schemes := make([]scoring.ExerciseScheme, 0, len(exercitii))
+	for _, exercitiu := range exercitii {
+		schemes = append(schemes, scoring.ExerciseScheme{
+			Numar:      exercitiu.Numar,
+			Materie:    exercitiu.Materie,
+			Puncte:     puncte[exercitiu.Numar],
+			Maxim:      exercitiu.PunctajMaxim,
+			Pondere:    exercitiu.Pondere,
+			Sectiune:   exercitiu.Sectiune,
+			Competente: competente[exercitiu.Numar],
+		})
+	}
+	return schemes, nil
+}
+
+// loadChosenVariante returns the variants recorded for a student on an exam in one grading slot, indexed by
+// exercise number
+func loadChosenVariante(tx *gorm.DB, studentId uint, exam string, slot uint8) (map[string]string, error) {
+	var calificative []*Calificativ
+	record := tx.
+		Table("calificativs").
+		Where("student = ? AND exam = ? AND slot = ?", studentId, exam, slot).
+		Scan(&calificative)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+
+	variante := make(map[string]string, len(calificative))
+	for _, calificativ := range calificative {
+		variante[calificativ.Exercitiu] = calificativ.Varianta
+	}
+	return variante, nil
+}
+
+// loadSlotTotals returns the totals per subject recorded in one grading slot, or nil if the slot has no marks
+func loadSlotTotals(tx *gorm.DB, schemes []scoring.ExerciseScheme, studentId uint, exam string, slot uint8) (map[string]*scoring.SubjectTotal, error) {
+	variante, err := loadChosenVariante(tx, studentId, exam, slot)
+	if err != nil {
+		return nil, err
+	}
+	if len(variante) == 0 {
+		return nil, nil
+	}
+
+	totals := make(map[string]*scoring.SubjectTotal)
+	for _, total := range scoring.ComputeSubjectTotals(schemes, variante) {
+		totals[total.Materie] = total
+	}
+	return totals, nil
+}
+
+// recomputeScore stores the total of a student on one subject of an exam. The marks of the two correctors and
+// of the arbiter are combined, and an arbitration is opened when the correctors disagree. A resolved appeal in
+// favour of the re-evaluation replaces the combined total
+func recomputeScore(tx *gorm.DB, studentId uint, exam string, materie string) error {
+	var examDb authentication.Exam
+	record := tx.Where("nume = ?", exam).First(&examDb)
+	if record.Error != nil {
+		return record.Error
+	}
+	rule := scoring.GradeRule{
+		PuncteOficiu: examDb.PuncteOficiu,
+		Rotunjire:    examDb.Rotunjire,
+	}
+
+	schemes, err := loadExerciseSchemes(tx, exam, materie)
+	if err != nil {
+		return err
+	}
+	totalsBySlot := make(map[uint8]map[string]*scoring.SubjectTotal)
+	for _, slot := range []uint8{SlotCorector1, SlotCorector2, SlotArbitru, SlotContestatie} {
+		totalsBySlot[slot], err = loadSlotTotals(tx, schemes, studentId, exam, slot)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, empty := range scoring.ComputeSubjectTotals(schemes, nil) {
+		corector1 := totalsBySlot[SlotCorector1][empty.Materie]
+		corector2 := totalsBySlot[SlotCorector2][empty.Materie]
+		arbitru := totalsBySlot[SlotArbitru][empty.Materie]
+
+		err = updateArbitraj(tx, studentId, &examDb, empty.Materie, corector1, corector2, arbitru, rule)
+		if err != nil {
+			return err
+		}
+
+		reevaluare := totalsBySlot[SlotContestatie][empty.Materie]
+		aplicata, err := updateContestatie(tx, studentId, exam, empty.Materie, reevaluare, rule)
+		if err != nil {
+			return err
+		}
+
+		total := scoring.CombineCorrections(corector1, corector2, arbitru)
+		if aplicata {
+			total = reevaluare
+		}
+		if total == nil {
+			total = empty
+		}
+		scor := authentication.Scor{
+			Student:      studentId,
+			Exam:         exam,
+			Materie:      total.Materie,
+			Punctaj:      total.Punctaj,
+			PunctajMaxim: total.PunctajMaxim,
+		}
+		record = tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(&scor)
+		if record.Error != nil {
+			return record.Error
+		}
+	}
+	return nil
+}
+
+// recomputeExamScores stores the totals of every student graded on an exam, after its points changed
+func recomputeExamScores(tx *gorm.DB, exam string) error {
+	var students []uint
+	record := tx.Table("calificativs").Where("exam = ?", exam).Distinct().Pluck("student", &students)
+	if record.Error != nil {
+		return record.Error
+	}
+
+	for _, studentId := range students {
+		err := recomputeScore(tx, studentId, exam, "")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadVariante returns the variants of an exercise in the order set by the exam
+func loadVariante(tx *gorm.DB, exam string, numar string) ([]authentication.VariantaExercitiu, error) {
+	var variante []authentication.VariantaExercitiu
+	record := tx.Where("exam = ? AND exercitiu = ?", exam, numar).Order("ordine").Find(&variante)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+	return variante, nil
+}
@@ -0,0 +1,222 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/dragos-rebegea/evaluare-tool/authentication"
+	"github.com/dragos-rebegea/evaluare-tool/scoring"
+	"github.com/dragos-rebegea/evaluare-tool/statistics"
+	"gorm.io/gorm"
+)
+
+func toScoringSections(sectiuni []Sectiune) []scoring.Section {
+	sections := make([]scoring.Section, 0, len(sectiuni))
+	for _, sectiune := range sectiuni {
+		sections = append(sections, scoring.Section{
+			Cod:     sectiune.Cod,
+			Titlu:   sectiune.Titlu,
+			Parinte: sectiune.Parinte,
+		})
+	}
+	return sections
+}
+
+// replaceSectiuni stores the tree of sections of an exam, keeping the order in which they were provided
+func replaceSectiuni(tx *gorm.DB, exam string, sectiuni []Sectiune) error {
+	record := tx.Where("exam = ?", exam).Delete(&authentication.Sectiune{})
+	if record.Error != nil {
+		return record.Error
+	}
+	for idx, sectiune := range sectiuni {
+		record = tx.Create(&authentication.Sectiune{
+			Exam:    exam,
+			Cod:     sectiune.Cod,
+			Titlu:   sectiune.Titlu,
+			Parinte: sectiune.Parinte,
+			Ordine:  idx,
+		})
+		if record.Error != nil {
+			return record.Error
+		}
+	}
+	return nil
+}
+
+// loadSections returns the tree of sections of an exam in the order set by the exam
+func loadSections(tx *gorm.DB, exam string) ([]scoring.Section, error) {
+	var sectiuni []authentication.Sectiune
+	record := tx.Where("exam = ?", exam).Order("ordine").Find(&sectiuni)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+
+	sections := make([]scoring.Section, 0, len(sectiuni))
+	for _, sectiune := range sectiuni {
+		sections = append(sections, scoring.Section{
+			Cod:     sectiune.Cod,
+			Titlu:   sectiune.Titlu,
+			Parinte: sectiune.Parinte,
+		})
+	}
+	return sections, nil
+}
+
+// checkExercitiuSectiuni checks that every exercise of an exam placed in a section references an existing one
+func checkExercitiuSectiuni(tx *gorm.DB, exam string) error {
+	var coduri []string
+	record := tx.Model(&authentication.Sectiune{}).Where("exam = ?", exam).Pluck("cod", &coduri)
+	if record.Error != nil {
+		return record.Error
+	}
+
+	var exercitii []authentication.Exercitiu
+	record = tx.Where("exam = ? AND sectiune <> ?", exam, "").Find(&exercitii)
+	if record.Error != nil {
+		return record.Error
+	}
+	for _, exercitiu := range exercitii {
+		if !contains(coduri, exercitiu.Sectiune) {
+			return fmt.Errorf("%w: exercitiul %s are sectiunea necunoscuta %s", scoring.ErrInvalidSection, exercitiu.Numar, exercitiu.Sectiune)
+		}
+	}
+	return nil
+}
+
+// loadFinalPuncte returns the points of a student on every exercise of an exam, indexed by exercise number.
+// The corrections of each subject are weighted the same way as for the stored total, so that the points of
+// the exercises add up to it
+func loadFinalPuncte(tx *gorm.DB, studentId uint, exam string, schemes []scoring.ExerciseScheme) (map[string]float64, error) {
+	varianteBySlot, weightsByMaterie, err := loadFinalCorrections(tx, studentId, exam, schemes)
+	if err != nil {
+		return nil, err
+	}
+
+	puncte := make(map[string]float64, len(schemes))
+	for _, scheme := range schemes {
+		for slot, weight := range weightsByMaterie[scheme.Materie] {
+			varianta, graded := varianteBySlot[slot][scheme.Numar]
+			if !graded || weight == 0 {
+				continue
+			}
+			puncte[scheme.Numar] += weight * scoring.ExercisePoints(scheme, varianta)
+		}
+	}
+	return puncte, nil
+}
+
+// loadFinalCorrections returns the variants chosen for a student in every grading slot, and how much each slot
+// counts towards the total of every subject. An applied appeal replaces the corrections of its subject
+func loadFinalCorrections(
+	tx *gorm.DB,
+	studentId uint,
+	exam string,
+	schemes []scoring.ExerciseScheme,
+) (map[uint8]map[string]string, map[string]map[uint8]float64, error) {
+	slots := []uint8{SlotCorector1, SlotCorector2, SlotArbitru, SlotContestatie}
+	varianteBySlot := make(map[uint8]map[string]string, len(slots))
+	totalsBySlot := make(map[uint8]map[string]*scoring.SubjectTotal, len(slots))
+	for _, slot := range slots {
+		variante, err := loadChosenVariante(tx, studentId, exam, slot)
+		if err != nil {
+			return nil, nil, err
+		}
+		varianteBySlot[slot] = variante
+		if len(variante) == 0 {
+			continue
+		}
+		totalsBySlot[slot] = make(map[string]*scoring.SubjectTotal)
+		for _, total := range scoring.ComputeSubjectTotals(schemes, variante) {
+			totalsBySlot[slot][total.Materie] = total
+		}
+	}
+
+	var aplicate []string
+	record := tx.
+		Model(&authentication.Contestatie{}).
+		Where("student = ? AND exam = ? AND aplicata = ?", studentId, exam, true).
+		Pluck("materie", &aplicate)
+	if record.Error != nil {
+		return nil, nil, record.Error
+	}
+
+	weightsByMaterie := make(map[string]map[uint8]float64)
+	for _, scheme := range schemes {
+		_, found := weightsByMaterie[scheme.Materie]
+		if found {
+			continue
+		}
+		weights := make(map[uint8]float64, len(slots))
+		if contains(aplicate, scheme.Materie) && scoring.IsComplete(totalsBySlot[SlotContestatie][scheme.Materie]) {
+			weights[SlotContestatie] = 1
+		} else {
+			weights[SlotCorector1], weights[SlotCorector2], weights[SlotArbitru] = scoring.CorrectionWeights(
+				totalsBySlot[SlotCorector1][scheme.Materie],
+				totalsBySlot[SlotCorector2][scheme.Materie],
+				totalsBySlot[SlotArbitru][scheme.Materie],
+			)
+		}
+		weightsByMaterie[scheme.Materie] = weights
+	}
+	return varianteBySlot, weightsByMaterie, nil
+}
+
+// computeStudentSections rolls up the points of a student into the sections of an exam. It returns nil if the
+// exam has no sections
+func computeStudentSections(tx *gorm.DB, studentId uint, exam string) ([]*scoring.SectionTotal, error) {
+	sections, err := loadSections(tx, exam)
+	if err != nil || len(sections) == 0 {
+		return nil, err
+	}
+	schemes, err := loadExerciseSchemes(tx, exam, "")
+	if err != nil {
+		return nil, err
+	}
+	puncte, err := loadFinalPuncte(tx, studentId, exam, schemes)
+	if err != nil {
+		return nil, err
+	}
+	return scoring.ComputeSectionTotals(sections, schemes, puncte), nil
+}
+
+// computeSectiuneStatistics summarizes the points obtained by the students on every section of an exam, parents
+// being listed before their subsections
+func computeSectiuneStatistics(tx *gorm.DB, exam string, students []uint) ([]*SectiuneStatistics, error) {
+	sections, err := loadSections(tx, exam)
+	if err != nil || len(sections) == 0 {
+		return nil, err
+	}
+	schemes, err := loadExerciseSchemes(tx, exam, "")
+	if err != nil {
+		return nil, err
+	}
+
+	puncteByCod := make(map[string][]float64, len(sections))
+	for _, student := range students {
+		puncte, errPuncte := loadFinalPuncte(tx, student, exam, schemes)
+		if errPuncte != nil {
+			return nil, errPuncte
+		}
+		walkSectionTotals(scoring.ComputeSectionTotals(sections, schemes, puncte), "", func(total *scoring.SectionTotal, _ string) {
+			puncteByCod[total.Cod] = append(puncteByCod[total.Cod], total.Punctaj)
+		})
+	}
+
+	result := make([]*SectiuneStatistics, 0, len(sections))
+	walkSectionTotals(scoring.ComputeSectionTotals(sections, schemes, nil), "", func(total *scoring.SectionTotal, parinte string) {
+		result = append(result, &SectiuneStatistics{
+			Cod:          total.Cod,
+			Titlu:        total.Titlu,
+			Parinte:      parinte,
+			PunctajMaxim: total.PunctajMaxim,
+			Punctaj:      statistics.Summarize(puncteByCod[total.Cod]),
+		})
+	})
+	return result, nil
+}
+
+func walkSectionTotals(totals []*scoring.SectionTotal, parinte string, visit func(total *scoring.SectionTotal, parinte string)) {
+	for _, total := range totals {
+		visit(total, parinte)
+		walkSectionTotals(total.Subsectiuni, total.Cod, visit)
+	}
+}
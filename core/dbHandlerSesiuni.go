@@ -0,0 +1,104 @@
+package core
+
+import (
+	"time"
+
+	"github.com/dragos-rebegea/evaluare-tool/authentication"
+	"gorm.io/gorm"
+)
+
+// CreateSesiune stores a new login of a profesor or of a student and drops the sessions of the same user that can
+// no longer be refreshed
+func (db *databaseHandler) CreateSesiune(sesiune *authentication.Sesiune) error {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	return db.database.Transaction(func(tx *gorm.DB) error {
+		record := tx.
+			Where("email = ? AND student = ? AND (expires_at < ? OR revoked_at IS NOT NULL)", sesiune.Email, sesiune.Student, time.Now()).
+			Delete(&authentication.Sesiune{})
+		if record.Error != nil {
+			return record.Error
+		}
+		return tx.Create(sesiune).Error
+	})
+}
+
+// RefreshSesiune replaces the refresh token of an active session and extends the session until expiresAt. The
+// previous refresh token cannot be used again
+func (db *databaseHandler) RefreshSesiune(refreshHash string, newHash string, expiresAt time.Time) (*authentication.Sesiune, error) {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	var sesiune authentication.Sesiune
+	err := db.database.Transaction(func(tx *gorm.DB) error {
+		record := tx.Where("refresh_hash = ? AND revoked_at IS NULL AND expires_at > ?", refreshHash, time.Now()).
+			Limit(1).Find(&sesiune)
+		if record.Error != nil {
+			return record.Error
+		}
+		if record.RowsAffected == 0 {
+			return ErrInvalidRefreshToken
+		}
+
+		sesiune.RefreshHash = newHash
+		sesiune.ExpiresAt = expiresAt
+		return tx.Model(&sesiune).Updates(map[string]interface{}{
+			"refresh_hash": newHash,
+			"expires_at":   expiresAt,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &sesiune, nil
+}
+
+// RevokeSesiune closes the session of a refresh token
+func (db *databaseHandler) RevokeSesiune(refreshHash string) error {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	record := db.database.Model(&authentication.Sesiune{}).
+		Where("refresh_hash = ? AND revoked_at IS NULL", refreshHash).
+		Update("revoked_at", time.Now())
+	if record.Error != nil {
+		return record.Error
+	}
+	if record.RowsAffected == 0 {
+		return ErrInvalidRefreshToken
+	}
+	return nil
+}
+
+// RevokeSesiuniProfesor closes every open session of a profesor and returns how many were closed
+func (db *databaseHandler) RevokeSesiuniProfesor(email string) (int64, error) {
+	_, err := db.GetProfesorByEmail(email)
+	if err != nil {
+		return 0, err
+	}
+
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	record := db.database.Model(&authentication.Sesiune{}).
+		Where("email = ? AND student = ? AND revoked_at IS NULL AND expires_at > ?", email, 0, time.Now()).
+		Update("revoked_at", time.Now())
+	return record.RowsAffected, record.Error
+}
+
+// IsSesiuneActiva returns true if the session was neither revoked nor left to expire
+func (db *databaseHandler) IsSesiuneActiva(id string) (bool, error) {
+	if len(id) == 0 {
+		return false, nil
+	}
+
+	var count int64
+	record := db.database.Model(&authentication.Sesiune{}).
+		Where("id = ? AND revoked_at IS NULL AND expires_at > ?", id, time.Now()).
+		Count(&count)
+	if record.Error != nil {
+		return false, record.Error
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,276 @@
+package core
+
+import (
+	"sort"
+
+	"github.com/dragos-rebegea/evaluare-tool/authentication"
+	"github.com/dragos-rebegea/evaluare-tool/scoring"
+	"github.com/dragos-rebegea/evaluare-tool/statistics"
+	"gorm.io/gorm"
+)
+
+// GetClassStatistics returns the statistics of a class on an exam, if the profesor teaches the class.
+// Admins can access every class. Unless the profesor manages the grading, only the final papers are counted
+func (db *databaseHandler) GetClassStatistics(email string, clasa string, exam string) (*ExamStatistics, error) {
+	err := db.checkClassAccess(email, clasa)
+	if err != nil {
+		return nil, err
+	}
+	visibility, err := db.newScoreVisibility(email)
+	if err != nil {
+		return nil, err
+	}
+
+	students, err := loadExamStudents(db.database.Where("clasa = ?", clasa), exam)
+	if err != nil {
+		return nil, err
+	}
+	finali, err := visibility.finalStudents(exam, students)
+	if err != nil {
+		return nil, err
+	}
+	result, err := computeExamStatistics(db.database, exam, finali)
+	if err != nil {
+		return nil, err
+	}
+	result.Clasa = clasa
+	result.Elevi = len(students)
+	return result, nil
+}
+
+// checkClassAccess checks that the profesor teaches or leads the class. Admins and directors can access every class
+func (db *databaseHandler) checkClassAccess(email string, clasa string) error {
+	prof, err := db.GetProfesorByEmail(email)
+	if err != nil {
+		return err
+	}
+
+	var class authentication.Clasa
+	record := db.database.Where("nume = ?", clasa).First(&class)
+	if record.Error != nil {
+		return record.Error
+	}
+	allowed, err := db.canAccessClass(email, clasa)
+	if err != nil || allowed {
+		return err
+	}
+	return db.checkProfesor(prof, &class)
+}
+
+// GetExamStatistics returns the statistics of every student of the school that sat an exam
+func (db *databaseHandler) GetExamStatistics(exam string) (*ExamStatistics, error) {
+	students, err := loadExamStudents(db.database, exam)
+	if err != nil {
+		return nil, err
+	}
+	return computeExamStatistics(db.database, exam, students)
+}
+
+// loadExamStudents returns the students, matching the provided query, that are assigned to an exam and were present
+func loadExamStudents(query *gorm.DB, exam string) ([]uint, error) {
+	var students []uint
+	record := query.
+		Model(&authentication.Student{}).
+		Where("(exam_stiinta = ? OR exam_limba = ?)", exam, exam).
+		Where("id NOT IN (?)", query.Session(&gorm.Session{NewDB: true}).
+			Model(&authentication.Absenta{}).Where("exam = ?", exam).Select("student")).
+		Order("id").
+		Pluck("id", &students)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+	return students, nil
+}
+
+func computeExamStatistics(tx *gorm.DB, examName string, students []uint) (*ExamStatistics, error) {
+	var exam authentication.Exam
+	record := tx.Where("nume = ?", examName).First(&exam)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+
+	var scores []authentication.Scor
+	record = tx.Where("exam = ? AND student IN ?", examName, students).Order("student, materie").Find(&scores)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+	scoresByStudent := make(map[uint][]authentication.Scor)
+	punctajeByMaterie := make(map[string][]float64)
+	for _, score := range scores {
+		scoresByStudent[score.Student] = append(scoresByStudent[score.Student], score)
+		punctajeByMaterie[score.Materie] = append(punctajeByMaterie[score.Materie], score.Punctaj)
+	}
+
+	punctaje := make([]float64, 0, len(scoresByStudent))
+	note := make([]float64, 0, len(scoresByStudent))
+	for _, student := range students {
+		studentScores, ok := scoresByStudent[student]
+		if !ok {
+			continue
+		}
+		grade := examGrade(&exam, studentScores)
+		punctaje = append(punctaje, grade.Punctaj)
+		note = append(note, grade.Nota)
+	}
+
+	result := &ExamStatistics{
+		Exam:    examName,
+		Elevi:   len(students),
+		Punctaj: statistics.Summarize(punctaje),
+		Note:    statistics.Summarize(note),
+		Materii: make([]*MaterieStatistics, 0, len(punctajeByMaterie)),
+	}
+	for materie, values := range punctajeByMaterie {
+		result.Materii = append(result.Materii, &MaterieStatistics{
+			Materie: materie,
+			Punctaj: statistics.Summarize(values),
+		})
+	}
+	sort.Slice(result.Materii, func(i, j int) bool {
+		return result.Materii[i].Materie < result.Materii[j].Materie
+	})
+
+	graded := make([]uint, 0, len(scoresByStudent))
+	for _, student := range students {
+		_, ok := scoresByStudent[student]
+		if ok {
+			graded = append(graded, student)
+		}
+	}
+	sectiuni, err := computeSectiuneStatistics(tx, examName, graded)
+	if err != nil {
+		return nil, err
+	}
+	result.Sectiuni = sectiuni
+
+	exercitii, err := computeExercitiuStatistics(tx, examName, students)
+	if err != nil {
+		return nil, err
+	}
+	result.Exercitii = exercitii
+	return result, nil
+}
+
+func computeExercitiuStatistics(tx *gorm.DB, exam string, students []uint) ([]*ExercitiuStatistics, error) {
+	schemes, numeVariante, err := loadExamStructure(tx, exam)
+	if err != nil {
+		return nil, err
+	}
+	finale, err := loadFinalExercitii(tx, exam, students, schemes)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*ExercitiuStatistics, 0, len(schemes))
+	for _, scheme := range schemes {
+		counts := make(map[string]int)
+		puncte := make([]float64, 0, len(finale))
+		for _, student := range students {
+			exercitiu, graded := finale[student][scheme.Numar]
+			if !graded {
+				continue
+			}
+			for _, varianta := range exercitiu.variante {
+				counts[varianta]++
+			}
+			puncte = append(puncte, exercitiu.puncte)
+		}
+
+		result = append(result, &ExercitiuStatistics{
+			Numar:        scheme.Numar,
+			Materie:      scheme.Materie,
+			Notate:       len(puncte),
+			PunctajMediu: statistics.Round(statistics.Mean(puncte)),
+			Variante:     variantaShares(numeVariante[scheme.Numar], counts, len(puncte)),
+		})
+	}
+	return result, nil
+}
+
+// loadExamStructure returns the scoring schemes of the exercises of an exam, sorted by subject and number,
+// together with the names of their variants in the order set by the exam
+func loadExamStructure(tx *gorm.DB, exam string) ([]scoring.ExerciseScheme, map[string][]string, error) {
+	schemes, err := loadExerciseSchemes(tx, exam, "")
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.Slice(schemes, func(i, j int) bool {
+		if schemes[i].Materie != schemes[j].Materie {
+			return schemes[i].Materie < schemes[j].Materie
+		}
+		return schemes[i].Numar < schemes[j].Numar
+	})
+
+	var variante []authentication.VariantaExercitiu
+	record := tx.Where("exam = ?", exam).Order("ordine").Find(&variante)
+	if record.Error != nil {
+		return nil, nil, record.Error
+	}
+	numeVariante := make(map[string][]string)
+	for _, varianta := range variante {
+		numeVariante[varianta.Exercitiu] = append(numeVariante[varianta.Exercitiu], varianta.Nume)
+	}
+	return schemes, numeVariante, nil
+}
+
+// variantaShares returns how many of the graded papers got each variant
+func variantaShares(nume []string, counts map[string]int, notate int) []*VariantaStatistics {
+	result := make([]*VariantaStatistics, 0, len(nume))
+	for _, varianta := range nume {
+		share := &VariantaStatistics{
+			Nume:  varianta,
+			Elevi: counts[varianta],
+		}
+		if notate > 0 {
+			share.Procent = statistics.Round(float64(share.Elevi) * 100 / float64(notate))
+		}
+		result = append(result, share)
+	}
+	return result
+}
+
+// finalExercitiu holds what one exercise of a student counts towards the final total: the weighted points of
+// the counted corrections and the distinct variants they chose
+type finalExercitiu struct {
+	puncte   float64
+	variante []string
+}
+
+// loadFinalExercitii returns the graded exercises of each student, indexed by student and exercise number. The
+// corrections are weighted as in the stored totals, so the points of the exercises add up to them
+func loadFinalExercitii(
+	tx *gorm.DB,
+	exam string,
+	students []uint,
+	schemes []scoring.ExerciseScheme,
+) (map[uint]map[string]*finalExercitiu, error) {
+	result := make(map[uint]map[string]*finalExercitiu, len(students))
+	for _, student := range students {
+		varianteBySlot, weightsByMaterie, err := loadFinalCorrections(tx, student, exam, schemes)
+		if err != nil {
+			return nil, err
+		}
+
+		exercitii := make(map[string]*finalExercitiu)
+		for _, scheme := range schemes {
+			for _, slot := range []uint8{SlotCorector1, SlotCorector2, SlotArbitru, SlotContestatie} {
+				varianta, graded := varianteBySlot[slot][scheme.Numar]
+				weight := weightsByMaterie[scheme.Materie][slot]
+				if !graded || weight == 0 {
+					continue
+				}
+				exercitiu, ok := exercitii[scheme.Numar]
+				if !ok {
+					exercitiu = &finalExercitiu{}
+					exercitii[scheme.Numar] = exercitiu
+				}
+				exercitiu.puncte += weight * scoring.ExercisePoints(scheme, varianta)
+				if !contains(exercitiu.variante, varianta) {
+					exercitiu.variante = append(exercitiu.variante, varianta)
+				}
+			}
+		}
+		result[student] = exercitii
+	}
+	return result, nil
+}
@@ -0,0 +1,275 @@
+package core
+
+import (
+	"sort"
+
+	"github.com/dragos-rebegea/evaluare-tool/authentication"
+	"github.com/dragos-rebegea/evaluare-tool/scoring"
+	"gorm.io/gorm"
+)
+
+// GetStudentByUsername returns a student by username
+func (db *databaseHandler) GetStudentByUsername(username string) (*authentication.Student, error) {
+	var student authentication.Student
+	record := db.database.Where("username = ?", username).First(&student)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+	return &student, nil
+}
+
+// GetStudentExame returns the exams assigned to a student, with their status and whether the student missed them
+func (db *databaseHandler) GetStudentExame(studentId uint) ([]*StudentExam, error) {
+	student, err := db.GetStudentByID(studentId)
+	if err != nil {
+		return nil, err
+	}
+	absente, err := loadAbsente(db.database, []uint{studentId})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*StudentExam, 0, 2)
+	for _, nume := range studentExamNames(student) {
+		exam, errExam := db.GetExamByName(nume)
+		if errExam != nil {
+			return nil, errExam
+		}
+		_, absent := absente[absentaKey{student: studentId, exam: nume}]
+		result = append(result, &StudentExam{
+			Exam:               exam.Nume,
+			Status:             exam.Status,
+			Absent:             absent,
+			RezultatePublicate: exam.Status == ExamRezultatePublicate,
+		})
+	}
+	return result, nil
+}
+
+// GetStudentRezultate returns the results of a student on one of their exams, once the results were released
+func (db *databaseHandler) GetStudentRezultate(studentId uint, examName string) (*StudentRezultate, error) {
+	exam, err := db.checkStudentRezultate(studentId, examName)
+	if err != nil {
+		return nil, err
+	}
+
+	var scores []authentication.Scor
+	record := db.database.Where("student = ? AND exam = ?", studentId, examName).Order("materie").Find(&scores)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+	result := &StudentRezultate{
+		Exam:      examName,
+		Materii:   make([]*SubjectScore, 0, len(scores)),
+		Exercitii: make([]*ExercitiuRezultat, 0),
+	}
+	if len(scores) > 0 {
+		result.Nota = examGrade(exam, scores)
+	}
+	for _, score := range scores {
+		result.Materii = append(result.Materii, &SubjectScore{
+			Exam:         score.Exam,
+			Materie:      score.Materie,
+			Punctaj:      score.Punctaj,
+			PunctajMaxim: score.PunctajMaxim,
+		})
+	}
+
+	result.Exercitii, err = loadExercitiiRezultat(db.database, studentId, examName)
+	if err != nil {
+		return nil, err
+	}
+	result.Sectiuni, err = computeStudentSections(db.database, studentId, examName)
+	if err != nil {
+		return nil, err
+	}
+	result.Comentarii, err = loadComentariiRezultat(db.database, studentId, examName)
+	if err != nil {
+		return nil, err
+	}
+
+	var contestatii []authentication.Contestatie
+	record = db.database.Where("student = ? AND exam = ?", studentId, examName).Order("materie").Find(&contestatii)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+	result.Contestatii = make([]*StudentContestatie, 0, len(contestatii))
+	for _, contestatie := range contestatii {
+		result.Contestatii = append(result.Contestatii, &StudentContestatie{
+			Materie:      contestatie.Materie,
+			Motiv:        contestatie.Motiv,
+			Status:       contestatie.Status,
+			NotaInitiala: contestatie.NotaInitiala,
+			NotaNoua:     contestatie.NotaNoua,
+			NotaFinala:   contestatie.NotaFinala,
+			Aplicata:     contestatie.Aplicata,
+			CreatedAt:    contestatie.CreatedAt,
+			UpdatedAt:    contestatie.UpdatedAt,
+		})
+	}
+	return result, nil
+}
+
+// FileStudentContestatie records the appeal of a student against their own grade. Students can appeal only
+// after the results of the exam were released
+func (db *databaseHandler) FileStudentContestatie(studentId uint, request *StudentContestatieRequest) (*authentication.Contestatie, error) {
+	_, err := db.checkStudentRezultate(studentId, request.Exam)
+	if err != nil {
+		return nil, err
+	}
+	student, err := db.GetStudentByID(studentId)
+	if err != nil {
+		return nil, err
+	}
+
+	return db.FileContestatie(student.Username, &ContestatieRequest{
+		Student: studentId,
+		Exam:    request.Exam,
+		Materie: request.Materie,
+		Motiv:   request.Motiv,
+	})
+}
+
+// checkStudentRezultate checks that the exam is assigned to the student and that its results were released
+func (db *databaseHandler) checkStudentRezultate(studentId uint, examName string) (*authentication.Exam, error) {
+	student, err := db.GetStudentByID(studentId)
+	if err != nil {
+		return nil, err
+	}
+	if len(examName) == 0 || !contains(studentExamNames(student), examName) {
+		return nil, ErrStudentNotInExam
+	}
+	exam, err := db.GetExamByName(examName)
+	if err != nil {
+		return nil, err
+	}
+	if exam.Status != ExamRezultatePublicate {
+		return nil, ErrRezultateNepublicate
+	}
+	return exam, nil
+}
+
+// loadExercitiiRezultat returns the points of a student on every exercise of an exam, ordered by subject and
+// exercise, together with the variants of the corrections that count towards the total
+func loadExercitiiRezultat(tx *gorm.DB, studentId uint, exam string) ([]*ExercitiuRezultat, error) {
+	schemes, err := loadExerciseSchemes(tx, exam, "")
+	if err != nil {
+		return nil, err
+	}
+	varianteBySlot, weightsByMaterie, err := loadFinalCorrections(tx, studentId, exam, schemes)
+	if err != nil {
+		return nil, err
+	}
+
+	var variante []authentication.VariantaExercitiu
+	record := tx.Where("exam = ?", exam).Find(&variante)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+	detalii := make(map[string]map[string]authentication.VariantaExercitiu)
+	for _, varianta := range variante {
+		_, ok := detalii[varianta.Exercitiu]
+		if !ok {
+			detalii[varianta.Exercitiu] = make(map[string]authentication.VariantaExercitiu)
+		}
+		detalii[varianta.Exercitiu][varianta.Nume] = varianta
+	}
+
+	result := make([]*ExercitiuRezultat, 0, len(schemes))
+	for _, scheme := range schemes {
+		exercitiu := &ExercitiuRezultat{
+			Numar:        scheme.Numar,
+			Materie:      scheme.Materie,
+			PunctajMaxim: scoring.MaxPoints(scheme),
+			Variante:     make([]authentication.VariantaExercitiu, 0, 1),
+		}
+		alese := make([]string, 0, 1)
+		for _, slot := range []uint8{SlotCorector1, SlotCorector2, SlotArbitru, SlotContestatie} {
+			varianta, graded := varianteBySlot[slot][scheme.Numar]
+			weight := weightsByMaterie[scheme.Materie][slot]
+			if !graded || weight == 0 {
+				continue
+			}
+			exercitiu.Puncte += weight * scoring.ExercisePoints(scheme, varianta)
+			if contains(alese, varianta) {
+				continue
+			}
+			alese = append(alese, varianta)
+			exercitiu.Variante = append(exercitiu.Variante, detalii[scheme.Numar][varianta])
+		}
+		result = append(result, exercitiu)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Materie != result[j].Materie {
+			return result[i].Materie < result[j].Materie
+		}
+		return result[i].Numar < result[j].Numar
+	})
+	return result, nil
+}
+
+// loadComentariiRezultat returns the comments shown to a student on an exam: the general comments, and the
+// comments on exercises left by the correctors whose marks count towards the final total. The authors are not
+// shown
+func loadComentariiRezultat(tx *gorm.DB, studentId uint, exam string) ([]*Comentariu, error) {
+	schemes, err := loadExerciseSchemes(tx, exam, "")
+	if err != nil {
+		return nil, err
+	}
+	_, weightsByMaterie, err := loadFinalCorrections(tx, studentId, exam, schemes)
+	if err != nil {
+		return nil, err
+	}
+	materii := make(map[string]string, len(schemes))
+	for _, scheme := range schemes {
+		materii[scheme.Numar] = scheme.Materie
+	}
+
+	var calificative []*Calificativ
+	record := tx.Table("calificativs").Where("student = ? AND exam = ?", studentId, exam).Scan(&calificative)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+	numarate := make(map[string]map[uint]bool)
+	for _, calificativ := range calificative {
+		if weightsByMaterie[materii[calificativ.Exercitiu]][calificativ.Slot] == 0 {
+			continue
+		}
+		_, ok := numarate[calificativ.Exercitiu]
+		if !ok {
+			numarate[calificativ.Exercitiu] = make(map[uint]bool)
+		}
+		numarate[calificativ.Exercitiu][calificativ.Profesor] = true
+	}
+
+	var records []authentication.Comentariu
+	record = tx.Where("student = ? AND exam = ?", studentId, exam).Order("exercitiu, created_at").Find(&records)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+	comentarii := make([]*Comentariu, 0, len(records))
+	for _, comentariu := range records {
+		if len(comentariu.Exercitiu) > 0 && !numarate[comentariu.Exercitiu][comentariu.Profesor] {
+			continue
+		}
+		comentarii = append(comentarii, &Comentariu{
+			Exam:      comentariu.Exam,
+			Exercitiu: comentariu.Exercitiu,
+			Text:      comentariu.Text,
+			CreatedAt: comentariu.CreatedAt,
+			UpdatedAt: comentariu.UpdatedAt,
+		})
+	}
+	return comentarii, nil
+}
+
+// studentExamNames returns the distinct exams assigned to a student
+func studentExamNames(student *authentication.Student) []string {
+	exams := make([]string, 0, 2)
+	for _, exam := range []string{student.ExamStiinta, student.ExamLimba} {
+		if len(exam) > 0 && !contains(exams, exam) {
+			exams = append(exams, exam)
+		}
+	}
+	return exams
+}
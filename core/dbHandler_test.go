@@ -0,0 +1,1676 @@
+package core
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dragos-rebegea/evaluare-tool/authentication"
+	"github.com/dragos-rebegea/evaluare-tool/migrations"
+	"github.com/dragos-rebegea/evaluare-tool/scoring"
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createMockArgsDatabaseHandler() ArgsDatabaseHandler {
+	return ArgsDatabaseHandler{
+		Driver:           SQLiteDriver,
+		ConnectionString: ":memory:",
+		AutoMigrate:      true,
+	}
+}
+
+func createMockClass(profUsername string) *Class {
+	class := &Class{
+		Nume:     "8A",
+		ProfMate: profUsername,
+	}
+	class.Elevi = append(class.Elevi, ClassStudent{
+		Nume:        "Popescu",
+		Prenume:     "Ion",
+		Email:       "ion@test.ro",
+		ExamStiinta: "simulare",
+		ExamLimba:   "simulare",
+	})
+	return class
+}
+
+func openGrading(t *testing.T, db *databaseHandler, exam string) {
+	for _, status := range []string{ExamPublicat, ExamNotareDeschisa} {
+		_, err := db.SetExamStatus("admin@test.ro", &ExamStatusRequest{Exam: exam, Status: status})
+		require.Nil(t, err)
+	}
+}
+
+func TestNewDatabaseHandler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty connection string should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsDatabaseHandler()
+		args.ConnectionString = ""
+		db, err := NewDatabaseHandler(args)
+		assert.Equal(t, ErrEmptyConnectionString, err)
+		assert.True(t, check.IfNil(db))
+	})
+	t.Run("unknown driver should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsDatabaseHandler()
+		args.Driver = "postgres"
+		db, err := NewDatabaseHandler(args)
+		assert.True(t, errors.Is(err, ErrUnknownDatabaseDriver))
+		assert.True(t, check.IfNil(db))
+	})
+	t.Run("schema behind should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsDatabaseHandler()
+		args.AutoMigrate = false
+		db, err := NewDatabaseHandler(args)
+		assert.True(t, errors.Is(err, migrations.ErrSchemaBehind))
+		assert.True(t, check.IfNil(db))
+	})
+	t.Run("sqlite should work", func(t *testing.T) {
+		t.Parallel()
+
+		db, err := NewDatabaseHandler(createMockArgsDatabaseHandler())
+		assert.Nil(t, err)
+		assert.False(t, check.IfNil(db))
+	})
+}
+
+func TestDatabaseHandler_GradingFlow(t *testing.T) {
+	t.Parallel()
+
+	db, err := NewDatabaseHandler(createMockArgsDatabaseHandler())
+	require.Nil(t, err)
+
+	prof := &authentication.Profesor{
+		User: authentication.User{
+			Username: "prof_mate",
+			Email:    "mate@test.ro",
+			Password: "password",
+		},
+		Materie: "matematica",
+	}
+	require.Nil(t, db.CreateProfesor(prof))
+
+	roluri, err := db.GetRoluri(prof.Email)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{authentication.RolProfesor}, authentication.RoleNames(roluri))
+
+	result, err := db.CreateClass(createMockClass(prof.Username))
+	require.Nil(t, err)
+	require.Equal(t, 1, len(result.Created))
+	students := result.Created
+
+	classes, err := db.GetAllClasses(prof.Email)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"8A"}, classes)
+
+	exam := &Exam{
+		Nume: "simulare",
+		Exercitii: []Exercitiu{
+			{
+				Numar:    "1",
+				Variante: []string{"A", "B"},
+				Punctaje: map[string]float64{"A": 1, "B": 3},
+				Rubrici:  map[string]*Rubrica{"B": {Descriere: "rezolvare completa", ExempluRaspuns: "x = 2"}},
+				Materie:  "matematica",
+			},
+			{Numar: "2", Variante: []string{"A", "B"}, Punctaje: map[string]float64{"B": 2}, Materie: "matematica"},
+		},
+	}
+	require.Nil(t, db.CreateExam(exam))
+
+	exercitii, err := db.GetExercitiiForProfesorAndStudent(prof.Email, "1")
+	require.Nil(t, err)
+	require.Equal(t, 2, len(exercitii))
+	assert.Equal(t, []string{"A", "B"}, exercitii[0].Variante)
+	assert.Equal(t, 3.0, exercitii[0].Punctaje["B"])
+	assert.Equal(t, 1.0, exercitii[0].Pondere)
+	assert.Equal(t, &Rubrica{Descriere: "rezolvare completa", ExempluRaspuns: "x = 2"}, exercitii[0].Rubrici["B"])
+
+	update := &RubricaUpdate{Exam: "simulare", Exercitiu: "1", Varianta: "A", Rubrica: Rubrica{Descriere: "doar rezultatul"}}
+	require.Nil(t, db.UpdateRubrica(update))
+	update.Varianta = "C"
+	assert.NotNil(t, db.UpdateRubrica(update))
+	exam.Exercitii[0].Rubrici = nil
+	require.Nil(t, db.CreateExam(exam))
+	exercitii, _ = db.GetExercitiiForProfesorAndStudent(prof.Email, "1")
+	assert.Equal(t, "doar rezultatul", exercitii[0].Rubrici["A"].Descriere)
+	assert.Equal(t, "rezolvare completa", exercitii[0].Rubrici["B"].Descriere)
+	openGrading(t, db, "simulare")
+	update.Varianta = "A"
+	assert.Equal(t, ErrExamNotDraft, db.UpdateRubrica(update))
+
+	calificativ := &Calificativ{
+		Student:   students[0].ID,
+		Exam:      "simulare",
+		Exercitiu: "1",
+		Varianta:  "C",
+	}
+	err = db.AddCalificativ(prof.Email, calificativ)
+	assert.NotNil(t, err)
+
+	calificativ.Varianta = "B"
+	require.Nil(t, db.AddCalificativ(prof.Email, calificativ))
+
+	calificative, err := db.GetCalificative(prof.Email, "1")
+	assert.Nil(t, err)
+	require.Equal(t, 1, len(calificative))
+	assert.Equal(t, "B", calificative[0].Varianta)
+	assert.Equal(t, prof.ID, calificative[0].Profesor)
+
+	score, err := db.GetStudentScore(prof.Email, "1")
+	require.Nil(t, err)
+	require.Equal(t, 1, len(score.Materii))
+	assert.Equal(t, 3.0, score.Materii[0].Punctaj)
+	assert.Equal(t, 5.0, score.Materii[0].PunctajMaxim)
+
+	calificativ.Varianta = "A"
+	require.Nil(t, db.UpdateCalificativ(prof.Email, calificativ))
+	score, _ = db.GetStudentScore(prof.Email, "1")
+	assert.Equal(t, 1.0, score.Materii[0].Punctaj)
+
+	exam.Exercitii[0].Punctaje["A"] = 2
+	exam.Exercitii[0].Pondere = 2
+	assert.Equal(t, ErrExamNotDraft, db.CreateExam(exam))
+	score, _ = db.GetStudentScore(prof.Email, "1")
+	assert.Equal(t, 1.0, score.Materii[0].Punctaj)
+	assert.Equal(t, 5.0, score.Materii[0].PunctajMaxim)
+
+	grades, err := db.GetStudentGrades(prof.Email, "1")
+	require.Nil(t, err)
+	require.Equal(t, 1, len(grades.Note))
+	assert.Equal(t, 2.0, grades.Note[0].Nota)
+	require.NotNil(t, grades.Media)
+	assert.Equal(t, 2.0, *grades.Media)
+
+	exam.Rotunjire = "aproximare"
+	assert.Equal(t, ErrInvalidGradeRule, db.CreateExam(exam))
+	exam.Rotunjire = ""
+
+	exam.Exercitii[0].Punctaje["C"] = 2
+	assert.True(t, errors.Is(db.CreateExam(exam), ErrUnknownVarianta))
+	delete(exam.Exercitii[0].Punctaje, "C")
+	exam.Exercitii[0].Rubrici = map[string]*Rubrica{"C": {Descriere: "alta varianta"}}
+	assert.True(t, errors.Is(db.CreateExam(exam), ErrUnknownVarianta))
+	exam.Exercitii[0].Rubrici = nil
+	exam.Exercitii[0].Punctaje["A"] = -1
+	assert.True(t, errors.Is(db.CreateExam(exam), ErrInvalidPunctaj))
+}
+
+func TestDatabaseHandler_DoubleGrading(t *testing.T) {
+	t.Parallel()
+
+	db, err := NewDatabaseHandler(createMockArgsDatabaseHandler())
+	require.Nil(t, err)
+
+	profesori := make([]*authentication.Profesor, 0, 3)
+	for _, username := range []string{"corector1", "corector2", "arbitru"} {
+		prof := &authentication.Profesor{
+			User: authentication.User{
+				Username: username,
+				Email:    username + "@test.ro",
+				Password: "password",
+			},
+			Materie: "matematica",
+		}
+		require.Nil(t, db.CreateProfesor(prof))
+		profesori = append(profesori, prof)
+	}
+	_, err = db.CreateClass(createMockClass(profesori[0].Username))
+	require.Nil(t, err)
+	exam := &Exam{
+		Nume: "simulare",
+		Exercitii: []Exercitiu{
+			{Numar: "1", Variante: []string{"A", "B"}, Punctaje: map[string]float64{"A": 1, "B": 9}, Materie: "matematica"},
+		},
+	}
+	require.Nil(t, db.CreateExam(exam))
+	openGrading(t, db, "simulare")
+
+	calificativ := &Calificativ{Student: 1, Exam: "simulare", Exercitiu: "1", Varianta: "A"}
+	assert.Equal(t, ErrNotAnEvaluator, db.AddCalificativ(profesori[1].Email, calificativ))
+
+	assignment := &EvaluatorAssignment{Clasa: "8A", Exam: "simulare", Materie: "matematica", Slot: 3, Profesor: profesori[1].Email}
+	assert.Equal(t, ErrInvalidSlot, db.AssignEvaluator(assignment))
+	assignment.Slot = SlotCorector2
+	require.Nil(t, db.AssignEvaluator(assignment))
+
+	require.Nil(t, db.AddCalificativ(profesori[0].Email, calificativ))
+	assert.Equal(t, SlotCorector1, calificativ.Slot)
+	calificativ = &Calificativ{Student: 1, Exam: "simulare", Exercitiu: "1", Varianta: "B"}
+	require.Nil(t, db.AddCalificativ(profesori[1].Email, calificativ))
+	assert.Equal(t, SlotCorector2, calificativ.Slot)
+
+	calificative, err := db.GetCalificative(profesori[1].Email, "1")
+	require.Nil(t, err)
+	require.Equal(t, 1, len(calificative))
+	assert.Equal(t, "B", calificative[0].Varianta)
+
+	_, err = db.SetComentariu(profesori[1].Email, &ComentariuRequest{Student: 1, Exam: "simulare", Exercitiu: "1", Text: "Rezolvare completa"})
+	require.Nil(t, err)
+
+	score, err := db.GetStudentScore(profesori[0].Email, "1")
+	require.Nil(t, err)
+	assert.Equal(t, 1.0, score.Materii[0].Punctaj)
+	assert.Equal(t, 0, len(score.Comentarii))
+	grades, err := db.GetStudentGrades(profesori[0].Email, "1")
+	require.Nil(t, err)
+	require.Equal(t, 1, len(grades.Note))
+	assert.Equal(t, 1.11, grades.Note[0].Nota)
+	admin := &authentication.Profesor{
+		User:    authentication.User{Username: "admin", Email: "admin@test.ro", Password: "password"},
+		Materie: "matematica",
+	}
+	require.Nil(t, db.CreateProfesor(admin, authentication.RolAdmin))
+	score, _ = db.GetStudentScore(admin.Email, "1")
+	assert.Equal(t, 5.0, score.Materii[0].Punctaj)
+	require.Equal(t, 1, len(score.Comentarii))
+	assert.Equal(t, profesori[1].Email, score.Comentarii[0].Autor)
+
+	arbitraje, err := db.GetArbitraje()
+	require.Nil(t, err)
+	require.Equal(t, 1, len(arbitraje))
+	assert.Equal(t, ArbitrajInAsteptare, arbitraje[0].Status)
+	assert.Equal(t, 1.11, arbitraje[0].Nota1)
+	assert.Equal(t, 10.0, arbitraje[0].Nota2)
+
+	arbiter := &ArbiterAssignment{Student: 1, Exam: "simulare", Materie: "matematica", Profesor: profesori[1].Email}
+	assert.NotNil(t, db.AssignArbiter(arbiter))
+	arbiter.Profesor = profesori[2].Email
+	require.Nil(t, db.AssignArbiter(arbiter))
+
+	calificativ = &Calificativ{Student: 1, Exam: "simulare", Exercitiu: "1", Varianta: "B"}
+	require.Nil(t, db.AddCalificativ(profesori[2].Email, calificativ))
+	assert.Equal(t, SlotArbitru, calificativ.Slot)
+
+	score, _ = db.GetStudentScore(profesori[0].Email, "1")
+	assert.Equal(t, 9.0, score.Materii[0].Punctaj)
+	grades, _ = db.GetStudentGrades(profesori[0].Email, "1")
+	assert.Equal(t, 10.0, grades.Note[0].Nota)
+	arbitraje, _ = db.GetArbitraje()
+	assert.Equal(t, 0, len(arbitraje))
+}
+
+func TestDatabaseHandler_GradingSlotMaterie(t *testing.T) {
+	t.Parallel()
+
+	db, err := NewDatabaseHandler(createMockArgsDatabaseHandler())
+	require.Nil(t, err)
+
+	profesori := make(map[string]*authentication.Profesor)
+	for username, materie := range map[string]string{"mate": "matematica", "mate2": "matematica", "fizica": "fizica"} {
+		prof := &authentication.Profesor{
+			User: authentication.User{
+				Username: username,
+				Email:    username + "@test.ro",
+				Password: "password",
+			},
+			Materie: materie,
+		}
+		require.Nil(t, db.CreateProfesor(prof))
+		profesori[username] = prof
+	}
+	class := createMockClass("mate")
+	class.ProfFizica = "fizica"
+	_, err = db.CreateClass(class)
+	require.Nil(t, err)
+	require.Nil(t, db.CreateExam(&Exam{
+		Nume: "simulare",
+		Exercitii: []Exercitiu{
+			{Numar: "1", Variante: []string{"A", "B"}, Punctaje: map[string]float64{"A": 1, "B": 9}, Materie: "matematica"},
+			{Numar: "2", Variante: []string{"A", "B"}, Punctaje: map[string]float64{"A": 1, "B": 9}, Materie: "fizica"},
+		},
+	}))
+	openGrading(t, db, "simulare")
+	require.Nil(t, db.AssignEvaluator(&EvaluatorAssignment{Clasa: "8A", Exam: "simulare", Materie: "matematica", Slot: SlotCorector2, Profesor: profesori["mate2"].Email}))
+
+	assert.Equal(t, ErrNotAnEvaluator, db.AddCalificativ(profesori["fizica"].Email, &Calificativ{Student: 1, Exam: "simulare", Exercitiu: "1", Varianta: "B"}))
+	require.Nil(t, db.AddCalificativ(profesori["fizica"].Email, &Calificativ{Student: 1, Exam: "simulare", Exercitiu: "2", Varianta: "B"}))
+	require.Nil(t, db.AddCalificativ(profesori["mate"].Email, &Calificativ{Student: 1, Exam: "simulare", Exercitiu: "1", Varianta: "A"}))
+	require.Nil(t, db.AddCalificativ(profesori["mate2"].Email, &Calificativ{Student: 1, Exam: "simulare", Exercitiu: "1", Varianta: "B"}))
+
+	arbiter := &ArbiterAssignment{Student: 1, Exam: "simulare", Materie: "matematica", Profesor: profesori["mate2"].Email}
+	assert.NotNil(t, db.AssignArbiter(arbiter))
+	arbiter.Profesor = profesori["fizica"].Email
+	require.Nil(t, db.AssignArbiter(arbiter))
+}
+
+func TestDatabaseHandler_ScoreVisibility(t *testing.T) {
+	t.Parallel()
+
+	db, err := NewDatabaseHandler(createMockArgsDatabaseHandler())
+	require.Nil(t, err)
+
+	profesori := make([]*authentication.Profesor, 0, 3)
+	for _, username := range []string{"corector1", "corector2", "arbitru"} {
+		prof := &authentication.Profesor{
+			User: authentication.User{
+				Username: username,
+				Email:    username + "@test.ro",
+				Password: "password",
+			},
+			Materie: "matematica",
+		}
+		require.Nil(t, db.CreateProfesor(prof))
+		profesori = append(profesori, prof)
+	}
+	_, err = db.CreateClass(createMockClass(profesori[0].Username))
+	require.Nil(t, err)
+	require.Nil(t, db.CreateExam(&Exam{
+		Nume: "simulare",
+		Exercitii: []Exercitiu{
+			{Numar: "1", Variante: []string{"A", "B"}, Punctaje: map[string]float64{"A": 1, "B": 9}, Materie: "matematica"},
+		},
+	}))
+	openGrading(t, db, "simulare")
+	require.Nil(t, db.AssignRol(&RolRequest{Email: profesori[1].Email, Rol: authentication.RolDiriginte, Clasa: "8A"}))
+	require.Nil(t, db.AssignEvaluator(&EvaluatorAssignment{Clasa: "8A", Exam: "simulare", Materie: "matematica", Slot: SlotCorector2, Profesor: profesori[1].Email}))
+	require.Nil(t, db.AddCalificativ(profesori[0].Email, &Calificativ{Student: 1, Exam: "simulare", Exercitiu: "1", Varianta: "A"}))
+	require.Nil(t, db.AddCalificativ(profesori[1].Email, &Calificativ{Student: 1, Exam: "simulare", Exercitiu: "1", Varianta: "B"}))
+
+	score, err := db.GetStudentScore(profesori[1].Email, "1")
+	require.Nil(t, err)
+	require.Equal(t, 1, len(score.Materii))
+	assert.Equal(t, 9.0, score.Materii[0].Punctaj)
+	stats, err := db.GetClassStatistics(profesori[1].Email, "8A", "simulare")
+	require.Nil(t, err)
+	assert.Equal(t, 1, stats.Elevi)
+	assert.Equal(t, 0, stats.Note.Count)
+	assert.Equal(t, 0, stats.Exercitii[0].Notate)
+	report, err := db.GetCompetencyReport(profesori[1].Email, "8A", "simulare")
+	require.Nil(t, err)
+	assert.Equal(t, 1, report.Elevi)
+	assert.Equal(t, 0, len(report.Profiluri))
+	evolution, err := db.GetStudentEvolution(profesori[1].Email, "1")
+	require.Nil(t, err)
+	require.Equal(t, 1, len(evolution.Exame))
+	assert.Equal(t, 9.0, evolution.Exame[0].Punctaj)
+	classEvolution, err := db.GetClassEvolution(profesori[1].Email, "8A")
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(classEvolution.Exame))
+
+	require.Nil(t, db.AssignArbiter(&ArbiterAssignment{Student: 1, Exam: "simulare", Materie: "matematica", Profesor: profesori[2].Email}))
+	require.Nil(t, db.AddCalificativ(profesori[2].Email, &Calificativ{Student: 1, Exam: "simulare", Exercitiu: "1", Varianta: "B"}))
+
+	stats, err = db.GetClassStatistics(profesori[1].Email, "8A", "simulare")
+	require.Nil(t, err)
+	assert.Equal(t, 1, stats.Note.Count)
+	assert.Equal(t, 1, stats.Exercitii[0].Notate)
+	_, err = db.SetComentariu(profesori[0].Email, &ComentariuRequest{Student: 1, Exam: "simulare", Text: "Lucrare buna"})
+	require.Nil(t, err)
+	report, err = db.GetCompetencyReport(profesori[1].Email, "8A", "simulare")
+	require.Nil(t, err)
+	require.Equal(t, 1, len(report.Profiluri))
+	require.NotNil(t, report.Profiluri[0].Nota)
+	assert.Equal(t, 10.0, report.Profiluri[0].Nota.Nota)
+	assert.Equal(t, 0, len(report.Profiluri[0].Comentarii))
+	classEvolution, err = db.GetClassEvolution(profesori[1].Email, "8A")
+	require.Nil(t, err)
+	require.Equal(t, 1, len(classEvolution.Exame))
+	assert.Equal(t, 1, classEvolution.Exame[0].Elevi)
+	assert.Equal(t, 9.0, classEvolution.Exame[0].Punctaj)
+}
+
+func TestDatabaseHandler_Contestatie(t *testing.T) {
+	t.Parallel()
+
+	db, err := NewDatabaseHandler(createMockArgsDatabaseHandler())
+	require.Nil(t, err)
+
+	profesori := make([]*authentication.Profesor, 0, 2)
+	for _, username := range []string{"corector", "reevaluator"} {
+		prof := &authentication.Profesor{
+			User: authentication.User{
+				Username: username,
+				Email:    username + "@test.ro",
+				Password: "password",
+			},
+			Materie: "matematica",
+		}
+		require.Nil(t, db.CreateProfesor(prof))
+		profesori = append(profesori, prof)
+	}
+	_, err = db.CreateClass(createMockClass(profesori[0].Username))
+	require.Nil(t, err)
+	exam := &Exam{
+		Nume: "simulare",
+		Exercitii: []Exercitiu{
+			{Numar: "1", Variante: []string{"A", "B"}, Punctaje: map[string]float64{"A": 1, "B": 9}, Materie: "matematica"},
+		},
+	}
+	require.Nil(t, db.CreateExam(exam))
+	openGrading(t, db, "simulare")
+
+	request := &ContestatieRequest{Student: 1, Exam: "simulare", Materie: "matematica", Motiv: "punctaj gresit"}
+	_, err = db.FileContestatie("admin@test.ro", request)
+	assert.NotNil(t, err)
+
+	calificativ := &Calificativ{Student: 1, Exam: "simulare", Exercitiu: "1", Varianta: "A"}
+	require.Nil(t, db.AddCalificativ(profesori[0].Email, calificativ))
+	_, err = db.FileContestatie("admin@test.ro", request)
+	assert.Equal(t, ErrRezultateNepublicate, err)
+
+	for _, status := range []string{ExamNotareInchisa, ExamRezultatePublicate} {
+		_, err = db.SetExamStatus("admin@test.ro", &ExamStatusRequest{Exam: "simulare", Status: status})
+		require.Nil(t, err)
+	}
+	contestatie, err := db.FileContestatie("admin@test.ro", request)
+	require.Nil(t, err)
+	assert.Equal(t, ContestatieDepusa, contestatie.Status)
+	assert.Equal(t, 1.11, contestatie.NotaInitiala)
+	_, err = db.FileContestatie("admin@test.ro", request)
+	assert.NotNil(t, err)
+
+	calificativ.Varianta = "B"
+	assert.Equal(t, ErrLucrareContestata, db.UpdateCalificativ(profesori[0].Email, calificativ))
+	_, err = db.ResolveContestatie(request)
+	assert.Equal(t, ErrContestatieStatus, err)
+
+	assignment := &ContestatieAssignment{Student: 1, Exam: "simulare", Materie: "matematica", Profesor: profesori[0].Email}
+	assert.NotNil(t, db.AssignContestatie(assignment))
+	assignment.Profesor = profesori[1].Email
+	require.Nil(t, db.AssignContestatie(assignment))
+
+	calificativ = &Calificativ{Student: 1, Exam: "simulare", Exercitiu: "1", Varianta: "B"}
+	require.Nil(t, db.AddCalificativ(profesori[1].Email, calificativ))
+	assert.Equal(t, SlotContestatie, calificativ.Slot)
+
+	contestatii, err := db.GetContestatii(ContestatieReevaluata)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(contestatii))
+	require.NotNil(t, contestatii[0].NotaNoua)
+	assert.Equal(t, 10.0, *contestatii[0].NotaNoua)
+
+	score, _ := db.GetStudentScore(profesori[0].Email, "1")
+	assert.Equal(t, 1.0, score.Materii[0].Punctaj)
+
+	contestatie, err = db.ResolveContestatie(request)
+	require.Nil(t, err)
+	assert.Equal(t, ContestatieRezolvata, contestatie.Status)
+	assert.True(t, contestatie.Aplicata)
+	require.NotNil(t, contestatie.NotaFinala)
+	assert.Equal(t, 10.0, *contestatie.NotaFinala)
+
+	score, _ = db.GetStudentScore(profesori[0].Email, "1")
+	assert.Equal(t, 9.0, score.Materii[0].Punctaj)
+	assert.NotNil(t, db.UpdateCalificativ(profesori[1].Email, calificativ))
+}
+
+func TestDatabaseHandler_GradingProgress(t *testing.T) {
+	t.Parallel()
+
+	db, err := NewDatabaseHandler(createMockArgsDatabaseHandler())
+	require.Nil(t, err)
+
+	prof := &authentication.Profesor{
+		User: authentication.User{
+			Username: "prof_mate",
+			Email:    "mate@test.ro",
+			Password: "password",
+		},
+		Materie: "matematica",
+	}
+	require.Nil(t, db.CreateProfesor(prof))
+	class := createMockClass(prof.Username)
+	class.Elevi = append(class.Elevi, ClassStudent{Nume: "Ionescu", Prenume: "Ana", ExamStiinta: "simulare"})
+	result, err := db.CreateClass(class)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(result.Created))
+	require.Nil(t, db.CreateExam(&Exam{
+		Nume: "simulare",
+		Exercitii: []Exercitiu{
+			{Numar: "1", Variante: []string{"A", "B"}, Materie: "matematica"},
+			{Numar: "2", Variante: []string{"A", "B"}, Materie: "matematica"},
+		},
+	}))
+	openGrading(t, db, "simulare")
+	require.Nil(t, db.SetAbsent("admin@test.ro", &AbsentStatus{Id: result.Created[1].ID, Exam: "simulare", Absent: true}))
+	require.Nil(t, db.AddCalificativ(prof.Email, &Calificativ{Student: result.Created[0].ID, Exam: "simulare", Exercitiu: "1", Varianta: "A"}))
+
+	progress, err := db.GetClassProgress("8A", "simulare")
+	require.Nil(t, err)
+	require.Equal(t, 1, len(progress.Profesori))
+	assert.Equal(t, &ProfesorProgress{
+		Profesor:        prof.Email,
+		Clasa:           "8A",
+		Exam:            "simulare",
+		Materie:         "matematica",
+		Slot:            SlotCorector1,
+		Elevi:           1,
+		EleviFinalizati: 0,
+		ExercitiiTotal:  2,
+		ExercitiiNotate: 1,
+	}, progress.Profesori[0])
+	require.Equal(t, 2, len(progress.Exercitii))
+	assert.Equal(t, 1, progress.Exercitii[0].Notate)
+	assert.Equal(t, 0, progress.Exercitii[1].Notate)
+	require.Equal(t, 1, len(progress.LucrariIncomplete))
+	assert.Equal(t, []string{"2"}, progress.LucrariIncomplete[0].Lipsa)
+
+	profProgress, err := db.GetProfesorProgress(prof.Email)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(profProgress))
+	assert.Equal(t, progress.Profesori[0], profProgress[0])
+
+	progress, err = db.GetClassProgress("8A", "alt examen")
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(progress.Profesori))
+}
+
+func TestDatabaseHandler_Statistics(t *testing.T) {
+	t.Parallel()
+
+	db, err := NewDatabaseHandler(createMockArgsDatabaseHandler())
+	require.Nil(t, err)
+
+	prof := &authentication.Profesor{
+		User: authentication.User{
+			Username: "prof_mate",
+			Email:    "mate@test.ro",
+			Password: "password",
+		},
+		Materie: "matematica",
+	}
+	require.Nil(t, db.CreateProfesor(prof))
+	class := createMockClass(prof.Username)
+	class.Elevi = append(class.Elevi, ClassStudent{Nume: "Ionescu", Prenume: "Ana", ExamStiinta: "simulare"})
+	result, err := db.CreateClass(class)
+	require.Nil(t, err)
+	require.Nil(t, db.CreateExam(&Exam{
+		Nume: "simulare",
+		Exercitii: []Exercitiu{
+			{Numar: "1", Variante: []string{"A", "B"}, Punctaje: map[string]float64{"A": 1, "B": 3}, Materie: "matematica"},
+			{Numar: "2", Variante: []string{"A", "B"}, Punctaje: map[string]float64{"B": 2}, Materie: "matematica"},
+		},
+	}))
+	openGrading(t, db, "simulare")
+
+	for _, calificativ := range []*Calificativ{
+		{Student: result.Created[0].ID, Exam: "simulare", Exercitiu: "1", Varianta: "B"},
+		{Student: result.Created[0].ID, Exam: "simulare", Exercitiu: "2", Varianta: "B"},
+		{Student: result.Created[1].ID, Exam: "simulare", Exercitiu: "1", Varianta: "A"},
+		{Student: result.Created[1].ID, Exam: "simulare", Exercitiu: "2", Varianta: "A"},
+	} {
+		require.Nil(t, db.AddCalificativ(prof.Email, calificativ))
+	}
+
+	_, err = db.GetClassStatistics("alt@test.ro", "8A", "simulare")
+	assert.NotNil(t, err)
+
+	stats, err := db.GetClassStatistics(prof.Email, "8A", "simulare")
+	require.Nil(t, err)
+	assert.Equal(t, "8A", stats.Clasa)
+	assert.Equal(t, 2, stats.Elevi)
+	assert.Equal(t, 3.0, stats.Punctaj.Media)
+	assert.Equal(t, 5.0, stats.Punctaj.Maxim)
+	assert.Equal(t, 2, stats.Note.Count)
+	require.Equal(t, 1, len(stats.Materii))
+	require.Equal(t, 2, len(stats.Exercitii))
+	assert.Equal(t, 2, stats.Exercitii[0].Notate)
+	assert.Equal(t, 2.0, stats.Exercitii[0].PunctajMediu)
+	assert.Equal(t, &VariantaStatistics{Nume: "A", Elevi: 1, Procent: 50}, stats.Exercitii[0].Variante[0])
+	assert.Equal(t, &VariantaStatistics{Nume: "B", Elevi: 1, Procent: 50}, stats.Exercitii[1].Variante[1])
+
+	schoolStats, err := db.GetExamStatistics("simulare")
+	require.Nil(t, err)
+	assert.Empty(t, schoolStats.Clasa)
+	assert.Equal(t, stats.Punctaj, schoolStats.Punctaj)
+
+	analysis, err := db.GetItemAnalysis("simulare")
+	require.Nil(t, err)
+	assert.Equal(t, 2, analysis.Elevi)
+	require.Equal(t, 2, len(analysis.Exercitii))
+	assert.Equal(t, 2, analysis.Exercitii[0].Elevi)
+	assert.Equal(t, 0.67, analysis.Exercitii[0].Facilitate)
+	assert.Equal(t, 0.67, analysis.Exercitii[0].Discriminare)
+	assert.Equal(t, 1.0, analysis.Exercitii[0].PunctBiserial)
+	assert.Equal(t, 0.5, analysis.Exercitii[1].Facilitate)
+	assert.Equal(t, 1.0, analysis.Exercitii[1].Discriminare)
+	assert.Equal(t, 50.0, analysis.Exercitii[1].Variante[1].Procent)
+}
+
+func TestDatabaseHandler_StatisticsDoubleGrading(t *testing.T) {
+	t.Parallel()
+
+	db, err := NewDatabaseHandler(createMockArgsDatabaseHandler())
+	require.Nil(t, err)
+
+	profesori := make([]*authentication.Profesor, 0, 2)
+	for _, username := range []string{"corector1", "corector2"} {
+		prof := &authentication.Profesor{
+			User: authentication.User{
+				Username: username,
+				Email:    username + "@test.ro",
+				Password: "password",
+			},
+			Materie: "matematica",
+		}
+		require.Nil(t, db.CreateProfesor(prof))
+		profesori = append(profesori, prof)
+	}
+	_, err = db.CreateClass(createMockClass(profesori[0].Username))
+	require.Nil(t, err)
+	prag := 2.0
+	require.Nil(t, db.CreateExam(&Exam{
+		Nume:         "simulare",
+		PragArbitraj: &prag,
+		Exercitii: []Exercitiu{
+			{Numar: "1", Variante: []string{"A", "B"}, Punctaje: map[string]float64{"A": 8, "B": 9}, Materie: "matematica"},
+		},
+	}))
+	openGrading(t, db, "simulare")
+	require.Nil(t, db.AssignEvaluator(&EvaluatorAssignment{Clasa: "8A", Exam: "simulare", Materie: "matematica", Slot: SlotCorector2, Profesor: profesori[1].Email}))
+	require.Nil(t, db.AddCalificativ(profesori[0].Email, &Calificativ{Student: 1, Exam: "simulare", Exercitiu: "1", Varianta: "A"}))
+	require.Nil(t, db.AddCalificativ(profesori[1].Email, &Calificativ{Student: 1, Exam: "simulare", Exercitiu: "1", Varianta: "B"}))
+	arbitraje, err := db.GetArbitraje()
+	require.Nil(t, err)
+	require.Equal(t, 0, len(arbitraje))
+
+	stats, err := db.GetExamStatistics("simulare")
+	require.Nil(t, err)
+	assert.Equal(t, 8.5, stats.Punctaj.Media)
+	require.Equal(t, 1, len(stats.Exercitii))
+	assert.Equal(t, 8.5, stats.Exercitii[0].PunctajMediu)
+	assert.Equal(t, &VariantaStatistics{Nume: "A", Elevi: 1, Procent: 100}, stats.Exercitii[0].Variante[0])
+	assert.Equal(t, &VariantaStatistics{Nume: "B", Elevi: 1, Procent: 100}, stats.Exercitii[0].Variante[1])
+
+	analysis, err := db.GetItemAnalysis("simulare")
+	require.Nil(t, err)
+	require.Equal(t, 1, len(analysis.Exercitii))
+	assert.Equal(t, 0.94, analysis.Exercitii[0].Facilitate)
+}
+
+func TestDatabaseHandler_Sections(t *testing.T) {
+	t.Parallel()
+
+	db, err := NewDatabaseHandler(createMockArgsDatabaseHandler())
+	require.Nil(t, err)
+
+	profesori := make([]*authentication.Profesor, 0, 2)
+	for _, username := range []string{"corector1", "corector2"} {
+		prof := &authentication.Profesor{
+			User: authentication.User{
+				Username: username,
+				Email:    username + "@test.ro",
+				Password: "password",
+			},
+			Materie: "matematica",
+		}
+		require.Nil(t, db.CreateProfesor(prof))
+		profesori = append(profesori, prof)
+	}
+	_, err = db.CreateClass(createMockClass(profesori[0].Username))
+	require.Nil(t, err)
+
+	exam := &Exam{
+		Nume: "simulare",
+		Sectiuni: []Sectiune{
+			{Cod: "I", Titlu: "Subiectul I"},
+			{Cod: "II", Titlu: "Subiectul II"},
+			{Cod: "II.1", Titlu: "1", Parinte: "II"},
+		},
+		Exercitii: []Exercitiu{
+			{Numar: "1", Variante: []string{"A", "B"}, Punctaje: map[string]float64{"A": 0, "B": 5}, Materie: "matematica", Sectiune: "I"},
+			{Numar: "2a", Variante: []string{"A", "B"}, Punctaje: map[string]float64{"A": 1, "B": 2}, Materie: "matematica", Sectiune: "II.1"},
+			{Numar: "2b", Variante: []string{"A", "B"}, Punctaje: map[string]float64{"A": 0, "B": 3}, Materie: "matematica", Sectiune: "III"},
+		},
+	}
+	assert.True(t, errors.Is(db.CreateExam(exam), scoring.ErrInvalidSection))
+	exam.Exercitii[2].Sectiune = "II.1"
+	exam.Sectiuni[0].Parinte = "II.1"
+	exam.Sectiuni[1].Parinte = "I"
+	assert.True(t, errors.Is(db.CreateExam(exam), scoring.ErrInvalidSection))
+	exam.Sectiuni[0].Parinte = ""
+	exam.Sectiuni[1].Parinte = ""
+	require.Nil(t, db.CreateExam(exam))
+	openGrading(t, db, "simulare")
+
+	exercitii, err := db.GetExercitiiForProfesorAndStudent(profesori[0].Email, "1")
+	require.Nil(t, err)
+	require.Equal(t, 3, len(exercitii))
+	assert.Equal(t, "II.1", exercitii[2].Sectiune)
+
+	require.Nil(t, db.AssignEvaluator(&EvaluatorAssignment{Clasa: "8A", Exam: "simulare", Materie: "matematica", Slot: SlotCorector2, Profesor: profesori[1].Email}))
+	for _, calificativ := range []*Calificativ{
+		{Student: 1, Exam: "simulare", Exercitiu: "1", Varianta: "B"},
+		{Student: 1, Exam: "simulare", Exercitiu: "2a", Varianta: "A"},
+		{Student: 1, Exam: "simulare", Exercitiu: "2b", Varianta: "B"},
+	} {
+		require.Nil(t, db.AddCalificativ(profesori[0].Email, calificativ))
+	}
+	for _, calificativ := range []*Calificativ{
+		{Student: 1, Exam: "simulare", Exercitiu: "1", Varianta: "B"},
+		{Student: 1, Exam: "simulare", Exercitiu: "2a", Varianta: "B"},
+		{Student: 1, Exam: "simulare", Exercitiu: "2b", Varianta: "B"},
+	} {
+		require.Nil(t, db.AddCalificativ(profesori[1].Email, calificativ))
+	}
+
+	score, err := db.GetStudentScore(profesori[0].Email, "1")
+	require.Nil(t, err)
+	assert.Equal(t, 9.5, score.Materii[0].Punctaj)
+	require.Equal(t, 1, len(score.Sectiuni))
+	sectiuni := score.Sectiuni[0].Sectiuni
+	require.Equal(t, 2, len(sectiuni))
+	assert.Equal(t, 5.0, sectiuni[0].Punctaj)
+	assert.Equal(t, 4.5, sectiuni[1].Punctaj)
+	assert.Equal(t, 5.0, sectiuni[1].PunctajMaxim)
+	require.Equal(t, 1, len(sectiuni[1].Subsectiuni))
+	assert.Equal(t, 4.5, sectiuni[1].Subsectiuni[0].Punctaj)
+
+	stats, err := db.GetExamStatistics("simulare")
+	require.Nil(t, err)
+	require.Equal(t, 3, len(stats.Sectiuni))
+	assert.Equal(t, "II", stats.Sectiuni[2].Parinte)
+	assert.Equal(t, 4.5, stats.Sectiuni[2].Punctaj.Media)
+
+	analysis, err := db.GetItemAnalysis("simulare")
+	require.Nil(t, err)
+	assert.Equal(t, "I", analysis.Exercitii[0].Sectiune)
+}
+
+func TestDatabaseHandler_ExamLifecycle(t *testing.T) {
+	t.Parallel()
+
+	db, err := NewDatabaseHandler(createMockArgsDatabaseHandler())
+	require.Nil(t, err)
+
+	prof := &authentication.Profesor{
+		User: authentication.User{
+			Username: "prof_mate",
+			Email:    "mate@test.ro",
+			Password: "password",
+		},
+		Materie: "matematica",
+	}
+	require.Nil(t, db.CreateProfesor(prof))
+	_, err = db.CreateClass(createMockClass(prof.Username))
+	require.Nil(t, err)
+
+	inceput := time.Now().Add(time.Hour)
+	sfarsit := inceput.Add(-2 * time.Hour)
+	exam := &Exam{
+		Nume:          "simulare",
+		InceputNotare: &inceput,
+		SfarsitNotare: &sfarsit,
+		Exercitii: []Exercitiu{
+			{Numar: "1", Variante: []string{"A", "B"}, Punctaje: map[string]float64{"A": 1, "B": 3}, Materie: "matematica"},
+		},
+	}
+	assert.Equal(t, ErrInvalidGradingWindow, db.CreateExam(exam))
+	exam.SfarsitNotare = nil
+	require.Nil(t, db.CreateExam(exam))
+	assert.Equal(t, ErrInvalidGradingWindow, db.CreateExam(&Exam{Nume: "simulare", SfarsitNotare: &sfarsit}))
+
+	examDb, err := db.GetExamByName("simulare")
+	require.Nil(t, err)
+	assert.Equal(t, ExamCiorna, examDb.Status)
+
+	calificativ := &Calificativ{Student: 1, Exam: "simulare", Exercitiu: "1", Varianta: "B"}
+	assert.Equal(t, ErrGradingClosed, db.AddCalificativ(prof.Email, calificativ))
+
+	_, err = db.SetExamStatus("admin@test.ro", &ExamStatusRequest{Exam: "simulare", Status: "necunoscut"})
+	assert.Equal(t, ErrInvalidExamStatus, err)
+	_, err = db.SetExamStatus("admin@test.ro", &ExamStatusRequest{Exam: "simulare", Status: ExamNotareDeschisa})
+	assert.True(t, errors.Is(err, ErrInvalidExamTransition))
+	openGrading(t, db, "simulare")
+	assert.Equal(t, ErrGradingClosed, db.AddCalificativ(prof.Email, calificativ))
+
+	inceput = time.Now().Add(-time.Hour)
+	assert.Equal(t, ErrExamNotDraft, db.CreateExam(exam))
+	require.Nil(t, db.database.Model(&authentication.Exam{Nume: "simulare"}).Update("inceput_notare", inceput).Error)
+	require.Nil(t, db.AddCalificativ(prof.Email, calificativ))
+
+	tranzitie, err := db.SetExamStatus("admin@test.ro", &ExamStatusRequest{Exam: "simulare", Status: ExamNotareInchisa, Motiv: "rezultate tiparite"})
+	require.Nil(t, err)
+	assert.Equal(t, ExamNotareDeschisa, tranzitie.DinStatus)
+	calificativ.Varianta = "A"
+	assert.Equal(t, ErrGradingClosed, db.UpdateCalificativ(prof.Email, calificativ))
+
+	tranzitii, err := db.GetExamTranzitii("simulare")
+	require.Nil(t, err)
+	require.Equal(t, 3, len(tranzitii))
+	assert.Equal(t, ExamNotareInchisa, tranzitii[2].InStatus)
+	assert.Equal(t, "rezultate tiparite", tranzitii[2].Motiv)
+	assert.Equal(t, "admin@test.ro", tranzitii[2].Admin)
+}
+
+func TestCheckGradingWindow(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	exam := &authentication.Exam{Status: ExamNotareDeschisa}
+	assert.Nil(t, checkGradingWindow(exam, SlotCorector1, now))
+	exam.InceputNotare = &past
+	exam.SfarsitNotare = &future
+	assert.Nil(t, checkGradingWindow(exam, SlotArbitru, now))
+	exam.SfarsitNotare = &past
+	assert.Equal(t, ErrGradingClosed, checkGradingWindow(exam, SlotCorector2, now))
+	assert.Nil(t, checkGradingWindow(exam, SlotContestatie, now))
+
+	exam.Status = ExamRezultatePublicate
+	assert.Equal(t, ErrGradingClosed, checkGradingWindow(exam, SlotCorector1, now))
+	assert.Nil(t, checkGradingWindow(exam, SlotContestatie, now))
+	exam.Status = ExamPublicat
+	assert.Equal(t, ErrGradingClosed, checkGradingWindow(exam, SlotContestatie, now))
+}
+
+func TestDatabaseHandler_CloneExam(t *testing.T) {
+	t.Parallel()
+
+	db, err := NewDatabaseHandler(createMockArgsDatabaseHandler())
+	require.Nil(t, err)
+
+	prof := &authentication.Profesor{
+		User: authentication.User{
+			Username: "prof_mate",
+			Email:    "mate@test.ro",
+			Password: "password",
+		},
+		Materie: "matematica",
+	}
+	require.Nil(t, db.CreateProfesor(prof))
+	_, err = db.CreateClass(createMockClass(prof.Username))
+	require.Nil(t, err)
+	require.Nil(t, db.CreateExam(&Exam{
+		Nume:         "simulare",
+		PuncteOficiu: 1,
+		Sectiuni:     []Sectiune{{Cod: "I", Titlu: "Subiectul I"}},
+		Exercitii: []Exercitiu{
+			{
+				Numar:    "1",
+				Variante: []string{"A", "B"},
+				Punctaje: map[string]float64{"A": 1, "B": 3},
+				Rubrici:  map[string]*Rubrica{"B": {Descriere: "rezolvare completa"}},
+				Materie:  "matematica",
+				Sectiune: "I",
+			},
+			{Numar: "2", Variante: []string{"A", "B"}, Punctaje: map[string]float64{"B": 2}, Materie: "matematica", Pondere: 2},
+			{Numar: "3", Variante: []string{"A"}, Punctaje: map[string]float64{"A": 4}, Materie: "matematica"},
+		},
+	}))
+	openGrading(t, db, "simulare")
+	require.Nil(t, db.AddCalificativ(prof.Email, &Calificativ{Student: 1, Exam: "simulare", Exercitiu: "1", Varianta: "B"}))
+
+	request := &CloneExamRequest{Exam: "simulare", Nume: "simulare"}
+	_, err = db.CloneExam(request)
+	assert.Equal(t, ErrExamExists, err)
+	request.Nume = "simulare2"
+	request.Excluse = []string{"4"}
+	_, err = db.CloneExam(request)
+	assert.True(t, errors.Is(err, ErrInvalidCloneRequest))
+	request.Excluse = []string{"3"}
+	request.Redenumiri = map[string]string{"2": "1"}
+	_, err = db.CloneExam(request)
+	assert.True(t, errors.Is(err, ErrInvalidCloneRequest))
+	request.Redenumiri = map[string]string{"2": "2a"}
+	clone, err := db.CloneExam(request)
+	require.Nil(t, err)
+	assert.Equal(t, "simulare2", clone.Nume)
+
+	examDb, err := db.GetExamByName("simulare2")
+	require.Nil(t, err)
+	assert.Equal(t, ExamCiorna, examDb.Status)
+	assert.Equal(t, 1.0, examDb.PuncteOficiu)
+
+	copied, err := loadExamDefinition(db.database, "simulare2")
+	require.Nil(t, err)
+	assert.Equal(t, []Sectiune{{Cod: "I", Titlu: "Subiectul I"}}, copied.Sectiuni)
+	require.Equal(t, 2, len(copied.Exercitii))
+	assert.Equal(t, "I", copied.Exercitii[0].Sectiune)
+	assert.Equal(t, "rezolvare completa", copied.Exercitii[0].Rubrici["B"].Descriere)
+	assert.Equal(t, "2a", copied.Exercitii[1].Numar)
+	assert.Equal(t, 2.0, copied.Exercitii[1].Pondere)
+	assert.Equal(t, 2.0, copied.Exercitii[1].Punctaje["B"])
+
+	var calificative int64
+	require.Nil(t, db.database.Table("calificativs").Where("exam = ?", "simulare2").Count(&calificative).Error)
+	assert.Equal(t, int64(0), calificative)
+
+	_, err = db.MoveExamStudents(&MoveStudentsRequest{Exam: "simulare", NouExam: "simulare3"})
+	assert.NotNil(t, err)
+	moved, err := db.MoveExamStudents(&MoveStudentsRequest{Exam: "simulare", NouExam: "simulare2", Clase: []string{"8B"}})
+	require.Nil(t, err)
+	assert.Equal(t, int64(0), moved.Mutati)
+	moved, err = db.MoveExamStudents(&MoveStudentsRequest{Exam: "simulare", NouExam: "simulare2"})
+	require.Nil(t, err)
+	assert.Equal(t, int64(2), moved.Mutati)
+	student, err := db.GetStudentByID(1)
+	require.Nil(t, err)
+	assert.Equal(t, "simulare2", student.ExamStiinta)
+	assert.Equal(t, "simulare2", student.ExamLimba)
+}
+
+func TestDatabaseHandler_AddCalificative(t *testing.T) {
+	t.Parallel()
+
+	db, err := NewDatabaseHandler(createMockArgsDatabaseHandler())
+	require.Nil(t, err)
+
+	prof := &authentication.Profesor{
+		User: authentication.User{
+			Username: "prof_mate",
+			Email:    "mate@test.ro",
+			Password: "password",
+		},
+		Materie: "matematica",
+	}
+	require.Nil(t, db.CreateProfesor(prof))
+	class := createMockClass(prof.Username)
+	class.Elevi = append(class.Elevi, ClassStudent{Nume: "Ionescu", Prenume: "Ana", ExamStiinta: "simulare"})
+	result, err := db.CreateClass(class)
+	require.Nil(t, err)
+	first, second := result.Created[0].ID, result.Created[1].ID
+	require.Nil(t, db.CreateExam(&Exam{
+		Nume: "simulare",
+		Exercitii: []Exercitiu{
+			{Numar: "1", Variante: []string{"A", "B"}, Punctaje: map[string]float64{"A": 1, "B": 3}, Materie: "matematica"},
+			{Numar: "2", Variante: []string{"A", "B"}, Punctaje: map[string]float64{"B": 2}, Materie: "matematica"},
+		},
+	}))
+	openGrading(t, db, "simulare")
+
+	batch := &CalificativBatch{
+		Calificative: []*Calificativ{
+			{Student: first, Exam: "simulare", Exercitiu: "1", Varianta: "B"},
+			{Student: second, Exam: "simulare", Exercitiu: "1", Varianta: "A"},
+			{Student: first, Exam: "simulare", Exercitiu: "1", Varianta: "A"},
+			{Student: first, Exam: "simulare", Exercitiu: "9", Varianta: "A"},
+			{Student: first, Exam: "simulare", Exercitiu: "2", Varianta: "C"},
+		},
+	}
+	batchResult, err := db.AddCalificative(prof.Email, batch)
+	require.Nil(t, err)
+	assert.Equal(t, 2, batchResult.Salvate)
+	assert.Equal(t, 3, batchResult.Respinse)
+	require.Equal(t, 5, len(batchResult.Rezultate))
+	assert.True(t, batchResult.Rezultate[0].Salvat)
+	assert.Equal(t, SlotCorector1, batchResult.Rezultate[0].Slot)
+	assert.Equal(t, "calificativ duplicat in cerere", batchResult.Rezultate[2].Reason)
+	assert.NotEmpty(t, batchResult.Rezultate[3].Reason)
+	assert.Equal(t, "varianta invalida", batchResult.Rezultate[4].Reason)
+
+	score, err := db.GetStudentScore(prof.Email, "1")
+	require.Nil(t, err)
+	assert.Equal(t, 3.0, score.Materii[0].Punctaj)
+
+	batch = &CalificativBatch{
+		Calificative: []*Calificativ{
+			{Student: first, Exam: "simulare", Exercitiu: "1", Varianta: "A"},
+			{Student: first, Exam: "simulare", Exercitiu: "2", Varianta: "B"},
+		},
+	}
+	batchResult, err = db.AddCalificative(prof.Email, batch)
+	require.Nil(t, err)
+	assert.Equal(t, "calificativ already exists", batchResult.Rezultate[0].Reason)
+	assert.True(t, batchResult.Rezultate[1].Salvat)
+	score, _ = db.GetStudentScore(prof.Email, "1")
+	assert.Equal(t, 5.0, score.Materii[0].Punctaj)
+
+	batch.Upsert = true
+	batchResult, err = db.AddCalificative(prof.Email, batch)
+	require.Nil(t, err)
+	assert.Equal(t, 2, batchResult.Salvate)
+	score, _ = db.GetStudentScore(prof.Email, "1")
+	assert.Equal(t, 3.0, score.Materii[0].Punctaj)
+
+	batch = &CalificativBatch{
+		AllOrNothing: true,
+		Calificative: []*Calificativ{
+			{Student: second, Exam: "simulare", Exercitiu: "2", Varianta: "B"},
+			{Student: second, Exam: "simulare", Exercitiu: "3", Varianta: "B"},
+		},
+	}
+	batchResult, err = db.AddCalificative(prof.Email, batch)
+	require.Nil(t, err)
+	assert.True(t, batchResult.RolledBack)
+	assert.Equal(t, 0, batchResult.Salvate)
+	assert.Equal(t, "lot anulat", batchResult.Rezultate[0].Reason)
+	assert.NotEqual(t, "lot anulat", batchResult.Rezultate[1].Reason)
+	calificative, err := db.GetCalificative(prof.Email, "2")
+	require.Nil(t, err)
+	assert.Equal(t, 1, len(calificative))
+}
+
+func TestDatabaseHandler_Absente(t *testing.T) {
+	t.Parallel()
+
+	db, err := NewDatabaseHandler(createMockArgsDatabaseHandler())
+	require.Nil(t, err)
+
+	prof := &authentication.Profesor{
+		User: authentication.User{
+			Username: "prof_mate",
+			Email:    "mate@test.ro",
+			Password: "password",
+		},
+		Materie: "matematica",
+	}
+	require.Nil(t, db.CreateProfesor(prof))
+	class := createMockClass(prof.Username)
+	class.Elevi[0].ExamLimba = "romana"
+	result, err := db.CreateClass(class)
+	require.Nil(t, err)
+	student := result.Created[0].ID
+	require.Nil(t, db.CreateExam(&Exam{
+		Nume:      "simulare",
+		Exercitii: []Exercitiu{{Numar: "1", Variante: []string{"A"}, Materie: "matematica"}},
+	}))
+	openGrading(t, db, "simulare")
+
+	err = db.SetAbsent("admin@test.ro", &AbsentStatus{Id: student, Exam: "evaluare", Absent: true})
+	assert.Equal(t, ErrStudentNotInExam, err)
+	require.Nil(t, db.SetAbsent("admin@test.ro", &AbsentStatus{Id: student, Exam: "simulare", Absent: true, Motiv: "medical"}))
+
+	students, err := db.GetStudentsByClass("8A", "simulare")
+	require.Nil(t, err)
+	require.Equal(t, 1, len(students))
+	assert.True(t, students[0].Absent)
+	assert.Equal(t, "medical", students[0].Motiv)
+	students, err = db.GetStudentsByClass("8A", "romana")
+	require.Nil(t, err)
+	assert.False(t, students[0].Absent)
+
+	calificativ := &Calificativ{Student: student, Exam: "simulare", Exercitiu: "1", Varianta: "A"}
+	assert.Equal(t, ErrStudentAbsent, db.AddCalificativ(prof.Email, calificativ))
+	statistics, err := db.GetExamStatistics("simulare")
+	require.Nil(t, err)
+	assert.Equal(t, 0, statistics.Elevi)
+
+	require.Nil(t, db.SetAbsent("admin@test.ro", &AbsentStatus{Id: student, Exam: "simulare", Absent: false}))
+	require.Nil(t, db.AddCalificativ(prof.Email, calificativ))
+}
+
+func TestDatabaseHandler_CompetencyReport(t *testing.T) {
+	t.Parallel()
+
+	db, err := NewDatabaseHandler(createMockArgsDatabaseHandler())
+	require.Nil(t, err)
+
+	prof := &authentication.Profesor{
+		User: authentication.User{
+			Username: "prof_mate",
+			Email:    "mate@test.ro",
+			Password: "password",
+		},
+		Materie: "matematica",
+	}
+	require.Nil(t, db.CreateProfesor(prof))
+	class := createMockClass(prof.Username)
+	class.Elevi = append(class.Elevi, ClassStudent{Nume: "Ionescu", Prenume: "Ana", ExamStiinta: "simulare"})
+	result, err := db.CreateClass(class)
+	require.Nil(t, err)
+	first, second := result.Created[0].ID, result.Created[1].ID
+
+	exam := &Exam{
+		Nume: "simulare",
+		Exercitii: []Exercitiu{
+			{Numar: "1", Variante: []string{"A", "B"}, Punctaje: map[string]float64{"A": 0, "B": 4}, Materie: "matematica", Competente: []string{"1.1", "2.1"}},
+			{Numar: "2", Variante: []string{"A", "B"}, Punctaje: map[string]float64{"A": 0, "B": 6}, Materie: "matematica", Competente: []string{"2.1"}},
+		},
+	}
+	exam.Exercitii[1].Competente = append(exam.Exercitii[1].Competente, "2.1")
+	assert.True(t, errors.Is(db.CreateExam(exam), scoring.ErrInvalidCompetency))
+	exam.Exercitii[1].Competente = []string{"2.1"}
+	require.Nil(t, db.CreateExam(exam))
+	openGrading(t, db, "simulare")
+	require.Nil(t, db.SetCompetente([]*Competenta{{Materie: "matematica", Cod: "2.1", Descriere: "Calcule"}}))
+
+	for _, calificativ := range []*Calificativ{
+		{Student: first, Exam: "simulare", Exercitiu: "1", Varianta: "B"},
+		{Student: first, Exam: "simulare", Exercitiu: "2", Varianta: "A"},
+		{Student: second, Exam: "simulare", Exercitiu: "1", Varianta: "B"},
+		{Student: second, Exam: "simulare", Exercitiu: "2", Varianta: "B"},
+	} {
+		require.Nil(t, db.AddCalificativ(prof.Email, calificativ))
+	}
+
+	report, err := db.GetCompetencyReport(prof.Email, "8A", "simulare")
+	require.Nil(t, err)
+	assert.Equal(t, 2, report.Elevi)
+	require.Equal(t, 2, len(report.Competente))
+	assert.Equal(t, "2.1", report.Competente[0].Cod)
+	assert.Equal(t, "Calcule", report.Competente[0].Descriere)
+	assert.Equal(t, []string{"1", "2"}, report.Competente[0].Exercitii)
+	assert.Equal(t, 10.0, report.Competente[0].PunctajMaxim)
+	assert.Equal(t, 70.0, report.Competente[0].Stapanire.Media)
+	assert.Equal(t, "1.1", report.Competente[1].Cod)
+	assert.Equal(t, 100.0, report.Competente[1].Stapanire.Media)
+
+	require.Equal(t, 2, len(report.Profiluri))
+	profil := report.Profiluri[1]
+	assert.Equal(t, first, profil.Student)
+	require.NotNil(t, profil.Nota)
+	require.Equal(t, 2, len(profil.Competente))
+	assert.Equal(t, "2.1", profil.Competente[0].Cod)
+	assert.Equal(t, 40.0, profil.Competente[0].Stapanire)
+	assert.Equal(t, 100.0, report.Profiluri[0].Competente[1].Stapanire)
+
+	clone, err := db.CloneExam(&CloneExamRequest{Exam: "simulare", Nume: "simulare2"})
+	require.Nil(t, err)
+	assert.Equal(t, []string{"1.1", "2.1"}, clone.Exercitii[0].Competente)
+
+	_, err = db.GetCompetencyReport("altcineva@test.ro", "8A", "simulare")
+	assert.NotNil(t, err)
+}
+
+func TestDatabaseHandler_Comentarii(t *testing.T) {
+	t.Parallel()
+
+	db, err := NewDatabaseHandler(createMockArgsDatabaseHandler())
+	require.Nil(t, err)
+
+	prof := &authentication.Profesor{
+		User: authentication.User{
+			Username: "prof_mate",
+			Email:    "mate@test.ro",
+			Password: "password",
+		},
+		Materie: "matematica",
+	}
+	require.Nil(t, db.CreateProfesor(prof))
+	altProf := &authentication.Profesor{
+		User: authentication.User{
+			Username: "prof_fizica",
+			Email:    "fizica@test.ro",
+			Password: "password",
+		},
+		Materie: "fizica",
+	}
+	require.Nil(t, db.CreateProfesor(altProf))
+	result, err := db.CreateClass(createMockClass(prof.Username))
+	require.Nil(t, err)
+	student := result.Created[0].ID
+	require.Nil(t, db.CreateExam(&Exam{
+		Nume:      "simulare",
+		Exercitii: []Exercitiu{{Numar: "1", Variante: []string{"A", "B"}, Punctaje: map[string]float64{"B": 2}, Materie: "matematica"}},
+	}))
+	openGrading(t, db, "simulare")
+	require.Nil(t, db.AddCalificativ(prof.Email, &Calificativ{Student: student, Exam: "simulare", Exercitiu: "1", Varianta: "B"}))
+
+	request := &ComentariuRequest{Student: student, Exam: "simulare", Exercitiu: "1", Text: "Rezolvare corecta"}
+	comentariu, err := db.SetComentariu(prof.Email, request)
+	require.Nil(t, err)
+	assert.Equal(t, prof.Email, comentariu.Autor)
+	_, err = db.SetComentariu(altProf.Email, request)
+	assert.NotNil(t, err)
+	_, err = db.SetComentariu(prof.Email, &ComentariuRequest{Student: student, Exam: "evaluare", Text: "Bine"})
+	assert.Equal(t, ErrStudentNotInExam, err)
+	_, err = db.SetComentariu(prof.Email, &ComentariuRequest{Student: student, Exam: "simulare", Text: "Lucrare buna"})
+	require.Nil(t, err)
+
+	request.Text = "Rezolvare corecta, dar incompleta"
+	updated, err := db.SetComentariu(prof.Email, request)
+	require.Nil(t, err)
+	assert.Equal(t, request.Text, updated.Text)
+	assert.Equal(t, comentariu.CreatedAt.Unix(), updated.CreatedAt.Unix())
+
+	calificative, err := db.GetCalificative(prof.Email, "1")
+	require.Nil(t, err)
+	require.Equal(t, 1, len(calificative))
+	require.NotNil(t, calificative[0].Comentariu)
+	assert.Equal(t, request.Text, calificative[0].Comentariu.Text)
+
+	score, err := db.GetStudentScore(prof.Email, "1")
+	require.Nil(t, err)
+	require.Equal(t, 2, len(score.Comentarii))
+	assert.Equal(t, "", score.Comentarii[0].Exercitiu)
+	grades, err := db.GetStudentGrades(prof.Email, "1")
+	require.Nil(t, err)
+	require.Equal(t, 1, len(grades.Note[0].Comentarii))
+	assert.Equal(t, "Lucrare buna", grades.Note[0].Comentarii[0].Text)
+
+	request.Text = " "
+	comentariu, err = db.SetComentariu(prof.Email, request)
+	require.Nil(t, err)
+	assert.Nil(t, comentariu)
+	score, _ = db.GetStudentScore(prof.Email, "1")
+	assert.Equal(t, 1, len(score.Comentarii))
+
+	for _, status := range []string{ExamNotareInchisa, ExamRezultatePublicate} {
+		_, err = db.SetExamStatus("admin@test.ro", &ExamStatusRequest{Exam: "simulare", Status: status})
+		require.Nil(t, err)
+	}
+	request.Text = "Prea tarziu"
+	_, err = db.SetComentariu(prof.Email, request)
+	assert.Equal(t, ErrComentariiInchise, err)
+}
+
+func TestDatabaseHandler_Evolution(t *testing.T) {
+	t.Parallel()
+
+	db, err := NewDatabaseHandler(createMockArgsDatabaseHandler())
+	require.Nil(t, err)
+
+	prof := &authentication.Profesor{
+		User: authentication.User{
+			Username: "prof_mate",
+			Email:    "mate@test.ro",
+			Password: "password",
+		},
+		Materie: "matematica",
+	}
+	require.Nil(t, db.CreateProfesor(prof))
+	class := &Class{
+		Nume:     "8A",
+		ProfMate: prof.Username,
+		Elevi: []ClassStudent{
+			{Nume: "Popescu", Prenume: "Ion", ExamStiinta: "toamna"},
+			{Nume: "Ionescu", Prenume: "Ana", ExamStiinta: "toamna"},
+		},
+	}
+	result, err := db.CreateClass(class)
+	require.Nil(t, err)
+	first, second := result.Created[0].ID, result.Created[1].ID
+
+	// the spring exam sorts first by name, but its grading starts after the autumn one
+	toamna, primavara := time.Now().Add(-48*time.Hour), time.Now().Add(-24*time.Hour)
+	for nume, inceput := range map[string]*time.Time{"toamna": &toamna, "primavara": &primavara} {
+		require.Nil(t, db.CreateExam(&Exam{
+			Nume:          nume,
+			InceputNotare: inceput,
+			Exercitii: []Exercitiu{
+				{Numar: "1", Variante: []string{"A", "B", "C"}, Punctaje: map[string]float64{"B": 2, "C": 4}, Materie: "matematica"},
+			},
+		}))
+		openGrading(t, db, nume)
+	}
+	require.Nil(t, db.AddCalificativ(prof.Email, &Calificativ{Student: first, Exam: "toamna", Exercitiu: "1", Varianta: "B"}))
+	require.Nil(t, db.AddCalificativ(prof.Email, &Calificativ{Student: second, Exam: "toamna", Exercitiu: "1", Varianta: "C"}))
+	_, err = db.MoveExamStudents(&MoveStudentsRequest{Exam: "toamna", NouExam: "primavara"})
+	require.Nil(t, err)
+	require.Nil(t, db.AddCalificativ(prof.Email, &Calificativ{Student: first, Exam: "primavara", Exercitiu: "1", Varianta: "C"}))
+
+	evolution, err := db.GetStudentEvolution(prof.Email, "1")
+	require.Nil(t, err)
+	require.Equal(t, 2, len(evolution.Exame))
+	assert.Equal(t, "toamna", evolution.Exame[0].Exam)
+	assert.Nil(t, evolution.Exame[0].DeltaNota)
+	assert.Equal(t, 50.0, evolution.Exame[0].Materii[0].Procent)
+	assert.Equal(t, "primavara", evolution.Exame[1].Exam)
+	require.NotNil(t, evolution.Exame[1].DeltaNota)
+	assert.Equal(t, evolution.Exame[1].Nota-evolution.Exame[0].Nota, *evolution.Exame[1].DeltaNota)
+	require.NotNil(t, evolution.Exame[1].Materii[0].Delta)
+	assert.Equal(t, 50.0, *evolution.Exame[1].Materii[0].Delta)
+
+	classEvolution, err := db.GetClassEvolution(prof.Email, "8A")
+	require.Nil(t, err)
+	require.Equal(t, 2, len(classEvolution.Exame))
+	assert.Equal(t, 2, classEvolution.Exame[0].Elevi)
+	assert.Equal(t, 75.0, classEvolution.Exame[0].Materii[0].Procent)
+	assert.Equal(t, 1, classEvolution.Exame[1].Elevi)
+	assert.Equal(t, 25.0, *classEvolution.Exame[1].Materii[0].Delta)
+
+	_, err = db.GetClassEvolution("altcineva@test.ro", "8A")
+	assert.NotNil(t, err)
+}
+
+func TestDatabaseHandler_Sesiuni(t *testing.T) {
+	t.Parallel()
+
+	db, err := NewDatabaseHandler(createMockArgsDatabaseHandler())
+	require.Nil(t, err)
+
+	prof := &authentication.Profesor{
+		User: authentication.User{
+			Username: "prof_mate",
+			Email:    "mate@test.ro",
+			Password: "password",
+		},
+		Materie: "matematica",
+	}
+	require.Nil(t, db.CreateProfesor(prof))
+	expiresAt := time.Now().Add(time.Hour)
+	require.Nil(t, db.CreateSesiune(&authentication.Sesiune{ID: "s1", Email: prof.Email, RefreshHash: "h1", ExpiresAt: expiresAt}))
+	require.Nil(t, db.CreateSesiune(&authentication.Sesiune{ID: "s2", Email: prof.Email, RefreshHash: "h2", ExpiresAt: expiresAt}))
+	require.Nil(t, db.CreateSesiune(&authentication.Sesiune{ID: "s3", Email: prof.Email, RefreshHash: "h3", ExpiresAt: time.Now().Add(-time.Hour)}))
+	require.Nil(t, db.CreateSesiune(&authentication.Sesiune{ID: "elev", Email: prof.Email, Student: 7, RefreshHash: "h4", ExpiresAt: expiresAt}))
+
+	active, err := db.IsSesiuneActiva("s1")
+	require.Nil(t, err)
+	assert.True(t, active)
+	active, _ = db.IsSesiuneActiva("s3")
+	assert.False(t, active)
+	active, _ = db.IsSesiuneActiva("")
+	assert.False(t, active)
+
+	sesiune, err := db.RefreshSesiune("h1", "h1-bis", expiresAt.Add(time.Hour))
+	require.Nil(t, err)
+	assert.Equal(t, "s1", sesiune.ID)
+	assert.Equal(t, prof.Email, sesiune.Email)
+	_, err = db.RefreshSesiune("h1", "h1-ter", expiresAt)
+	assert.Equal(t, ErrInvalidRefreshToken, err)
+	_, err = db.RefreshSesiune("h3", "h3-bis", expiresAt)
+	assert.Equal(t, ErrInvalidRefreshToken, err)
+
+	require.Nil(t, db.RevokeSesiune("h1-bis"))
+	assert.Equal(t, ErrInvalidRefreshToken, db.RevokeSesiune("h1-bis"))
+	active, _ = db.IsSesiuneActiva("s1")
+	assert.False(t, active)
+	_, err = db.RefreshSesiune("h1-bis", "h1-ter", expiresAt)
+	assert.Equal(t, ErrInvalidRefreshToken, err)
+
+	revocate, err := db.RevokeSesiuniProfesor(prof.Email)
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), revocate)
+	active, _ = db.IsSesiuneActiva("s2")
+	assert.False(t, active)
+	active, _ = db.IsSesiuneActiva("elev")
+	assert.True(t, active)
+	sesiune, err = db.RefreshSesiune("h4", "h4-bis", expiresAt)
+	require.Nil(t, err)
+	assert.Equal(t, uint(7), sesiune.Student)
+	_, err = db.RevokeSesiuniProfesor("nimeni@test.ro")
+	assert.NotNil(t, err)
+}
+
+func TestDatabaseHandler_Roluri(t *testing.T) {
+	t.Parallel()
+
+	db, err := NewDatabaseHandler(createMockArgsDatabaseHandler())
+	require.Nil(t, err)
+
+	newProfesor := func(username string, roluri ...string) *authentication.Profesor {
+		prof := &authentication.Profesor{
+			User: authentication.User{
+				Username: username,
+				Email:    username + "@test.ro",
+				Password: "password",
+			},
+			Materie: "matematica",
+		}
+		require.Nil(t, db.CreateProfesor(prof, roluri...))
+		return prof
+	}
+	admin := newProfesor("admin", authentication.RolAdmin)
+	mate := newProfesor("mate")
+	alt := newProfesor("alt")
+	_, err = db.CreateClass(createMockClass(mate.Username))
+	require.Nil(t, err)
+	err = db.CreateProfesor(&authentication.Profesor{User: authentication.User{Username: "x"}}, "portar")
+	assert.True(t, errors.Is(err, ErrInvalidRol))
+
+	classes, err := db.GetAllClasses(alt.Email)
+	require.Nil(t, err)
+	assert.Empty(t, classes)
+	_, err = db.GetClassEvolution(alt.Email, "8A")
+	assert.NotNil(t, err)
+
+	err = db.AssignRol(&RolRequest{Email: alt.Email, Rol: authentication.RolDiriginte})
+	assert.True(t, errors.Is(err, ErrInvalidRol))
+	err = db.AssignRol(&RolRequest{Email: alt.Email, Rol: authentication.RolStudent})
+	assert.True(t, errors.Is(err, ErrInvalidRol))
+	err = db.AssignRol(&RolRequest{Email: alt.Email, Rol: authentication.RolDirector, Clasa: "8A"})
+	assert.True(t, errors.Is(err, ErrInvalidRol))
+	assert.NotNil(t, db.AssignRol(&RolRequest{Email: alt.Email, Rol: authentication.RolDiriginte, Clasa: "9Z"}))
+	require.Nil(t, db.AssignRol(&RolRequest{Email: alt.Email, Rol: authentication.RolDiriginte, Clasa: "8A"}))
+	require.Nil(t, db.AssignRol(&RolRequest{Email: alt.Email, Rol: authentication.RolDiriginte, Clasa: "8A"}))
+
+	classes, err = db.GetAllClasses(alt.Email)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"8A"}, classes)
+	_, err = db.GetClassEvolution(alt.Email, "8A")
+	assert.Nil(t, err)
+
+	require.Nil(t, db.RevokeRol(&RolRequest{Email: alt.Email, Rol: authentication.RolDiriginte, Clasa: "8A"}))
+	assert.Equal(t, ErrRolNotFound, db.RevokeRol(&RolRequest{Email: alt.Email, Rol: authentication.RolDiriginte, Clasa: "8A"}))
+	require.Nil(t, db.AssignRol(&RolRequest{Email: alt.Email, Rol: authentication.RolDirector}))
+	classes, err = db.GetAllClasses(alt.Email)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"8A"}, classes)
+
+	roluri, err := db.GetRoluri(alt.Email)
+	require.Nil(t, err)
+	assert.Equal(t, []string{authentication.RolDirector, authentication.RolProfesor}, authentication.RoleNames(roluri))
+
+	assert.Equal(t, ErrLastAdmin, db.RevokeRol(&RolRequest{Email: admin.Email, Rol: authentication.RolAdmin}))
+	require.Nil(t, db.AssignRol(&RolRequest{Email: mate.Email, Rol: authentication.RolAdmin}))
+	require.Nil(t, db.RevokeRol(&RolRequest{Email: admin.Email, Rol: authentication.RolAdmin}))
+}
+
+func TestDatabaseHandler_Invitatii(t *testing.T) {
+	t.Parallel()
+
+	db, err := NewDatabaseHandler(createMockArgsDatabaseHandler())
+	require.Nil(t, err)
+
+	newProfesor := func(username string) *authentication.Profesor {
+		return &authentication.Profesor{
+			User: authentication.User{
+				Username: username,
+				Email:    username + "@test.ro",
+				Password: "password",
+			},
+		}
+	}
+
+	require.Nil(t, db.CreateInvitatie(&authentication.Invitatie{
+		ID:        "setup",
+		Rol:       authentication.RolAdmin,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}))
+	exists, err := db.HasAdmin()
+	require.Nil(t, err)
+	assert.False(t, exists)
+	require.Nil(t, db.RedeemInvitatie("setup", newProfesor("director")))
+	assert.Equal(t, ErrInvalidInvitatie, db.RedeemInvitatie("setup", newProfesor("director2")))
+	roluri, err := db.GetRoluri("director@test.ro")
+	require.Nil(t, err)
+	assert.Equal(t, []string{authentication.RolAdmin}, authentication.RoleNames(roluri))
+	exists, err = db.HasAdmin()
+	require.Nil(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, ErrAdminExists, db.CreateFirstAdmin(newProfesor("intrus")))
+
+	require.Nil(t, db.CreateInvitatie(&authentication.Invitatie{
+		ID:        "setup2",
+		Rol:       authentication.RolAdmin,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}))
+	require.Nil(t, db.CreateInvitatie(&authentication.Invitatie{
+		ID:        "adjunct",
+		Email:     "adjunct@test.ro",
+		Rol:       authentication.RolAdmin,
+		Admin:     "director@test.ro",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}))
+	require.Nil(t, db.CreateInvitatie(&authentication.Invitatie{
+		ID:        "mate",
+		Email:     "mate@test.ro",
+		Rol:       authentication.RolProfesor,
+		Admin:     "director@test.ro",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}))
+	require.Nil(t, db.CreateInvitatie(&authentication.Invitatie{
+		ID:        "expirata",
+		Rol:       authentication.RolAdmin,
+		ExpiresAt: time.Now().Add(-time.Minute),
+	}))
+
+	assert.Equal(t, ErrAdminExists, db.RedeemInvitatie("setup2", newProfesor("intrus")))
+	assert.Equal(t, ErrInvalidInvitatie, db.RedeemInvitatie("expirata", newProfesor("expirat")))
+	assert.Equal(t, ErrInvalidInvitatie, db.RedeemInvitatie("necunoscuta", newProfesor("necunoscut")))
+	assert.Equal(t, ErrInvalidInvitatie, db.RedeemInvitatie("mate", newProfesor("alt")))
+	_, err = db.GetProfesorByEmail("intrus@test.ro")
+	assert.NotNil(t, err)
+
+	require.Nil(t, db.RedeemInvitatie("adjunct", newProfesor("adjunct")))
+	roluri, err = db.GetRoluri("adjunct@test.ro")
+	require.Nil(t, err)
+	assert.Equal(t, []string{authentication.RolAdmin}, authentication.RoleNames(roluri))
+
+	mate := newProfesor("mate")
+	require.Nil(t, db.RedeemInvitatie("mate", mate))
+	roluri, err = db.GetRoluri(mate.Email)
+	require.Nil(t, err)
+	assert.Equal(t, []string{authentication.RolProfesor}, authentication.RoleNames(roluri))
+	prof, err := db.GetProfesorByEmail(mate.Email)
+	require.Nil(t, err)
+	assert.Nil(t, prof.CheckPassword("password"))
+}
+
+func TestDatabaseHandler_StudentRezultate(t *testing.T) {
+	t.Parallel()
+
+	db, err := NewDatabaseHandler(createMockArgsDatabaseHandler())
+	require.Nil(t, err)
+
+	prof := &authentication.Profesor{
+		User: authentication.User{
+			Username: "prof_mate",
+			Email:    "mate@test.ro",
+			Password: "password",
+		},
+		Materie: "matematica",
+	}
+	require.Nil(t, db.CreateProfesor(prof))
+	result, err := db.CreateClass(createMockClass(prof.Username))
+	require.Nil(t, err)
+	student := result.Created[0]
+	require.Nil(t, db.CreateExam(&Exam{
+		Nume: "simulare",
+		Exercitii: []Exercitiu{
+			{Numar: "1", Variante: []string{"A", "B"}, Punctaje: map[string]float64{"B": 2}, Materie: "matematica"},
+			{Numar: "2", Variante: []string{"A", "B"}, Punctaje: map[string]float64{"B": 3}, Materie: "matematica"},
+		},
+	}))
+	openGrading(t, db, "simulare")
+	require.Nil(t, db.AddCalificativ(prof.Email, &Calificativ{Student: student.ID, Exam: "simulare", Exercitiu: "1", Varianta: "B"}))
+	require.Nil(t, db.AddCalificativ(prof.Email, &Calificativ{Student: student.ID, Exam: "simulare", Exercitiu: "2", Varianta: "A"}))
+	_, err = db.SetComentariu(prof.Email, &ComentariuRequest{Student: student.ID, Exam: "simulare", Exercitiu: "2", Text: "Lipseste justificarea"})
+	require.Nil(t, err)
+	_, err = db.SetComentariu(prof.Email, &ComentariuRequest{Student: student.ID, Exam: "simulare", Text: "Lucrare ordonata"})
+	require.Nil(t, err)
+
+	found, err := db.GetStudentByUsername(student.Username)
+	require.Nil(t, err)
+	assert.Equal(t, student.ID, found.ID)
+	assert.Nil(t, found.CheckPassword(student.Password))
+
+	exame, err := db.GetStudentExame(student.ID)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(exame))
+	assert.Equal(t, ExamNotareDeschisa, exame[0].Status)
+	assert.False(t, exame[0].RezultatePublicate)
+	_, err = db.GetStudentRezultate(student.ID, "simulare")
+	assert.Equal(t, ErrRezultateNepublicate, err)
+	_, err = db.FileStudentContestatie(student.ID, &StudentContestatieRequest{Exam: "simulare", Materie: "matematica"})
+	assert.Equal(t, ErrRezultateNepublicate, err)
+
+	for _, status := range []string{ExamNotareInchisa, ExamRezultatePublicate} {
+		_, err = db.SetExamStatus("admin@test.ro", &ExamStatusRequest{Exam: "simulare", Status: status})
+		require.Nil(t, err)
+	}
+	_, err = db.GetStudentRezultate(student.ID, "evaluare")
+	assert.Equal(t, ErrStudentNotInExam, err)
+
+	rezultate, err := db.GetStudentRezultate(student.ID, "simulare")
+	require.Nil(t, err)
+	require.NotNil(t, rezultate.Nota)
+	assert.Equal(t, 2.0, rezultate.Nota.Punctaj)
+	require.Equal(t, 2, len(rezultate.Exercitii))
+	assert.Equal(t, "1", rezultate.Exercitii[0].Numar)
+	assert.Equal(t, 2.0, rezultate.Exercitii[0].Puncte)
+	assert.Equal(t, 3.0, rezultate.Exercitii[1].PunctajMaxim)
+	require.Equal(t, 1, len(rezultate.Exercitii[1].Variante))
+	assert.Equal(t, "A", rezultate.Exercitii[1].Variante[0].Nume)
+	require.Equal(t, 2, len(rezultate.Comentarii))
+	assert.Equal(t, "Lucrare ordonata", rezultate.Comentarii[0].Text)
+	assert.Equal(t, "Lipseste justificarea", rezultate.Comentarii[1].Text)
+	assert.Empty(t, rezultate.Comentarii[1].Autor)
+	assert.Empty(t, rezultate.Contestatii)
+
+	contestatie, err := db.FileStudentContestatie(student.ID, &StudentContestatieRequest{Exam: "simulare", Materie: "matematica", Motiv: "ex. 2"})
+	require.Nil(t, err)
+	assert.Equal(t, student.ID, contestatie.Student)
+	assert.Equal(t, student.Username, contestatie.DepusaDe)
+	rezultate, err = db.GetStudentRezultate(student.ID, "simulare")
+	require.Nil(t, err)
+	require.Equal(t, 1, len(rezultate.Contestatii))
+	assert.Equal(t, ContestatieDepusa, rezultate.Contestatii[0].Status)
+
+	reevaluator := &authentication.Profesor{
+		User:    authentication.User{Username: "reevaluator", Email: "reevaluator@test.ro", Password: "password"},
+		Materie: "matematica",
+	}
+	require.Nil(t, db.CreateProfesor(reevaluator))
+	require.Nil(t, db.AssignContestatie(&ContestatieAssignment{Student: student.ID, Exam: "simulare", Materie: "matematica", Profesor: reevaluator.Email}))
+	require.Nil(t, db.AddCalificativ(reevaluator.Email, &Calificativ{Student: student.ID, Exam: "simulare", Exercitiu: "1", Varianta: "B"}))
+	require.Nil(t, db.AddCalificativ(reevaluator.Email, &Calificativ{Student: student.ID, Exam: "simulare", Exercitiu: "2", Varianta: "B"}))
+	_, err = db.ResolveContestatie(&ContestatieRequest{Student: student.ID, Exam: "simulare", Materie: "matematica"})
+	require.Nil(t, err)
+
+	rezultate, err = db.GetStudentRezultate(student.ID, "simulare")
+	require.Nil(t, err)
+	assert.Equal(t, 5.0, rezultate.Nota.Punctaj)
+	require.Equal(t, 1, len(rezultate.Comentarii))
+	assert.Equal(t, "Lucrare ordonata", rezultate.Comentarii[0].Text)
+	require.Equal(t, 1, len(rezultate.Contestatii))
+	assert.True(t, rezultate.Contestatii[0].Aplicata)
+}
+
+func TestDatabaseHandler_CreateClass(t *testing.T) {
+	t.Parallel()
+
+	t.Run("duplicate student should be rejected", func(t *testing.T) {
+		t.Parallel()
+
+		db, err := NewDatabaseHandler(createMockArgsDatabaseHandler())
+		require.Nil(t, err)
+
+		class := createMockClass("prof")
+		class.Elevi = append(class.Elevi, class.Elevi[0], ClassStudent{Nume: "Ionescu"})
+		result, err := db.CreateClass(class)
+		require.Nil(t, err)
+		assert.False(t, result.RolledBack)
+		assert.Equal(t, 1, len(result.Created))
+		require.Equal(t, 2, len(result.Rejected))
+		assert.Equal(t, "missing nume or prenume", result.Rejected[0].Reason)
+
+		students, err := db.GetStudentsByClass(class.Nume, "")
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(students))
+	})
+	t.Run("all or nothing should roll back the class", func(t *testing.T) {
+		t.Parallel()
+
+		db, err := NewDatabaseHandler(createMockArgsDatabaseHandler())
+		require.Nil(t, err)
+
+		class := createMockClass("prof")
+		class.Elevi = append(class.Elevi, class.Elevi[0])
+		class.AllOrNothing = true
+		result, err := db.CreateClass(class)
+		require.Nil(t, err)
+		assert.True(t, result.RolledBack)
+		assert.Equal(t, 0, len(result.Created))
+		assert.Equal(t, 1, len(result.Rejected))
+
+		students, err := db.GetStudentsByClass(class.Nume, "")
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(students))
+	})
+	t.Run("duplicate class should error", func(t *testing.T) {
+		t.Parallel()
+
+		db, err := NewDatabaseHandler(createMockArgsDatabaseHandler())
+		require.Nil(t, err)
+
+		class := createMockClass("prof")
+		class.Elevi = nil
+		_, err = db.CreateClass(class)
+		require.Nil(t, err)
+		_, err = db.CreateClass(class)
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,96 @@
+package core
+
+import "errors"
+
+// ErrUnknownDatabaseDriver signals that an unsupported database driver has been provided
+var ErrUnknownDatabaseDriver = errors.New("unknown database driver")
+
+// ErrEmptyConnectionString signals that an empty connection string has been provided
+var ErrEmptyConnectionString = errors.New("empty connection string")
+
+// ErrDatabaseUnreachable signals that the database could not be reached
+var ErrDatabaseUnreachable = errors.New("database unreachable")
+
+// errClassImportRolledBack is used to abort the class import transaction when all-or-nothing is requested
+var errClassImportRolledBack = errors.New("class import rolled back")
+
+// errCalificativeRolledBack is used to abort the batch grading transaction when all-or-nothing is requested
+var errCalificativeRolledBack = errors.New("calificative rolled back")
+
+// ErrInvalidPunctaj signals that the points of an exercise are not valid
+var ErrInvalidPunctaj = errors.New("punctaj invalid")
+
+// ErrUnknownVarianta signals that a variant is not one of the variants of the exercise
+var ErrUnknownVarianta = errors.New("varianta invalida")
+
+// ErrInvalidGradeRule signals that the rule converting points into grades is not valid
+var ErrInvalidGradeRule = errors.New("invalid grade rule")
+
+// ErrNotAnEvaluator signals that the profesor has no grading slot for the paper
+var ErrNotAnEvaluator = errors.New("profesor invalid")
+
+// ErrInvalidSlot signals that an evaluator was assigned to an unknown grading slot
+var ErrInvalidSlot = errors.New("invalid grading slot")
+
+// ErrArbitrajNotFound signals that no arbitration is open for the paper
+var ErrArbitrajNotFound = errors.New("arbitraj not found")
+
+// ErrContestatieNotFound signals that no appeal matches the request
+var ErrContestatieNotFound = errors.New("contestatie not found")
+
+// ErrContestatieStatus signals that the appeal is not in the state required by the operation
+var ErrContestatieStatus = errors.New("invalid contestatie status")
+
+// ErrLucrareContestata signals that the marks of a contested paper can only be changed by its re-evaluation
+var ErrLucrareContestata = errors.New("lucrarea este in contestatie")
+
+// ErrInvalidExamStatus signals that an unknown exam state was provided
+var ErrInvalidExamStatus = errors.New("invalid exam status")
+
+// ErrInvalidExamTransition signals that the exam cannot move from its current state to the requested one
+var ErrInvalidExamTransition = errors.New("invalid exam transition")
+
+// ErrInvalidGradingWindow signals that the grading window ends before it starts
+var ErrInvalidGradingWindow = errors.New("invalid grading window")
+
+// ErrExamNotDraft signals that the definition of an exam cannot be changed once the exam left the draft state
+var ErrExamNotDraft = errors.New("examenul nu mai este ciorna")
+
+// ErrGradingClosed signals that the marks of the exam cannot be changed at this time
+var ErrGradingClosed = errors.New("notarea este inchisa")
+
+// ErrExamExists signals that an exam with the requested name already exists
+var ErrExamExists = errors.New("exam already exists")
+
+// ErrInvalidCloneRequest signals that the exercises to rename or drop do not match the copied exam
+var ErrInvalidCloneRequest = errors.New("invalid clone request")
+
+// ErrStudentAbsent signals that the student was absent from the exam
+var ErrStudentAbsent = errors.New("elevul a absentat")
+
+// ErrStudentNotInExam signals that the student is not assigned to the exam
+var ErrStudentNotInExam = errors.New("student not assigned to exam")
+
+// ErrComentariiInchise signals that the comments of an exam cannot be changed once its results were released
+var ErrComentariiInchise = errors.New("comentariile nu mai pot fi modificate")
+
+// ErrInvalidRefreshToken signals that the refresh token is unknown, expired or belongs to a revoked session
+var ErrInvalidRefreshToken = errors.New("invalid refresh token")
+
+// ErrInvalidRol signals that the role is unknown or is assigned without the class it requires
+var ErrInvalidRol = errors.New("rol invalid")
+
+// ErrRolNotFound signals that the user does not hold the role
+var ErrRolNotFound = errors.New("rol not found")
+
+// ErrLastAdmin signals that the role cannot be revoked because no admin would be left
+var ErrLastAdmin = errors.New("cannot revoke the last admin")
+
+// ErrAdminExists signals that the first admin was already registered
+var ErrAdminExists = errors.New("an admin already exists")
+
+// ErrInvalidInvitatie signals that the invitation is unknown, expired, already used or meant for another email
+var ErrInvalidInvitatie = errors.New("invalid invitation")
+
+// ErrRezultateNepublicate signals that the results of the exam were not released yet
+var ErrRezultateNepublicate = errors.New("rezultatele nu au fost publicate")
@@ -0,0 +1,193 @@
+package core
+
+import (
+	"errors"
+	"time"
+
+	"github.com/dragos-rebegea/evaluare-tool/authentication"
+)
+
+type exercitiuKey struct {
+	exam  string
+	numar string
+}
+
+// gradingChecker validates the marks written by one profesor. The students, exercises, exams and grading slots
+// it looks up are kept, so that checking many marks of the same papers does not repeat the queries
+type gradingChecker struct {
+	db        *databaseHandler
+	prof      *authentication.Profesor
+	now       time.Time
+	students  map[uint]*authentication.Student
+	exercitii map[exercitiuKey]*authentication.Exercitiu
+	variante  map[exercitiuKey][]string
+	exams     map[string]*authentication.Exam
+	slots     map[paperKey]uint8
+	absente   map[absentaKey]*authentication.Absenta
+}
+
+func (db *databaseHandler) newGradingChecker(profEmail string) (*gradingChecker, error) {
+	prof, err := db.GetProfesorByEmail(profEmail)
+	if err != nil {
+		return nil, err
+	}
+	if prof == nil {
+		return nil, errors.New("profesor not found")
+	}
+
+	return &gradingChecker{
+		db:        db,
+		prof:      prof,
+		now:       time.Now(),
+		students:  make(map[uint]*authentication.Student),
+		exercitii: make(map[exercitiuKey]*authentication.Exercitiu),
+		variante:  make(map[exercitiuKey][]string),
+		exams:     make(map[string]*authentication.Exam),
+		slots:     make(map[paperKey]uint8),
+		absente:   make(map[absentaKey]*authentication.Absenta),
+	}, nil
+}
+
+// check validates a mark and fills in the profesor and the grading slot it is written to. It returns the
+// graded exercise
+func (checker *gradingChecker) check(calificativ *Calificativ) (*authentication.Exercitiu, error) {
+	student, err := checker.student(calificativ.Student)
+	if err != nil {
+		return nil, err
+	}
+	exercitiu, err := checker.exercitiu(calificativ.Exam, calificativ.Exercitiu)
+	if err != nil {
+		return nil, err
+	}
+	if checker.absente[absentaKey{student: student.ID, exam: exercitiu.Exam}] != nil {
+		return nil, ErrStudentAbsent
+	}
+
+	slot, err := checker.slot(student, exercitiu)
+	if err != nil {
+		return nil, err
+	}
+	exam, err := checker.exam(exercitiu.Exam)
+	if err != nil {
+		return nil, err
+	}
+	err = checkGradingWindow(exam, slot, checker.now)
+	if err != nil {
+		return nil, err
+	}
+	calificativ.Profesor = checker.prof.ID
+	calificativ.Slot = slot
+
+	variante, err := checker.varianteOf(exercitiu)
+	if err != nil {
+		return nil, err
+	}
+	if !contains(variante, calificativ.Varianta) {
+		return nil, ErrUnknownVarianta
+	}
+
+	return exercitiu, nil
+}
+
+func (checker *gradingChecker) student(id uint) (*authentication.Student, error) {
+	student, ok := checker.students[id]
+	if ok {
+		return student, nil
+	}
+
+	student, _ = checker.db.GetStudentByID(id)
+	if student == nil {
+		return nil, errors.New("student not found")
+	}
+	absente, err := loadAbsente(checker.db.database, []uint{id})
+	if err != nil {
+		return nil, err
+	}
+	for key, absenta := range absente {
+		checker.absente[key] = absenta
+	}
+	checker.students[id] = student
+	return student, nil
+}
+
+func (checker *gradingChecker) exercitiu(exam string, numar string) (*authentication.Exercitiu, error) {
+	key := exercitiuKey{exam: exam, numar: numar}
+	exercitiu, ok := checker.exercitii[key]
+	if ok {
+		return exercitiu, nil
+	}
+
+	var err error
+	if getTypeByMaterie(checker.prof.Materie) == "stiinta" {
+		exercitiu, err = checker.db.GetExercitiuStiintaByExamAndNumber(exam, numar)
+	} else {
+		exercitiu, err = checker.db.GetExercitiuLimbaByExamAndNumber(exam, numar)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if exercitiu == nil {
+		return nil, errors.New("exercitiu not found")
+	}
+	checker.exercitii[key] = exercitiu
+	return exercitiu, nil
+}
+
+// slot returns the grading slot of the profesor for the paper. Marks of a contested paper can only be written
+// by its re-evaluation
+func (checker *gradingChecker) slot(student *authentication.Student, exercitiu *authentication.Exercitiu) (uint8, error) {
+	key := paperKey{student: student.ID, exam: exercitiu.Exam, materie: exercitiu.Materie}
+	slot, ok := checker.slots[key]
+	if ok {
+		return slot, nil
+	}
+
+	slot, err := checker.db.gradingSlot(checker.prof, student, exercitiu.Exam, exercitiu.Materie)
+	if err != nil {
+		return 0, err
+	}
+	if slot != SlotContestatie {
+		contestata, errContestatie := checker.db.isContestata(student.ID, exercitiu.Exam, exercitiu.Materie)
+		if errContestatie != nil {
+			return 0, errContestatie
+		}
+		if contestata {
+			return 0, ErrLucrareContestata
+		}
+	}
+	checker.slots[key] = slot
+	return slot, nil
+}
+
+func (checker *gradingChecker) exam(nume string) (*authentication.Exam, error) {
+	exam, ok := checker.exams[nume]
+	if ok {
+		return exam, nil
+	}
+
+	exam, err := checker.db.GetExamByName(nume)
+	if err != nil {
+		return nil, err
+	}
+	checker.exams[nume] = exam
+	return exam, nil
+}
+
+func (checker *gradingChecker) varianteOf(exercitiu *authentication.Exercitiu) ([]string, error) {
+	key := exercitiuKey{exam: exercitiu.Exam, numar: exercitiu.Numar}
+	nume, ok := checker.variante[key]
+	if ok {
+		return nume, nil
+	}
+
+	variante, err := loadVariante(checker.db.database, exercitiu.Exam, exercitiu.Numar)
+	if err != nil {
+		return nil, err
+	}
+	nume = make([]string, 0, len(variante))
+	for _, varianta := range variante {
+		nume = append(nume, varianta.Nume)
+	}
+	checker.variante[key] = nume
+	return nume, nil
+}
@@ -1 +1,86 @@
 package core
+
+import (
+	"time"
+
+	"github.com/dragos-rebegea/evaluare-tool/authentication"
+	"github.com/dragos-rebegea/evaluare-tool/migrations"
+)
+
+// DatabaseHandler defines the storage operations used by the API groups
+type DatabaseHandler interface {
+	GetStudentByID(id uint) (*authentication.Student, error)
+	GetExamByName(name string) (*authentication.Exam, error)
+	GetExercitiuStiintaByExamAndNumber(exam string, number string) (*authentication.Exercitiu, error)
+	GetExercitiuLimbaByExamAndNumber(exam string, number string) (*authentication.Exercitiu, error)
+	GetProfesorByEmail(email string) (*authentication.Profesor, error)
+	GetStudentsByClass(clasa string, exam string) ([]*StudentStatus, error)
+	GetAllClasses(profEmail string) ([]string, error)
+	GetClassByID(studentId uint) (*authentication.Clasa, error)
+	SetAbsent(email string, status *AbsentStatus) error
+	CreateProfesor(profesor *authentication.Profesor, roluri ...string) error
+	CreateClass(class *Class) (*CreateClassResult, error)
+	CreateStudent(student *authentication.Student) error
+	DeleteStudent(id *uint) error
+	CreateExam(exam *Exam) error
+	UpdateRubrica(update *RubricaUpdate) error
+	CloneExam(request *CloneExamRequest) (*Exam, error)
+	MoveExamStudents(request *MoveStudentsRequest) (*MoveStudentsResult, error)
+	SetExamStatus(email string, request *ExamStatusRequest) (*authentication.TranzitieExam, error)
+	GetExamTranzitii(exam string) ([]authentication.TranzitieExam, error)
+	AddCalificativ(profEmail string, calificativ *Calificativ) error
+	UpdateCalificativ(profEmail string, calificativ *Calificativ) error
+	AddCalificative(profEmail string, batch *CalificativBatch) (*CalificativBatchResult, error)
+	GetCalificativByStudentAndExercitiu(id uint, exercitiu uint) (*Calificativ, error)
+	GetCalificative(email string, student string) ([]*Calificativ, error)
+	SetComentariu(email string, request *ComentariuRequest) (*Comentariu, error)
+	GetExercitiiForProfesorAndStudent(email string, studentId string) ([]*Exercitiu, error)
+	GetStudentScore(email string, studentId string) (*StudentScore, error)
+	GetStudentGrades(email string, studentId string) (*StudentGrades, error)
+	AssignEvaluator(assignment *EvaluatorAssignment) error
+	AssignArbiter(assignment *ArbiterAssignment) error
+	GetArbitraje() ([]authentication.Arbitraj, error)
+	FileContestatie(email string, request *ContestatieRequest) (*authentication.Contestatie, error)
+	AssignContestatie(assignment *ContestatieAssignment) error
+	ResolveContestatie(request *ContestatieRequest) (*authentication.Contestatie, error)
+	GetContestatii(status string) ([]authentication.Contestatie, error)
+	GetClassProgress(clasa string, exam string) (*GradingProgress, error)
+	GetProfesorProgress(email string) ([]*ProfesorProgress, error)
+	GetClassStatistics(email string, clasa string, exam string) (*ExamStatistics, error)
+	GetExamStatistics(exam string) (*ExamStatistics, error)
+	GetItemAnalysis(exam string) (*ItemAnalysis, error)
+	SetCompetente(competente []*Competenta) error
+	GetCompetente(materie string) ([]*Competenta, error)
+	GetCompetencyReport(email string, clasa string, exam string) (*CompetencyReport, error)
+	GetStudentEvolution(email string, studentId string) (*StudentEvolution, error)
+	GetClassEvolution(email string, clasa string) (*ClassEvolution, error)
+	CreateSesiune(sesiune *authentication.Sesiune) error
+	RefreshSesiune(refreshHash string, newHash string, expiresAt time.Time) (*authentication.Sesiune, error)
+	RevokeSesiune(refreshHash string) error
+	RevokeSesiuniProfesor(email string) (int64, error)
+	IsSesiuneActiva(id string) (bool, error)
+	GetRoluri(email string) ([]authentication.RolUtilizator, error)
+	AssignRol(request *RolRequest) error
+	RevokeRol(request *RolRequest) error
+	HasAdmin() (bool, error)
+	CreateFirstAdmin(profesor *authentication.Profesor) error
+	CreateInvitatie(invitatie *authentication.Invitatie) error
+	RedeemInvitatie(id string, profesor *authentication.Profesor) error
+	GetStudentByUsername(username string) (*authentication.Student, error)
+	GetStudentExame(studentId uint) ([]*StudentExam, error)
+	GetStudentRezultate(studentId uint, exam string) (*StudentRezultate, error)
+	FileStudentContestatie(studentId uint, request *StudentContestatieRequest) (*authentication.Contestatie, error)
+	IsInterfaceNil() bool
+}
+
+// SchemaMigrator defines the operations of the versioned schema migrations subsystem
+type SchemaMigrator interface {
+	CurrentVersion() (uint32, error)
+	LatestVersion() uint32
+	Status() ([]migrations.MigrationStatus, error)
+	Up() error
+	Down() error
+	To(version uint32) error
+	CheckSchema() error
+	IsInterfaceNil() bool
+}
@@ -0,0 +1,243 @@
+package core
+
+import (
+	"github.com/dragos-rebegea/evaluare-tool/authentication"
+	"github.com/dragos-rebegea/evaluare-tool/scoring"
+	"gorm.io/gorm"
+)
+
+// scoreVisibility decides which totals of the students a profesor may see, for every read of the stored scores.
+// The holders of grading:manage or class:read:all see every total. The other profesori see the total of a
+// subject once the paper is final, and before that only a corrector sees it, as the total of their own slot,
+// so the grading stays blind. Reports aggregating many papers count only the final ones
+type scoreVisibility struct {
+	db       *databaseHandler
+	prof     *authentication.Profesor
+	all      bool
+	students map[uint]*authentication.Student
+	exams    map[string]*authentication.Exam
+}
+
+func (db *databaseHandler) newScoreVisibility(email string) (*scoreVisibility, error) {
+	prof, err := db.GetProfesorByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	roluri, err := db.GetRoluri(email)
+	if err != nil {
+		return nil, err
+	}
+
+	names := authentication.RoleNames(roluri)
+	return &scoreVisibility{
+		db:   db,
+		prof: prof,
+		all: authentication.HasPermission(names, authentication.PermGradingManage) ||
+			authentication.HasPermission(names, authentication.PermClassReadAll),
+		students: make(map[uint]*authentication.Student),
+		exams:    make(map[string]*authentication.Exam),
+	}, nil
+}
+
+// filter returns the totals the profesor may see, and the exams with a subject that is not final or hidden
+func (sv *scoreVisibility) filter(scores []authentication.Scor) ([]authentication.Scor, map[string]bool, error) {
+	partial := make(map[string]bool)
+	if sv.all {
+		return scores, partial, nil
+	}
+
+	shown := make([]authentication.Scor, 0, len(scores))
+	for _, scor := range scores {
+		visible, final, err := sv.visible(scor)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !final {
+			partial[scor.Exam] = true
+		}
+		if visible != nil {
+			shown = append(shown, *visible)
+		}
+	}
+	return shown, partial, nil
+}
+
+// paper returns the totals of one student on one exam as the profesor may see them, or nil if the total of one
+// of the subjects is hidden, together with whether every total is final
+func (sv *scoreVisibility) paper(scores []authentication.Scor) ([]authentication.Scor, bool, error) {
+	visible, partial, err := sv.filter(scores)
+	if err != nil || len(visible) < len(scores) {
+		return nil, false, err
+	}
+	return visible, len(partial) == 0, nil
+}
+
+// finalPaper returns true if the totals of one student on one exam can be counted in a report aggregating many
+// papers: the profesor sees every total, or every subject is final
+func (sv *scoreVisibility) finalPaper(scores []authentication.Scor) (bool, error) {
+	if sv.all {
+		return true, nil
+	}
+	for _, scor := range scores {
+		student, exam, err := sv.load(scor)
+		if err != nil {
+			return false, err
+		}
+		final, err := paperFinal(sv.db.database, student, exam, scor.Materie)
+		if err != nil || !final {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// finalStudents keeps the students whose papers on an exam can be counted in a report. Students without any
+// total have no marks to leak and are kept
+func (sv *scoreVisibility) finalStudents(exam string, students []uint) ([]uint, error) {
+	if sv.all {
+		return students, nil
+	}
+
+	var scores []authentication.Scor
+	record := sv.db.database.Where("exam = ? AND student IN ?", exam, students).Find(&scores)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+	byStudent := make(map[uint][]authentication.Scor)
+	for _, scor := range scores {
+		byStudent[scor.Student] = append(byStudent[scor.Student], scor)
+	}
+
+	result := make([]uint, 0, len(students))
+	for _, student := range students {
+		final, err := sv.finalPaper(byStudent[student])
+		if err != nil {
+			return nil, err
+		}
+		if final {
+			result = append(result, student)
+		}
+	}
+	return result, nil
+}
+
+// comentarii keeps the comments the profesor may read on an exam: their own until the results are released
+func (sv *scoreVisibility) comentarii(exam *authentication.Exam, comentarii []*Comentariu) []*Comentariu {
+	if sv.all || exam.Status == ExamRezultatePublicate {
+		return comentarii
+	}
+	proprii := make([]*Comentariu, 0, len(comentarii))
+	for _, comentariu := range comentarii {
+		if comentariu.Autor == sv.prof.Email {
+			proprii = append(proprii, comentariu)
+		}
+	}
+	return proprii
+}
+
+// visible returns the total the profesor may see for one subject, or nil if it is hidden, and whether it is final
+func (sv *scoreVisibility) visible(scor authentication.Scor) (*authentication.Scor, bool, error) {
+	student, exam, err := sv.load(scor)
+	if err != nil {
+		return nil, false, err
+	}
+	final, err := paperFinal(sv.db.database, student, exam, scor.Materie)
+	if err != nil || final {
+		return &scor, final, err
+	}
+
+	slot, err := sv.db.gradingSlot(sv.prof, student, scor.Exam, scor.Materie)
+	if err == ErrNotAnEvaluator {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	schemes, err := loadExerciseSchemes(sv.db.database, scor.Exam, scor.Materie)
+	if err != nil {
+		return nil, false, err
+	}
+	totals, err := loadSlotTotals(sv.db.database, schemes, student.ID, scor.Exam, slot)
+	if err != nil {
+		return nil, false, err
+	}
+	scor.Punctaj = 0
+	total, found := totals[scor.Materie]
+	if found {
+		scor.Punctaj = total.Punctaj
+	}
+	return &scor, false, nil
+}
+
+// load returns the student and the exam of a total, reading each of them once
+func (sv *scoreVisibility) load(scor authentication.Scor) (*authentication.Student, *authentication.Exam, error) {
+	student, found := sv.students[scor.Student]
+	if !found {
+		student = &authentication.Student{}
+		record := sv.db.database.Where("id = ?", scor.Student).First(student)
+		if record.Error != nil {
+			return nil, nil, record.Error
+		}
+		sv.students[scor.Student] = student
+	}
+	exam, found := sv.exams[scor.Exam]
+	if !found {
+		exam = &authentication.Exam{}
+		record := sv.db.database.Where("nume = ?", scor.Exam).First(exam)
+		if record.Error != nil {
+			return nil, nil, record.Error
+		}
+		sv.exams[scor.Exam] = exam
+	}
+	return student, exam, nil
+}
+
+// paperFinal returns true once grading can no longer change the total of a student on a subject: the results
+// were released, the appeal or the arbitration was resolved, or every assigned corrector finished and they agree
+func paperFinal(tx *gorm.DB, student *authentication.Student, exam *authentication.Exam, materie string) (bool, error) {
+	if exam.Status == ExamRezultatePublicate {
+		return true, nil
+	}
+	contestatie, err := findContestatie(tx, student.ID, exam.Nume, materie)
+	if err != nil {
+		return false, err
+	}
+	if contestatie != nil && contestatie.Status == ContestatieRezolvata {
+		return true, nil
+	}
+
+	var arbitraj authentication.Arbitraj
+	record := tx.Where("student = ? AND exam = ? AND materie = ?", student.ID, exam.Nume, materie).Limit(1).Find(&arbitraj)
+	if record.Error != nil {
+		return false, record.Error
+	}
+	if record.RowsAffected > 0 {
+		return arbitraj.Status == ArbitrajRezolvat, nil
+	}
+
+	schemes, err := loadExerciseSchemes(tx, exam.Nume, materie)
+	if err != nil {
+		return false, err
+	}
+	corector1, err := loadSlotTotals(tx, schemes, student.ID, exam.Nume, SlotCorector1)
+	if err != nil || !scoring.IsComplete(corector1[materie]) {
+		return false, err
+	}
+
+	var corectori2 int64
+	record = tx.
+		Model(&authentication.Evaluator{}).
+		Where("clasa = ? AND exam = ? AND materie = ? AND slot = ?", student.Clasa, exam.Nume, materie, SlotCorector2).
+		Count(&corectori2)
+	if record.Error != nil {
+		return false, record.Error
+	}
+	if corectori2 == 0 {
+		return true, nil
+	}
+	corector2, err := loadSlotTotals(tx, schemes, student.ID, exam.Nume, SlotCorector2)
+	if err != nil {
+		return false, err
+	}
+	return scoring.IsComplete(corector2[materie]), nil
+}
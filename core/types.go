@@ -1,42 +1,518 @@
 package core
 
+import (
+	"time"
+
+	"github.com/dragos-rebegea/evaluare-tool/authentication"
+	"github.com/dragos-rebegea/evaluare-tool/scoring"
+	"github.com/dragos-rebegea/evaluare-tool/statistics"
+)
+
 type Class struct {
-	Nume  string `json:"nume"`
-	Elevi []struct {
-		Nume        string `json:"nume"`
-		Prenume     string `json:"prenume"`
-		Email       string `json:"email"`
-		ExamStiinta string `json:"exam_stiinta"`
-		ExamLimba   string `json:"exam_limba"`
-	} `json:"elevi"`
-	ProfMate    string `json:"prof_mate"`
-	ProfFizica  string `json:"prof_fizica"`
-	ProfBio     string `json:"prof_bio"`
-	ProfRomana  string `json:"prof_romana"`
-	ProfEngleza string `json:"prof_engleza"`
+	Nume         string         `json:"nume"`
+	Elevi        []ClassStudent `json:"elevi"`
+	ProfMate     string         `json:"prof_mate"`
+	ProfFizica   string         `json:"prof_fizica"`
+	ProfBio      string         `json:"prof_bio"`
+	ProfRomana   string         `json:"prof_romana"`
+	ProfEngleza  string         `json:"prof_engleza"`
+	AllOrNothing bool           `json:"all_or_nothing"`
+}
+
+type ClassStudent struct {
+	Nume        string `json:"nume"`
+	Prenume     string `json:"prenume"`
+	Email       string `json:"email"`
+	ExamStiinta string `json:"exam_stiinta"`
+	ExamLimba   string `json:"exam_limba"`
+}
+
+// CreateClassResult holds the outcome of a class import. When RolledBack is set nothing was stored
+type CreateClassResult struct {
+	Clasa      string             `json:"clasa"`
+	Created    []*CreatedStudent  `json:"created"`
+	Rejected   []*RejectedStudent `json:"rejected"`
+	RolledBack bool               `json:"rolled_back"`
+}
+
+type CreatedStudent struct {
+	ID       uint   `json:"userId"`
+	Email    string `json:"email"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type RejectedStudent struct {
+	Nume    string `json:"nume"`
+	Prenume string `json:"prenume"`
+	Email   string `json:"email"`
+	Reason  string `json:"reason"`
 }
 
 type AbsentStatus struct {
-	Id     uint `json:"id"`
-	Absent bool `json:"absent"`
+	Id     uint   `json:"id"`
+	Exam   string `json:"exam"`
+	Absent bool   `json:"absent"`
+	Motiv  string `json:"motiv"`
+}
+
+// StudentStatus is a student of a class along with their absence from the requested exam
+type StudentStatus struct {
+	authentication.Student
+	Absent bool   `json:"absent"`
+	Motiv  string `json:"motiv,omitempty"`
 }
 
 type Exam struct {
-	Nume      string      `json:"nume"`
-	Exercitii []Exercitiu `json:"exercitii"`
+	Nume            string      `json:"nume"`
+	Exercitii       []Exercitiu `json:"exercitii"`
+	PuncteOficiu    float64     `json:"puncte_oficiu"`
+	Rotunjire       string      `json:"rotunjire"`
+	PragArbitraj    *float64    `json:"prag_arbitraj,omitempty"`
+	PragContestatie *float64    `json:"prag_contestatie,omitempty"`
+	Sectiuni        []Sectiune  `json:"sectiuni,omitempty"`
+	InceputNotare   *time.Time  `json:"inceput_notare,omitempty"`
+	SfarsitNotare   *time.Time  `json:"sfarsit_notare,omitempty"`
+}
+
+// Sectiune is a node of the tree of an exam. Exercises are the leaves of the tree and reference their section
+// by code
+type Sectiune struct {
+	Cod     string `json:"cod"`
+	Titlu   string `json:"titlu"`
+	Parinte string `json:"parinte,omitempty"`
 }
 
 type Exercitiu struct {
-	Numar    string   `json:"numar"`
-	Variante []string `json:"variante"`
-	Materie  string   `json:"materie"`
-	Exam     string   `json:"exam"`
+	Numar        string              `json:"numar"`
+	Variante     []string            `json:"variante"`
+	Punctaje     map[string]float64  `json:"punctaje,omitempty"`
+	Rubrici      map[string]*Rubrica `json:"rubrici,omitempty"`
+	PunctajMaxim *float64            `json:"punctaj_maxim,omitempty"`
+	Pondere      float64             `json:"pondere,omitempty"`
+	Sectiune     string              `json:"sectiune,omitempty"`
+	Competente   []string            `json:"competente,omitempty"`
+	Materie      string              `json:"materie"`
+	Exam         string              `json:"exam"`
 }
 
 type Calificativ struct {
+	Student    uint        `json:"student_id"`
+	Profesor   uint        `json:"profesor_id"`
+	Exam       string      `json:"exam"`
+	Exercitiu  string      `json:"exercitiu"`
+	Slot       uint8       `json:"slot"`
+	Varianta   string      `json:"varianta"`
+	Comentariu *Comentariu `gorm:"-" json:"comentariu,omitempty"`
+}
+
+// ComentariuRequest sets the comment of a profesor on one exercise of a paper, or on the whole paper when
+// Exercitiu is empty. An empty text removes the comment
+type ComentariuRequest struct {
 	Student   uint   `json:"student_id"`
-	Profesor  uint   `json:"profesor_id"`
+	Exam      string `json:"exam"`
+	Exercitiu string `json:"exercitiu"`
+	Text      string `json:"text"`
+}
+
+// Comentariu is a comment on a paper, as shown in reports, along with its author
+type Comentariu struct {
+	Exam      string    `json:"exam"`
+	Exercitiu string    `json:"exercitiu,omitempty"`
+	Autor     string    `json:"autor,omitempty"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CalificativBatch holds many marks of a profesor, written together. In upsert mode a mark already recorded in
+// the same slot is replaced instead of rejected. With AllOrNothing a single rejected entry rolls back the batch
+type CalificativBatch struct {
+	Calificative []*Calificativ `json:"calificative"`
+	Upsert       bool           `json:"upsert"`
+	AllOrNothing bool           `json:"all_or_nothing"`
+}
+
+// CalificativBatchResult holds the outcome of every entry of a batch, in the order of the request. When
+// RolledBack is set nothing was stored
+type CalificativBatchResult struct {
+	Salvate    int                  `json:"salvate"`
+	Respinse   int                  `json:"respinse"`
+	Rezultate  []*CalificativResult `json:"rezultate"`
+	RolledBack bool                 `json:"rolled_back"`
+}
+
+// CalificativResult is the outcome of one entry of a batch
+type CalificativResult struct {
+	Student   uint   `json:"student_id"`
+	Exam      string `json:"exam"`
+	Exercitiu string `json:"exercitiu"`
+	Varianta  string `json:"varianta"`
+	Slot      uint8  `json:"slot,omitempty"`
+	Salvat    bool   `json:"salvat"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// StudentScore holds the stored totals of a student, per exam and subject
+type StudentScore struct {
+	Student    uint            `json:"student_id"`
+	Materii    []*SubjectScore `json:"materii"`
+	Sectiuni   []*ExamSections `json:"sectiuni,omitempty"`
+	Comentarii []*Comentariu   `json:"comentarii,omitempty"`
+}
+
+// ExamSections holds the points of a student rolled up into the sections of an exam
+type ExamSections struct {
+	Exam     string                  `json:"exam"`
+	Sectiuni []*scoring.SectionTotal `json:"sectiuni"`
+}
+
+type SubjectScore struct {
+	Exam         string  `json:"exam"`
+	Materie      string  `json:"materie"`
+	Punctaj      float64 `json:"punctaj"`
+	PunctajMaxim float64 `json:"punctaj_maxim"`
+}
+
+// StudentGrades holds the grades of a student on the 1-10 scale and their average
+type StudentGrades struct {
+	Student uint         `json:"student_id"`
+	Note    []*ExamGrade `json:"note"`
+	Media   *float64     `json:"media,omitempty"`
+}
+
+type ExamGrade struct {
+	Exam         string        `json:"exam"`
+	Punctaj      float64       `json:"punctaj"`
+	PunctajMaxim float64       `json:"punctaj_maxim"`
+	PuncteOficiu float64       `json:"puncte_oficiu"`
+	Nota         float64       `json:"nota"`
+	Comentarii   []*Comentariu `json:"comentarii,omitempty"`
+}
+
+// EvaluatorAssignment assigns the profesor with the given email to a grading slot of a class
+type EvaluatorAssignment struct {
+	Clasa    string `json:"clasa"`
+	Exam     string `json:"exam"`
+	Materie  string `json:"materie"`
+	Slot     uint8  `json:"slot"`
+	Profesor string `json:"profesor"`
+}
+
+// ArbiterAssignment assigns the profesor with the given email as arbiter of a paper
+type ArbiterAssignment struct {
+	Student  uint   `json:"student_id"`
+	Exam     string `json:"exam"`
+	Materie  string `json:"materie"`
+	Profesor string `json:"profesor"`
+}
+
+// ContestatieRequest identifies the contested subject of an exam of a student
+type ContestatieRequest struct {
+	Student uint   `json:"student_id"`
+	Exam    string `json:"exam"`
+	Materie string `json:"materie"`
+	Motiv   string `json:"motiv"`
+}
+
+// ContestatieAssignment assigns the profesor with the given email to re-evaluate a contested paper
+type ContestatieAssignment struct {
+	Student  uint   `json:"student_id"`
+	Exam     string `json:"exam"`
+	Materie  string `json:"materie"`
+	Profesor string `json:"profesor"`
+}
+
+// CloneExamRequest copies an exam under a new name. Exercises can be renamed, from the old number to the new
+// one, or dropped from the copy
+type CloneExamRequest struct {
+	Exam       string            `json:"exam"`
+	Nume       string            `json:"nume"`
+	Redenumiri map[string]string `json:"redenumiri,omitempty"`
+	Excluse    []string          `json:"excluse,omitempty"`
+}
+
+// MoveStudentsRequest moves the students assigned to an exam to another one. An empty list of classes moves
+// the students of every class
+type MoveStudentsRequest struct {
+	Exam    string   `json:"exam"`
+	NouExam string   `json:"nou_exam"`
+	Clase   []string `json:"clase,omitempty"`
+}
+
+// MoveStudentsResult holds how many exam assignments were moved
+type MoveStudentsResult struct {
+	Mutati int64 `json:"mutati"`
+}
+
+// ExamStatusRequest moves an exam to another state of its lifecycle
+type ExamStatusRequest struct {
+	Exam   string `json:"exam"`
+	Status string `json:"status"`
+	Motiv  string `json:"motiv"`
+}
+
+// GradingProgress holds the grading progress of a class on an exam
+type GradingProgress struct {
+	Clasa             string               `json:"clasa"`
+	Exam              string               `json:"exam"`
+	Profesori         []*ProfesorProgress  `json:"profesori"`
+	Exercitii         []*ExercitiuProgress `json:"exercitii"`
+	LucrariIncomplete []*StudentProgress   `json:"lucrari_incomplete"`
+}
+
+// ProfesorProgress counts the papers and exercises graded by a profesor in one slot, for a class and a subject
+type ProfesorProgress struct {
+	Profesor        string `json:"profesor"`
+	Clasa           string `json:"clasa"`
+	Exam            string `json:"exam"`
+	Materie         string `json:"materie"`
+	Slot            uint8  `json:"slot"`
+	Elevi           int    `json:"elevi"`
+	EleviFinalizati int    `json:"elevi_finalizati"`
+	ExercitiiTotal  int    `json:"exercitii_total"`
+	ExercitiiNotate int    `json:"exercitii_notate"`
+}
+
+// ExercitiuProgress counts the papers on which an exercise has been graded
+type ExercitiuProgress struct {
+	Exam    string `json:"exam"`
+	Numar   string `json:"numar"`
+	Materie string `json:"materie"`
+	Total   int    `json:"total"`
+	Notate  int    `json:"notate"`
+}
+
+// StudentProgress lists the exercises a profesor still has to grade on the paper of a student
+type StudentProgress struct {
+	Student  uint     `json:"student_id"`
+	Nume     string   `json:"nume"`
+	Prenume  string   `json:"prenume"`
+	Exam     string   `json:"exam"`
+	Materie  string   `json:"materie"`
+	Slot     uint8    `json:"slot"`
+	Profesor string   `json:"profesor"`
+	Lipsa    []string `json:"exercitii_lipsa"`
+}
+
+// ExamStatistics describes the results of a class, or of the whole school if Clasa is empty, on an exam
+type ExamStatistics struct {
+	Clasa     string                 `json:"clasa,omitempty"`
+	Exam      string                 `json:"exam"`
+	Elevi     int                    `json:"elevi"`
+	Punctaj   *statistics.Summary    `json:"punctaj"`
+	Note      *statistics.Summary    `json:"note"`
+	Materii   []*MaterieStatistics   `json:"materii"`
+	Sectiuni  []*SectiuneStatistics  `json:"sectiuni,omitempty"`
+	Exercitii []*ExercitiuStatistics `json:"exercitii"`
+}
+
+// SectiuneStatistics describes the points obtained on one section of an exam
+type SectiuneStatistics struct {
+	Cod          string              `json:"cod"`
+	Titlu        string              `json:"titlu"`
+	Parinte      string              `json:"parinte,omitempty"`
+	PunctajMaxim float64             `json:"punctaj_maxim"`
+	Punctaj      *statistics.Summary `json:"punctaj"`
+}
+
+// MaterieStatistics describes the points obtained on one subject of an exam
+type MaterieStatistics struct {
+	Materie string              `json:"materie"`
+	Punctaj *statistics.Summary `json:"punctaj"`
+}
+
+// ExercitiuStatistics describes how the variants of an exercise were distributed among the graded papers
+type ExercitiuStatistics struct {
+	Numar        string                `json:"numar"`
+	Materie      string                `json:"materie"`
+	Notate       int                   `json:"notate"`
+	PunctajMediu float64               `json:"punctaj_mediu"`
+	Variante     []*VariantaStatistics `json:"variante"`
+}
+
+// VariantaStatistics counts the papers on which a variant was chosen
+type VariantaStatistics struct {
+	Nume    string  `json:"nume"`
+	Elevi   int     `json:"elevi"`
+	Procent float64 `json:"procent"`
+}
+
+// ItemAnalysis holds the difficulty and discrimination indices of every exercise of an exam
+type ItemAnalysis struct {
+	Exam      string            `json:"exam"`
+	Elevi     int               `json:"elevi"`
+	Exercitii []*ItemStatistics `json:"exercitii"`
+}
+
+// ItemStatistics describes how an exercise separated the students graded on its subject
+type ItemStatistics struct {
+	Numar         string                `json:"numar"`
+	Materie       string                `json:"materie"`
+	Sectiune      string                `json:"sectiune,omitempty"`
+	Elevi         int                   `json:"elevi"`
+	PunctajMaxim  float64               `json:"punctaj_maxim"`
+	Facilitate    float64               `json:"facilitate"`
+	Discriminare  float64               `json:"discriminare"`
+	PunctBiserial float64               `json:"punct_biserial"`
+	Variante      []*VariantaStatistics `json:"variante"`
+}
+
+// Rubrica is the marking scheme of a variant, with an optional example answer
+type Rubrica struct {
+	Descriere      string `json:"descriere"`
+	ExempluRaspuns string `json:"exemplu_raspuns,omitempty"`
+}
+
+// RubricaUpdate changes the marking scheme of one variant of an exercise
+type RubricaUpdate struct {
 	Exam      string `json:"exam"`
 	Exercitiu string `json:"exercitiu"`
 	Varianta  string `json:"varianta"`
+	Rubrica
+}
+
+// Competenta describes a specific competency of the national curriculum of a subject
+type Competenta struct {
+	Materie   string `json:"materie"`
+	Cod       string `json:"cod"`
+	Descriere string `json:"descriere"`
+}
+
+// CompetencyReport holds the mastery of the competencies assessed by an exam, for a class and for each of its
+// students. Competencies are listed from the weakest to the strongest
+type CompetencyReport struct {
+	Clasa      string                  `json:"clasa"`
+	Exam       string                  `json:"exam"`
+	Elevi      int                     `json:"elevi"`
+	Competente []*CompetentaStatistics `json:"competente"`
+	Profiluri  []*StudentCompetente    `json:"profiluri"`
+}
+
+// CompetentaStatistics summarizes the mastery, as a percentage, of one competency by the students graded on it
+type CompetentaStatistics struct {
+	Materie      string              `json:"materie"`
+	Cod          string              `json:"cod"`
+	Descriere    string              `json:"descriere,omitempty"`
+	Exercitii    []string            `json:"exercitii"`
+	PunctajMaxim float64             `json:"punctaj_maxim"`
+	Stapanire    *statistics.Summary `json:"stapanire"`
+}
+
+// StudentCompetente is the competency profile of a student on an exam, along with the grade of the exam
+type StudentCompetente struct {
+	Student    uint                       `json:"student_id"`
+	Nume       string                     `json:"nume"`
+	Prenume    string                     `json:"prenume"`
+	Nota       *ExamGrade                 `json:"nota,omitempty"`
+	Competente []*scoring.CompetencyTotal `json:"competente"`
+	Comentarii []*Comentariu              `json:"comentarii,omitempty"`
+}
+
+// StudentEvolution lists the exams a student was graded on, from the oldest to the newest
+type StudentEvolution struct {
+	Student uint             `json:"student_id"`
+	Nume    string           `json:"nume"`
+	Prenume string           `json:"prenume"`
+	Clasa   string           `json:"clasa"`
+	Exame   []*ExamEvolution `json:"exame"`
+}
+
+// ExamEvolution holds the results on one exam and how they changed since the previous exam. The delta of a
+// subject compares the percentage of the maximum obtained with the last exam that had the same subject
+type ExamEvolution struct {
+	Exam         string                  `json:"exam"`
+	Data         *time.Time              `json:"data,omitempty"`
+	Elevi        int                     `json:"elevi,omitempty"`
+	Nota         float64                 `json:"nota"`
+	DeltaNota    *float64                `json:"delta_nota,omitempty"`
+	Punctaj      float64                 `json:"punctaj"`
+	PunctajMaxim float64                 `json:"punctaj_maxim"`
+	Materii      []*MaterieEvolution     `json:"materii"`
+	Sectiuni     []*scoring.SectionTotal `json:"sectiuni,omitempty"`
+}
+
+// MaterieEvolution holds the points obtained on one subject of an exam, as a percentage of the maximum too
+type MaterieEvolution struct {
+	Materie      string   `json:"materie"`
+	Punctaj      float64  `json:"punctaj"`
+	PunctajMaxim float64  `json:"punctaj_maxim"`
+	Procent      float64  `json:"procent"`
+	Delta        *float64 `json:"delta,omitempty"`
+}
+
+// ClassEvolution lists the averages of the students of a class on every exam they were graded on, from the
+// oldest to the newest
+type ClassEvolution struct {
+	Clasa string           `json:"clasa"`
+	Exame []*ExamEvolution `json:"exame"`
+}
+
+// RevokeSessionsRequest names the profesor whose sessions are closed
+type RevokeSessionsRequest struct {
+	Email string `json:"email"`
+}
+
+// RolRequest assigns or revokes a role of a user. Clasa is required for the diriginte role and must be empty
+// for the others
+type RolRequest struct {
+	Email string `json:"email"`
+	Rol   string `json:"rol"`
+	Clasa string `json:"clasa,omitempty"`
+}
+
+// InvitatieRequest invites a user to register with a role
+type InvitatieRequest struct {
+	Email string `json:"email"`
+	Rol   string `json:"rol,omitempty"`
+}
+
+// StudentExam is an exam assigned to a student, as the student sees it. Results can be read once published
+type StudentExam struct {
+	Exam               string `json:"exam"`
+	Status             string `json:"status"`
+	Absent             bool   `json:"absent"`
+	RezultatePublicate bool   `json:"rezultate_publicate"`
+}
+
+// StudentRezultate holds the released results of a student on one exam: the grade, the totals per subject and
+// section, the points and variants of every exercise, the comments on the corrections that count and the appeals
+type StudentRezultate struct {
+	Exam        string                  `json:"exam"`
+	Nota        *ExamGrade              `json:"nota,omitempty"`
+	Materii     []*SubjectScore         `json:"materii"`
+	Sectiuni    []*scoring.SectionTotal `json:"sectiuni,omitempty"`
+	Exercitii   []*ExercitiuRezultat    `json:"exercitii"`
+	Comentarii  []*Comentariu           `json:"comentarii"`
+	Contestatii []*StudentContestatie   `json:"contestatii"`
+}
+
+// StudentContestatie is an appeal as shown to the student who filed it, without the profesori handling it
+type StudentContestatie struct {
+	Materie      string    `json:"materie"`
+	Motiv        string    `json:"motiv"`
+	Status       string    `json:"status"`
+	NotaInitiala float64   `json:"nota_initiala"`
+	NotaNoua     *float64  `json:"nota_noua"`
+	NotaFinala   *float64  `json:"nota_finala"`
+	Aplicata     bool      `json:"aplicata"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// ExercitiuRezultat holds the points of a student on one exercise and the variants they come from. Two variants
+// are listed when the points average two corrections
+type ExercitiuRezultat struct {
+	Numar        string                             `json:"numar"`
+	Materie      string                             `json:"materie"`
+	Puncte       float64                            `json:"puncte"`
+	PunctajMaxim float64                            `json:"punctaj_maxim"`
+	Variante     []authentication.VariantaExercitiu `json:"variante"`
+}
+
+// StudentContestatieRequest is an appeal filed by a student against their grade on one subject of an exam
+type StudentContestatieRequest struct {
+	Exam    string `json:"exam"`
+	Materie string `json:"materie"`
+	Motiv   string `json:"motiv"`
 }
@@ -0,0 +1,111 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/dragos-rebegea/evaluare-tool/core"
+)
+
+var competencyReportHeader = []string{
+	"materie",
+	"competenta",
+	"descriere",
+	"exercitii",
+	"punctaj_maxim",
+	"elevi",
+	"stapanire_medie",
+	"stapanire_mediana",
+	"stapanire_minima",
+	"stapanire_maxima",
+}
+
+var studentCompetenciesHeader = []string{
+	"student_id",
+	"nume",
+	"prenume",
+	"nota",
+	"materie",
+	"competenta",
+	"punctaj",
+	"punctaj_maxim",
+	"stapanire",
+}
+
+// WriteCompetencyReportCSV writes the mastery of the competencies of a class as CSV, one competency per row,
+// from the weakest to the strongest. Masteries are percentages
+func WriteCompetencyReportCSV(w io.Writer, report *core.CompetencyReport) error {
+	if report == nil {
+		return ErrNilReport
+	}
+
+	writer := csv.NewWriter(w)
+	err := writer.Write(competencyReportHeader)
+	if err != nil {
+		return err
+	}
+
+	for _, competenta := range report.Competente {
+		err = writer.Write([]string{
+			competenta.Materie,
+			competenta.Cod,
+			competenta.Descriere,
+			strings.Join(competenta.Exercitii, "; "),
+			formatFloat(competenta.PunctajMaxim),
+			strconv.Itoa(competenta.Stapanire.Count),
+			formatFloat(competenta.Stapanire.Media),
+			formatFloat(competenta.Stapanire.Mediana),
+			formatFloat(competenta.Stapanire.Minim),
+			formatFloat(competenta.Stapanire.Maxim),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteStudentCompetenciesCSV writes the competency profile of every student of a report as CSV, one row per
+// student and competency, weakest competency first. The grade of the exam is repeated on every row of the
+// student and left empty until the paper is graded
+func WriteStudentCompetenciesCSV(w io.Writer, report *core.CompetencyReport) error {
+	if report == nil {
+		return ErrNilReport
+	}
+
+	writer := csv.NewWriter(w)
+	err := writer.Write(studentCompetenciesHeader)
+	if err != nil {
+		return err
+	}
+
+	for _, profil := range report.Profiluri {
+		nota := ""
+		if profil.Nota != nil {
+			nota = formatFloat(profil.Nota.Nota)
+		}
+		for _, competenta := range profil.Competente {
+			err = writer.Write([]string{
+				strconv.FormatUint(uint64(profil.Student), 10),
+				profil.Nume,
+				profil.Prenume,
+				nota,
+				competenta.Materie,
+				competenta.Cod,
+				formatFloat(competenta.Punctaj),
+				formatFloat(competenta.PunctajMaxim),
+				formatFloat(competenta.Stapanire),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,91 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dragos-rebegea/evaluare-tool/core"
+	"github.com/dragos-rebegea/evaluare-tool/scoring"
+	"github.com/dragos-rebegea/evaluare-tool/statistics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestCompetencyReport() *core.CompetencyReport {
+	return &core.CompetencyReport{
+		Clasa: "8A",
+		Exam:  "simulare",
+		Elevi: 2,
+		Competente: []*core.CompetentaStatistics{
+			{
+				Materie:      "matematica",
+				Cod:          "2.1",
+				Descriere:    "Calcule cu numere reale",
+				Exercitii:    []string{"1", "3"},
+				PunctajMaxim: 10,
+				Stapanire:    &statistics.Summary{Count: 2, Media: 40, Mediana: 40, Minim: 30, Maxim: 50},
+			},
+		},
+		Profiluri: []*core.StudentCompetente{
+			{
+				Student: 1,
+				Nume:    "Popescu",
+				Prenume: "Ion",
+				Nota:    &core.ExamGrade{Nota: 7.5},
+				Competente: []*scoring.CompetencyTotal{
+					{Materie: "matematica", Cod: "2.1", Punctaj: 3, PunctajMaxim: 10, Stapanire: 30},
+					{Materie: "matematica", Cod: "1.1", Punctaj: 5, PunctajMaxim: 5, Stapanire: 100},
+				},
+			},
+			{
+				Student:    2,
+				Nume:       "Ionescu",
+				Prenume:    "Ana",
+				Competente: []*scoring.CompetencyTotal{{Materie: "matematica", Cod: "2.1", PunctajMaxim: 10}},
+			},
+		},
+	}
+}
+
+func TestWriteCompetencyReportCSV(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil report should error", func(t *testing.T) {
+		t.Parallel()
+
+		buff := &bytes.Buffer{}
+		assert.Equal(t, ErrNilReport, WriteCompetencyReportCSV(buff, nil))
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		buff := &bytes.Buffer{}
+		require.Nil(t, WriteCompetencyReportCSV(buff, createTestCompetencyReport()))
+		expected := "materie,competenta,descriere,exercitii,punctaj_maxim,elevi,stapanire_medie,stapanire_mediana," +
+			"stapanire_minima,stapanire_maxima\n" +
+			"matematica,2.1,Calcule cu numere reale,1; 3,10,2,40,40,30,50\n"
+		assert.Equal(t, expected, buff.String())
+	})
+}
+
+func TestWriteStudentCompetenciesCSV(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil report should error", func(t *testing.T) {
+		t.Parallel()
+
+		buff := &bytes.Buffer{}
+		assert.Equal(t, ErrNilReport, WriteStudentCompetenciesCSV(buff, nil))
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		buff := &bytes.Buffer{}
+		require.Nil(t, WriteStudentCompetenciesCSV(buff, createTestCompetencyReport()))
+		expected := "student_id,nume,prenume,nota,materie,competenta,punctaj,punctaj_maxim,stapanire\n" +
+			"1,Popescu,Ion,7.5,matematica,2.1,3,10,30\n" +
+			"1,Popescu,Ion,7.5,matematica,1.1,5,5,100\n" +
+			"2,Ionescu,Ana,,matematica,2.1,0,10,0\n"
+		assert.Equal(t, expected, buff.String())
+	})
+}
@@ -0,0 +1,6 @@
+package export
+
+import "errors"
+
+// ErrNilReport signals that a nil report was provided for export
+var ErrNilReport = errors.New("nil report")
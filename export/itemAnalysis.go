@@ -0,0 +1,66 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/dragos-rebegea/evaluare-tool/core"
+)
+
+var itemAnalysisHeader = []string{
+	"exercitiu",
+	"materie",
+	"sectiune",
+	"elevi",
+	"punctaj_maxim",
+	"facilitate",
+	"discriminare",
+	"punct_biserial",
+	"variante",
+}
+
+// WriteItemAnalysisCSV writes the item analysis of an exam as CSV, one exercise per row. The variant shares
+// are joined in a single column, as "A: 40%; B: 60%"
+func WriteItemAnalysisCSV(w io.Writer, analysis *core.ItemAnalysis) error {
+	if analysis == nil {
+		return ErrNilReport
+	}
+
+	writer := csv.NewWriter(w)
+	err := writer.Write(itemAnalysisHeader)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range analysis.Exercitii {
+		variante := make([]string, 0, len(item.Variante))
+		for _, varianta := range item.Variante {
+			variante = append(variante, fmt.Sprintf("%s: %s%%", varianta.Nume, formatFloat(varianta.Procent)))
+		}
+
+		err = writer.Write([]string{
+			item.Numar,
+			item.Materie,
+			item.Sectiune,
+			strconv.Itoa(item.Elevi),
+			formatFloat(item.PunctajMaxim),
+			formatFloat(item.Facilitate),
+			formatFloat(item.Discriminare),
+			formatFloat(item.PunctBiserial),
+			strings.Join(variante, "; "),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
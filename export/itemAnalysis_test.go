@@ -0,0 +1,51 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dragos-rebegea/evaluare-tool/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteItemAnalysisCSV(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil report should error", func(t *testing.T) {
+		t.Parallel()
+
+		buff := &bytes.Buffer{}
+		assert.Equal(t, ErrNilReport, WriteItemAnalysisCSV(buff, nil))
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		analysis := &core.ItemAnalysis{
+			Exam:  "simulare",
+			Elevi: 2,
+			Exercitii: []*core.ItemStatistics{
+				{
+					Numar:         "1",
+					Materie:       "matematica",
+					Sectiune:      "I",
+					Elevi:         2,
+					PunctajMaxim:  3,
+					Facilitate:    0.67,
+					Discriminare:  0.5,
+					PunctBiserial: -0.1,
+					Variante: []*core.VariantaStatistics{
+						{Nume: "A", Elevi: 1, Procent: 50},
+						{Nume: "B", Elevi: 1, Procent: 50},
+					},
+				},
+			},
+		}
+
+		buff := &bytes.Buffer{}
+		require.Nil(t, WriteItemAnalysisCSV(buff, analysis))
+		expected := "exercitiu,materie,sectiune,elevi,punctaj_maxim,facilitate,discriminare,punct_biserial,variante\n" +
+			"1,matematica,I,2,3,0.67,0.5,-0.1,A: 50%; B: 50%\n"
+		assert.Equal(t, expected, buff.String())
+	})
+}
@@ -0,0 +1,67 @@
+package factory
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dragos-rebegea/evaluare-tool/config"
+	"github.com/dragos-rebegea/evaluare-tool/core"
+	"github.com/dragos-rebegea/evaluare-tool/migrations"
+	"github.com/go-sql-driver/mysql"
+)
+
+// CreateDatabaseHandler creates the storage backend described by the provided config
+func CreateDatabaseHandler(cfg config.DatabaseConfig) (core.DatabaseHandler, error) {
+	dbHandler, err := core.NewDatabaseHandler(createArgsDatabaseHandler(cfg))
+	if err != nil {
+		return nil, fmt.Errorf("%w while creating the %s database handler", err, cfg.Driver)
+	}
+
+	return dbHandler, nil
+}
+
+// CreateSchemaMigrator creates the schema migrator for the database described by the provided config
+func CreateSchemaMigrator(cfg config.DatabaseConfig) (core.SchemaMigrator, error) {
+	db, err := core.OpenDatabase(createArgsDatabaseHandler(cfg))
+	if err != nil {
+		return nil, fmt.Errorf("%w while opening the %s database", err, cfg.Driver)
+	}
+
+	migrator, err := migrations.NewMigrator(db, migrations.All())
+	if err != nil {
+		return nil, err
+	}
+
+	return migrator, nil
+}
+
+func createArgsDatabaseHandler(cfg config.DatabaseConfig) core.ArgsDatabaseHandler {
+	return core.ArgsDatabaseHandler{
+		Driver:             cfg.Driver,
+		ConnectionString:   createConnectionString(cfg),
+		MaxOpenConnections: cfg.MaxOpenConnections,
+		MaxIdleConnections: cfg.MaxIdleConnections,
+		ConnMaxLifetime:    time.Duration(cfg.ConnMaxLifetimeInSec) * time.Second,
+		AutoMigrate:        cfg.AutoMigrate,
+	}
+}
+
+func createConnectionString(cfg config.DatabaseConfig) string {
+	if cfg.Driver != core.MySQLDriver {
+		return cfg.SQLiteFile
+	}
+
+	mysqlConfig := mysql.NewConfig()
+	mysqlConfig.User = cfg.User
+	mysqlConfig.Passwd = cfg.Password
+	mysqlConfig.Net = "tcp"
+	mysqlConfig.Addr = fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)
+	mysqlConfig.DBName = cfg.Name
+	mysqlConfig.TLSConfig = cfg.TLSMode
+	mysqlConfig.ParseTime = true
+	mysqlConfig.Timeout = time.Duration(cfg.ConnectTimeoutInSec) * time.Second
+	mysqlConfig.ReadTimeout = time.Duration(cfg.ReadWriteTimeoutInSec) * time.Second
+	mysqlConfig.WriteTimeout = time.Duration(cfg.ReadWriteTimeoutInSec) * time.Second
+
+	return mysqlConfig.FormatDSN()
+}
@@ -0,0 +1,76 @@
+package factory
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dragos-rebegea/evaluare-tool/config"
+	"github.com/dragos-rebegea/evaluare-tool/core"
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/stretchr/testify/assert"
+)
+
+func createMockDatabaseConfig() config.DatabaseConfig {
+	return config.DatabaseConfig{
+		Driver:                core.MySQLDriver,
+		Host:                  "127.0.0.1",
+		Port:                  1,
+		User:                  "user",
+		Password:              "secret",
+		Name:                  "id_db",
+		TLSMode:               "false",
+		SQLiteFile:            ":memory:",
+		ConnectTimeoutInSec:   1,
+		ReadWriteTimeoutInSec: 1,
+		AutoMigrate:           true,
+	}
+}
+
+func TestCreateConnectionString(t *testing.T) {
+	t.Parallel()
+
+	t.Run("mysql", func(t *testing.T) {
+		t.Parallel()
+
+		dsn := createConnectionString(createMockDatabaseConfig())
+		assert.Equal(t, "user:secret@tcp(127.0.0.1:1)/id_db?parseTime=true&readTimeout=1s&timeout=1s&tls=false&writeTimeout=1s", dsn)
+	})
+	t.Run("sqlite", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := createMockDatabaseConfig()
+		cfg.Driver = core.SQLiteDriver
+		assert.Equal(t, ":memory:", createConnectionString(cfg))
+	})
+}
+
+func TestCreateDatabaseHandler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("unreachable mysql should error", func(t *testing.T) {
+		t.Parallel()
+
+		dbHandler, err := CreateDatabaseHandler(createMockDatabaseConfig())
+		assert.True(t, errors.Is(err, core.ErrDatabaseUnreachable))
+		assert.True(t, check.IfNil(dbHandler))
+	})
+	t.Run("sqlite should work", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := createMockDatabaseConfig()
+		cfg.Driver = core.SQLiteDriver
+		dbHandler, err := CreateDatabaseHandler(cfg)
+		assert.Nil(t, err)
+		assert.False(t, check.IfNil(dbHandler))
+	})
+}
+
+func TestCreateSchemaMigrator(t *testing.T) {
+	t.Parallel()
+
+	cfg := createMockDatabaseConfig()
+	cfg.Driver = core.SQLiteDriver
+	migrator, err := CreateSchemaMigrator(cfg)
+	assert.Nil(t, err)
+	assert.False(t, check.IfNil(migrator))
+}
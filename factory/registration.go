@@ -0,0 +1,67 @@
+package factory
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dragos-rebegea/evaluare-tool/authentication"
+	"github.com/dragos-rebegea/evaluare-tool/config"
+	"github.com/dragos-rebegea/evaluare-tool/core"
+)
+
+// CreateSetupToken stores a one-time token allowing the holder to register the first admin through
+// /auth/register. Only the hash of the token is stored. It returns core.ErrAdminExists once an admin exists
+func CreateSetupToken(dbHandler core.DatabaseHandler, cfg config.RegistrationConfig) (string, time.Time, error) {
+	err := checkRegistrationConfig(cfg)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	exists, err := dbHandler.HasAdmin()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if exists {
+		return "", time.Time{}, core.ErrAdminExists
+	}
+
+	token, hash, err := authentication.NewToken()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	expiresAt := time.Now().Add(time.Duration(cfg.SetupTokenLifetimeInSec) * time.Second)
+	err = dbHandler.CreateInvitatie(&authentication.Invitatie{
+		ID:        hash,
+		Rol:       authentication.RolAdmin,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return token, expiresAt, nil
+}
+
+// printSetupTokenIfNoAdmin logs a setup token when the school has no admin yet, so the first admin can be
+// registered by whoever can read the logs of the service
+func printSetupTokenIfNoAdmin(dbHandler core.DatabaseHandler, cfg config.RegistrationConfig) error {
+	token, expiresAt, err := CreateSetupToken(dbHandler, cfg)
+	if err == core.ErrAdminExists {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	log.Warn("no admin registered, use the setup token with /auth/register",
+		"setup token", token, "expires at", expiresAt.Format(time.RFC3339))
+	return nil
+}
+
+func checkRegistrationConfig(cfg config.RegistrationConfig) error {
+	if cfg.SetupTokenLifetimeInSec <= 0 {
+		return fmt.Errorf("invalid setup token lifetime: %d", cfg.SetupTokenLifetimeInSec)
+	}
+	if cfg.InvitationLifetimeInSec <= 0 {
+		return fmt.Errorf("invalid invitation lifetime: %d", cfg.InvitationLifetimeInSec)
+	}
+	return nil
+}
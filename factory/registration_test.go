@@ -0,0 +1,57 @@
+package factory
+
+import (
+	"testing"
+
+	"github.com/dragos-rebegea/evaluare-tool/authentication"
+	"github.com/dragos-rebegea/evaluare-tool/config"
+	"github.com/dragos-rebegea/evaluare-tool/core"
+	"github.com/dragos-rebegea/evaluare-tool/testsCommon/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateSetupToken(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid lifetime should error", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, err := CreateSetupToken(&database.DatabaseHandlerStub{}, config.RegistrationConfig{InvitationLifetimeInSec: 1})
+		assert.NotNil(t, err)
+	})
+	t.Run("existing admin should error", func(t *testing.T) {
+		t.Parallel()
+
+		dbHandler := &database.DatabaseHandlerStub{
+			HasAdminCalled: func() (bool, error) {
+				return true, nil
+			},
+			CreateInvitatieCalled: func(invitatie *authentication.Invitatie) error {
+				assert.Fail(t, "should not have stored a setup token")
+				return nil
+			},
+		}
+		cfg := config.RegistrationConfig{SetupTokenLifetimeInSec: 60, InvitationLifetimeInSec: 60}
+		_, _, err := CreateSetupToken(dbHandler, cfg)
+		assert.Equal(t, core.ErrAdminExists, err)
+	})
+	t.Run("should store only the hash of the token", func(t *testing.T) {
+		t.Parallel()
+
+		var stored *authentication.Invitatie
+		dbHandler := &database.DatabaseHandlerStub{
+			CreateInvitatieCalled: func(invitatie *authentication.Invitatie) error {
+				stored = invitatie
+				return nil
+			},
+		}
+		cfg := config.RegistrationConfig{SetupTokenLifetimeInSec: 60, InvitationLifetimeInSec: 60}
+		token, expiresAt, err := CreateSetupToken(dbHandler, cfg)
+		require.Nil(t, err)
+		require.NotNil(t, stored)
+		assert.Equal(t, authentication.HashToken(token), stored.ID)
+		assert.Equal(t, authentication.RolAdmin, stored.Rol)
+		assert.Equal(t, expiresAt, stored.ExpiresAt)
+	})
+}
@@ -4,10 +4,14 @@ import (
 	"io"
 
 	"github.com/dragos-rebegea/evaluare-tool/api/gin"
+	"github.com/dragos-rebegea/evaluare-tool/authentication"
 	"github.com/dragos-rebegea/evaluare-tool/config"
 	"github.com/dragos-rebegea/evaluare-tool/facade"
+	logger "github.com/multiversx/mx-chain-logger-go"
 )
 
+var log = logger.GetOrCreate("factory")
+
 // StartWebServer creates and starts a web server able to respond with the metrics holder information
 func StartWebServer(configs config.Configs) (io.Closer, error) {
 	argsFacade := facade.ArgsEvaluationFacade{
@@ -20,10 +24,28 @@ func StartWebServer(configs config.Configs) (io.Closer, error) {
 		return nil, err
 	}
 
+	dbHandler, err := CreateDatabaseHandler(configs.GeneralConfig.Database)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenHandler, err := authentication.NewJWTHandler(configs.GeneralConfig.JWT)
+	if err != nil {
+		return nil, err
+	}
+
+	err = printSetupTokenIfNoAdmin(dbHandler, configs.GeneralConfig.Registration)
+	if err != nil {
+		return nil, err
+	}
+
 	httpServerArgs := gin.ArgsNewWebServer{
 		Facade:          authFacade,
 		ApiConfig:       configs.ApiRoutesConfig,
 		AntiFloodConfig: configs.GeneralConfig.Antiflood.WebServer,
+		DatabaseHandler: dbHandler,
+		TokenHandler:    tokenHandler,
+		Registration:    configs.GeneralConfig.Registration,
 	}
 
 	httpServerWrapper, err := gin.NewWebServerHandler(httpServerArgs)
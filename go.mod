@@ -8,6 +8,7 @@ require (
 	github.com/gin-contrib/cors v1.4.0
 	github.com/gin-contrib/pprof v1.4.0
 	github.com/gin-gonic/gin v1.9.0
+	github.com/go-sql-driver/mysql v1.7.0
 	github.com/multiversx/mx-chain-core-go v1.1.33
 	github.com/multiversx/mx-chain-go v1.4.8
 	github.com/multiversx/mx-chain-logger-go v1.0.11
@@ -15,5 +16,6 @@ require (
 	github.com/urfave/cli v1.22.13
 	golang.org/x/crypto v0.9.0
 	gorm.io/driver/mysql v1.5.0
+	gorm.io/driver/sqlite v1.5.0
 	gorm.io/gorm v1.25.1
 )
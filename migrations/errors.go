@@ -0,0 +1,21 @@
+package migrations
+
+import "errors"
+
+// ErrNilDatabase signals that a nil database connection has been provided
+var ErrNilDatabase = errors.New("nil database")
+
+// ErrInvalidMigrationsOrder signals that the migrations are not numbered consecutively starting from 1
+var ErrInvalidMigrationsOrder = errors.New("invalid migrations order")
+
+// ErrUnknownVersion signals that the requested schema version does not exist
+var ErrUnknownVersion = errors.New("unknown schema version")
+
+// ErrSchemaBehind signals that the database schema is older than the one required by the application
+var ErrSchemaBehind = errors.New("database schema is behind, run the migrate up command")
+
+// ErrSchemaAhead signals that the database schema is newer than the one known by the application
+var ErrSchemaAhead = errors.New("database schema is newer than the application")
+
+// ErrNothingToRollback signals that there is no applied migration to roll back
+var ErrNothingToRollback = errors.New("no migration to roll back")
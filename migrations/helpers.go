@@ -0,0 +1,12 @@
+package migrations
+
+import "gorm.io/gorm"
+
+// restoreIndex creates again an index of a table, which sqlite loses when it rebuilds the table to add or
+// drop a column
+func restoreIndex(tx *gorm.DB, model interface{}, index string) error {
+	if tx.Migrator().HasIndex(model, index) {
+		return nil
+	}
+	return tx.Migrator().CreateIndex(model, index)
+}
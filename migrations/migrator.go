@@ -0,0 +1,181 @@
+package migrations
+
+import (
+	"fmt"
+	"time"
+
+	logger "github.com/multiversx/mx-chain-logger-go"
+	"gorm.io/gorm"
+)
+
+var log = logger.GetOrCreate("migrations")
+
+type migrator struct {
+	database   *gorm.DB
+	migrations []Migration
+}
+
+// NewMigrator returns a new instance of migrator able to apply the provided migrations
+func NewMigrator(database *gorm.DB, migrations []Migration) (*migrator, error) {
+	if database == nil {
+		return nil, ErrNilDatabase
+	}
+	for idx, migration := range migrations {
+		if migration.Version != uint32(idx+1) {
+			return nil, fmt.Errorf("%w: expected version %d, got %d", ErrInvalidMigrationsOrder, idx+1, migration.Version)
+		}
+	}
+
+	err := database.AutoMigrate(&schemaVersion{})
+	if err != nil {
+		return nil, err
+	}
+
+	return &migrator{
+		database:   database,
+		migrations: migrations,
+	}, nil
+}
+
+// CurrentVersion returns the version of the last migration applied on the database
+func (m *migrator) CurrentVersion() (uint32, error) {
+	var current schemaVersion
+	record := m.database.Order("version DESC").Limit(1).Find(&current)
+	if record.Error != nil {
+		return 0, record.Error
+	}
+	return current.Version, nil
+}
+
+// LatestVersion returns the version of the last migration known by the application
+func (m *migrator) LatestVersion() uint32 {
+	return uint32(len(m.migrations))
+}
+
+// Status returns all known migrations and whether each one has been applied
+func (m *migrator) Status() ([]MigrationStatus, error) {
+	var applied []schemaVersion
+	record := m.database.Order("version").Find(&applied)
+	if record.Error != nil {
+		return nil, record.Error
+	}
+
+	appliedByVersion := make(map[uint32]schemaVersion)
+	for _, version := range applied {
+		appliedByVersion[version.Version] = version
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := MigrationStatus{
+			Version: migration.Version,
+			Name:    migration.Name,
+		}
+		version, ok := appliedByVersion[migration.Version]
+		if ok {
+			appliedAt := version.AppliedAt
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Up applies all pending migrations
+func (m *migrator) Up() error {
+	return m.To(m.LatestVersion())
+}
+
+// Down rolls back the last applied migration
+func (m *migrator) Down() error {
+	current, err := m.CurrentVersion()
+	if err != nil {
+		return err
+	}
+	if current == 0 {
+		return ErrNothingToRollback
+	}
+	return m.To(current - 1)
+}
+
+// To applies or rolls back migrations until the schema reaches the provided version
+func (m *migrator) To(version uint32) error {
+	if version > m.LatestVersion() {
+		return fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+	}
+
+	current, err := m.CurrentVersion()
+	if err != nil {
+		return err
+	}
+	if current > m.LatestVersion() {
+		return fmt.Errorf("%w: database at %d, application at %d", ErrSchemaAhead, current, m.LatestVersion())
+	}
+
+	for current < version {
+		err = m.apply(m.migrations[current])
+		if err != nil {
+			return err
+		}
+		current++
+	}
+	for current > version {
+		err = m.rollback(m.migrations[current-1])
+		if err != nil {
+			return err
+		}
+		current--
+	}
+	return nil
+}
+
+// CheckSchema returns an error if the database schema does not match the latest known migration
+func (m *migrator) CheckSchema() error {
+	current, err := m.CurrentVersion()
+	if err != nil {
+		return err
+	}
+	if current < m.LatestVersion() {
+		return fmt.Errorf("%w: database at %d, application at %d", ErrSchemaBehind, current, m.LatestVersion())
+	}
+	if current > m.LatestVersion() {
+		return fmt.Errorf("%w: database at %d, application at %d", ErrSchemaAhead, current, m.LatestVersion())
+	}
+	return nil
+}
+
+func (m *migrator) apply(migration Migration) error {
+	log.Info("applying migration", "version", migration.Version, "name", migration.Name)
+
+	return m.database.Transaction(func(tx *gorm.DB) error {
+		err := migration.Up(tx)
+		if err != nil {
+			return fmt.Errorf("%w while applying migration %d (%s)", err, migration.Version, migration.Name)
+		}
+
+		return tx.Create(&schemaVersion{
+			Version:   migration.Version,
+			Name:      migration.Name,
+			AppliedAt: time.Now(),
+		}).Error
+	})
+}
+
+func (m *migrator) rollback(migration Migration) error {
+	log.Info("rolling back migration", "version", migration.Version, "name", migration.Name)
+
+	return m.database.Transaction(func(tx *gorm.DB) error {
+		err := migration.Down(tx)
+		if err != nil {
+			return fmt.Errorf("%w while rolling back migration %d (%s)", err, migration.Version, migration.Name)
+		}
+
+		return tx.Delete(&schemaVersion{}, migration.Version).Error
+	})
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (m *migrator) IsInterfaceNil() bool {
+	return m == nil
+}
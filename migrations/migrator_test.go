@@ -0,0 +1,265 @@
+package migrations
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type testTable struct {
+	ID uint
+}
+
+func createTestDatabase(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.Nil(t, err)
+	sqlDB, err := db.DB()
+	require.Nil(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	return db
+}
+
+func createTestMigrations() []Migration {
+	return []Migration{
+		initialSchema(),
+		{
+			Version: 2,
+			Name:    "test table",
+			Up: func(tx *gorm.DB) error {
+				return tx.Migrator().CreateTable(&testTable{})
+			},
+			Down: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&testTable{})
+			},
+		},
+	}
+}
+
+func TestNewMigrator(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil database should error", func(t *testing.T) {
+		t.Parallel()
+
+		m, err := NewMigrator(nil, All())
+		assert.Equal(t, ErrNilDatabase, err)
+		assert.True(t, check.IfNil(m))
+	})
+	t.Run("gap in versions should error", func(t *testing.T) {
+		t.Parallel()
+
+		migrations := createTestMigrations()
+		migrations[1].Version = 3
+		m, err := NewMigrator(createTestDatabase(t), migrations)
+		assert.True(t, errors.Is(err, ErrInvalidMigrationsOrder))
+		assert.True(t, check.IfNil(m))
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		m, err := NewMigrator(createTestDatabase(t), All())
+		assert.Nil(t, err)
+		assert.False(t, check.IfNil(m))
+		assert.Equal(t, uint32(len(All())), m.LatestVersion())
+	})
+}
+
+func TestMigrator_UpDownTo(t *testing.T) {
+	t.Parallel()
+
+	db := createTestDatabase(t)
+	m, err := NewMigrator(db, createTestMigrations())
+	require.Nil(t, err)
+
+	assert.True(t, errors.Is(m.CheckSchema(), ErrSchemaBehind))
+	assert.Equal(t, ErrNothingToRollback, m.Down())
+
+	require.Nil(t, m.Up())
+	assert.Nil(t, m.CheckSchema())
+	assert.True(t, db.Migrator().HasTable("students"))
+	assert.True(t, db.Migrator().HasTable(&testTable{}))
+
+	statuses, err := m.Status()
+	require.Nil(t, err)
+	require.Equal(t, 2, len(statuses))
+	assert.True(t, statuses[0].Applied)
+	assert.True(t, statuses[1].Applied)
+
+	require.Nil(t, m.Down())
+	current, err := m.CurrentVersion()
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(1), current)
+	assert.False(t, db.Migrator().HasTable(&testTable{}))
+
+	assert.True(t, errors.Is(m.To(3), ErrUnknownVersion))
+
+	require.Nil(t, m.To(0))
+	assert.False(t, db.Migrator().HasTable("students"))
+
+	statuses, err = m.Status()
+	require.Nil(t, err)
+	assert.False(t, statuses[0].Applied)
+}
+
+func TestMigrator_SchemaAhead(t *testing.T) {
+	t.Parallel()
+
+	db := createTestDatabase(t)
+	m, err := NewMigrator(db, createTestMigrations())
+	require.Nil(t, err)
+	require.Nil(t, m.Up())
+
+	older, err := NewMigrator(db, createTestMigrations()[:1])
+	require.Nil(t, err)
+	assert.True(t, errors.Is(older.CheckSchema(), ErrSchemaAhead))
+	assert.True(t, errors.Is(older.Up(), ErrSchemaAhead))
+}
+
+func TestInitialSchema_KeepsExistingTables(t *testing.T) {
+	t.Parallel()
+
+	db := createTestDatabase(t)
+	require.Nil(t, db.Migrator().CreateTable(&v1Exam{}))
+	require.Nil(t, db.Create(&v1Exam{Nume: "simulare"}).Error)
+
+	m, err := NewMigrator(db, All())
+	require.Nil(t, err)
+	require.Nil(t, m.Up())
+
+	var count int64
+	db.Model(&v1Exam{}).Count(&count)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestAll_UpAndDown(t *testing.T) {
+	t.Parallel()
+
+	db := createTestDatabase(t)
+	m, err := NewMigrator(db, All())
+	require.Nil(t, err)
+
+	require.Nil(t, m.Up())
+	assert.Nil(t, m.CheckSchema())
+	require.Nil(t, m.To(0))
+	require.Nil(t, m.Up())
+	assert.Nil(t, m.CheckSchema())
+}
+
+func TestDoubleGrading_KeepsExistingCalificative(t *testing.T) {
+	t.Parallel()
+
+	db := createTestDatabase(t)
+	m, err := NewMigrator(db, All())
+	require.Nil(t, err)
+	require.Nil(t, m.To(3))
+	require.Nil(t, db.Create(&v3Calificativ{Student: 1, Exam: "simulare", Exercitiu: 2, Varianta: "B"}).Error)
+
+	require.Nil(t, m.To(4))
+	var calificative []v4Calificativ
+	require.Nil(t, db.Find(&calificative).Error)
+	require.Equal(t, 1, len(calificative))
+	assert.Equal(t, "2", calificative[0].Exercitiu)
+	assert.Equal(t, uint8(1), calificative[0].Slot)
+
+	require.Nil(t, m.To(3))
+	var restored []v3Calificativ
+	require.Nil(t, db.Find(&restored).Error)
+	require.Equal(t, 1, len(restored))
+	assert.Equal(t, 2, restored[0].Exercitiu)
+}
+
+func TestVariantRubrics_RestoresJoinedVariante(t *testing.T) {
+	t.Parallel()
+
+	db := createTestDatabase(t)
+	m, err := NewMigrator(db, All())
+	require.Nil(t, err)
+	require.Nil(t, m.To(6))
+	require.Nil(t, db.Exec("INSERT INTO exercitius (numar, materie, exam) VALUES ('1', 'matematica', 'simulare')").Error)
+	for idx, nume := range []string{"B", "A"} {
+		require.Nil(t, db.Create(&v6VariantaExercitiu{Exam: "simulare", Exercitiu: "1", Nume: nume, Ordine: idx}).Error)
+	}
+
+	require.Nil(t, m.To(5))
+	var exercitiu v2Exercitiu
+	require.Nil(t, db.First(&exercitiu).Error)
+	assert.Equal(t, "B;A", exercitiu.Variante)
+}
+
+func TestExamLifecycle_OpensGradingForExistingExams(t *testing.T) {
+	t.Parallel()
+
+	db := createTestDatabase(t)
+	m, err := NewMigrator(db, All())
+	require.Nil(t, err)
+	require.Nil(t, m.To(7))
+	require.Nil(t, db.Exec("INSERT INTO exams (nume) VALUES ('simulare')").Error)
+
+	require.Nil(t, m.To(8))
+	var exam v8Exam
+	require.Nil(t, db.First(&exam).Error)
+	assert.Equal(t, "notare_deschisa", exam.Status)
+
+	require.Nil(t, db.Create(&v8Exam{Nume: "evaluare"}).Error)
+	var created v8Exam
+	require.Nil(t, db.Where("nume = ?", "evaluare").First(&created).Error)
+	assert.Equal(t, "ciorna", created.Status)
+}
+
+func TestExamAbsences_ConvertsAbsentStudents(t *testing.T) {
+	t.Parallel()
+
+	db := createTestDatabase(t)
+	m, err := NewMigrator(db, All())
+	require.Nil(t, err)
+	require.Nil(t, m.To(8))
+	require.Nil(t, db.Exec("INSERT INTO students (username, exam_stiinta, exam_limba, absent) VALUES ('a', 'mate', 'romana', true)").Error)
+	require.Nil(t, db.Exec("INSERT INTO students (username, exam_stiinta, absent) VALUES ('b', 'mate', false)").Error)
+
+	require.Nil(t, m.To(9))
+	var absente []v9Absenta
+	require.Nil(t, db.Order("exam").Find(&absente).Error)
+	require.Equal(t, 2, len(absente))
+	assert.Equal(t, "mate", absente[0].Exam)
+	assert.Equal(t, "romana", absente[1].Exam)
+	assert.False(t, db.Migrator().HasColumn(&v1Student{}, "Absent"))
+	assert.True(t, db.Migrator().HasIndex(&v1Student{}, "idx_students_deleted_at"))
+
+	require.Nil(t, m.To(8))
+	var absent int64
+	require.Nil(t, db.Table("students").Where("absent = ?", true).Count(&absent).Error)
+	assert.Equal(t, int64(1), absent)
+}
+
+func TestRoles_ConvertsAdminFlag(t *testing.T) {
+	t.Parallel()
+
+	db := createTestDatabase(t)
+	m, err := NewMigrator(db, All())
+	require.Nil(t, err)
+	require.Nil(t, m.To(12))
+	require.Nil(t, db.Exec("INSERT INTO profesors (username, email, type, is_admin) VALUES ('a', 'a@test.ro', 'profesor', true)").Error)
+	require.Nil(t, db.Exec("INSERT INTO profesors (username, email, type, is_admin) VALUES ('b', 'b@test.ro', 'profesor', false)").Error)
+
+	require.Nil(t, m.To(13))
+	var roluri []v13RolUtilizator
+	require.Nil(t, db.Order("email, rol").Find(&roluri).Error)
+	require.Equal(t, 3, len(roluri))
+	assert.Equal(t, "a@test.ro", roluri[0].Email)
+	assert.Equal(t, "admin", roluri[0].Rol)
+	assert.Equal(t, "profesor", roluri[1].Rol)
+	assert.Equal(t, "b@test.ro", roluri[2].Email)
+	assert.False(t, db.Migrator().HasColumn(&v1Profesor{}, "IsAdmin"))
+	assert.True(t, db.Migrator().HasIndex(&v1Profesor{}, "idx_profesors_deleted_at"))
+
+	require.Nil(t, m.To(12))
+	var admins int64
+	require.Nil(t, db.Table("profesors").Where("is_admin = ?", true).Count(&admins).Error)
+	assert.Equal(t, int64(1), admins)
+}
@@ -0,0 +1,23 @@
+package migrations
+
+// All returns every schema migration known by the application, ordered by version.
+// New migrations must be appended at the end and never modified once released
+func All() []Migration {
+	return []Migration{
+		initialSchema(),
+		exercisePoints(),
+		gradeRules(),
+		doubleGrading(),
+		contestatii(),
+		variantRubrics(),
+		sections(),
+		examLifecycle(),
+		examAbsences(),
+		competencies(),
+		comments(),
+		sessions(),
+		roles(),
+		invitations(),
+		studentSessions(),
+	}
+}
@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Migration is a numbered, reversible schema change
+type Migration struct {
+	Version uint32
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+// MigrationStatus describes whether a migration has been applied to the database
+type MigrationStatus struct {
+	Version   uint32     `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+// schemaVersion is a row of the schema_version table, one for each applied migration
+type schemaVersion struct {
+	Version   uint32 `gorm:"primarykey;autoIncrement:false"`
+	Name      string
+	AppliedAt time.Time
+}
+
+// TableName returns the name of the table holding the applied migrations
+func (schemaVersion) TableName() string {
+	return "schema_version"
+}
@@ -0,0 +1,45 @@
+package migrations
+
+import "gorm.io/gorm"
+
+type v10CompetentaExercitiu struct {
+	Exam       string `gorm:"primarykey"`
+	Exercitiu  string `gorm:"primarykey"`
+	Competenta string `gorm:"primarykey"`
+}
+
+func (v10CompetentaExercitiu) TableName() string {
+	return "competenta_exercitius"
+}
+
+type v10Competenta struct {
+	Materie   string `gorm:"primarykey"`
+	Cod       string `gorm:"primarykey"`
+	Descriere string
+}
+
+func (v10Competenta) TableName() string {
+	return "competenta"
+}
+
+// competencies adds the curriculum competencies assessed by each exercise and their descriptions
+func competencies() Migration {
+	return Migration{
+		Version: 10,
+		Name:    "competencies",
+		Up: func(tx *gorm.DB) error {
+			err := tx.Migrator().CreateTable(&v10CompetentaExercitiu{})
+			if err != nil {
+				return err
+			}
+			return tx.Migrator().CreateTable(&v10Competenta{})
+		},
+		Down: func(tx *gorm.DB) error {
+			err := tx.Migrator().DropTable(&v10Competenta{})
+			if err != nil {
+				return err
+			}
+			return tx.Migrator().DropTable(&v10CompetentaExercitiu{})
+		},
+	}
+}
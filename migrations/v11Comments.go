@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type v11Comentariu struct {
+	Student   uint   `gorm:"primarykey;autoIncrement:false"`
+	Exam      string `gorm:"primarykey"`
+	Exercitiu string `gorm:"primarykey"`
+	Profesor  uint   `gorm:"primarykey;autoIncrement:false"`
+	Text      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (v11Comentariu) TableName() string {
+	return "comentarius"
+}
+
+// comments adds the feedback of the profesori on the exercises of a paper and on the whole paper
+func comments() Migration {
+	return Migration{
+		Version: 11,
+		Name:    "comments",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&v11Comentariu{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&v11Comentariu{})
+		},
+	}
+}
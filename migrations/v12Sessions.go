@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type v12Sesiune struct {
+	ID          string `gorm:"primarykey;size:64"`
+	Email       string `gorm:"index;size:255"`
+	RefreshHash string `gorm:"uniqueIndex;size:64"`
+	ExpiresAt   time.Time
+	RevokedAt   *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (v12Sesiune) TableName() string {
+	return "sesiunes"
+}
+
+// sessions stores the logins of the profesori, so their tokens can be refreshed and revoked
+func sessions() Migration {
+	return Migration{
+		Version: 12,
+		Name:    "sessions",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&v12Sesiune{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&v12Sesiune{})
+		},
+	}
+}
@@ -0,0 +1,76 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type v13RolUtilizator struct {
+	Email     string `gorm:"primarykey;size:255"`
+	Rol       string `gorm:"primarykey;size:32"`
+	Clasa     string `gorm:"primarykey;size:64"`
+	CreatedAt time.Time
+}
+
+func (v13RolUtilizator) TableName() string {
+	return "rol_utilizators"
+}
+
+// roles replaces the admin flag of the profesori with assigned roles. Every profesor gets the profesor role
+// and the admins also get the admin role
+func roles() Migration {
+	return Migration{
+		Version: 13,
+		Name:    "roles",
+		Up: func(tx *gorm.DB) error {
+			err := tx.Migrator().CreateTable(&v13RolUtilizator{})
+			if err != nil {
+				return err
+			}
+
+			var profesori []v1Profesor
+			err = tx.Find(&profesori).Error
+			if err != nil {
+				return err
+			}
+			for _, profesor := range profesori {
+				roluri := []string{"profesor"}
+				if profesor.IsAdmin {
+					roluri = append(roluri, "admin")
+				}
+				for _, rol := range roluri {
+					rolUtilizator := v13RolUtilizator{Email: profesor.User.Email, Rol: rol}
+					err = tx.Where("email = ? AND rol = ? AND clasa = ?", rolUtilizator.Email, rol, "").
+						FirstOrCreate(&rolUtilizator).Error
+					if err != nil {
+						return err
+					}
+				}
+			}
+
+			err = tx.Migrator().DropColumn(&v1Profesor{}, "IsAdmin")
+			if err != nil {
+				return err
+			}
+			return restoreIndex(tx, &v1Profesor{}, "idx_profesors_deleted_at")
+		},
+		Down: func(tx *gorm.DB) error {
+			err := tx.Migrator().AddColumn(&v1Profesor{}, "IsAdmin")
+			if err != nil {
+				return err
+			}
+			err = tx.Model(&v1Profesor{}).
+				Where("email IN (?)", tx.Model(&v13RolUtilizator{}).Where("rol = ?", "admin").Select("email")).
+				Update("is_admin", true).Error
+			if err != nil {
+				return err
+			}
+			err = tx.Migrator().DropTable(&v13RolUtilizator{})
+			if err != nil {
+				return err
+			}
+			return restoreIndex(tx, &v1Profesor{}, "idx_profesors_deleted_at")
+		},
+	}
+}
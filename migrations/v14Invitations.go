@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type v14Invitatie struct {
+	ID        string `gorm:"primarykey;size:64"`
+	Email     string `gorm:"size:255"`
+	Rol       string `gorm:"size:32"`
+	Admin     string `gorm:"size:255"`
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+func (v14Invitatie) TableName() string {
+	return "invitaties"
+}
+
+// invitations stores the setup tokens and the invitations that can register admins
+func invitations() Migration {
+	return Migration{
+		Version: 14,
+		Name:    "invitations",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&v14Invitatie{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&v14Invitatie{})
+		},
+	}
+}
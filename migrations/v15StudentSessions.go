@@ -0,0 +1,49 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type v15Sesiune struct {
+	ID          string `gorm:"primarykey;size:64"`
+	Email       string `gorm:"index;size:255"`
+	Student     uint   `gorm:"default:0"`
+	RefreshHash string `gorm:"uniqueIndex;size:64"`
+	ExpiresAt   time.Time
+	RevokedAt   *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (v15Sesiune) TableName() string {
+	return "sesiunes"
+}
+
+// studentSessions records the student a session belongs to, so students can log in and refresh their tokens.
+// The sessions of the profesori keep student 0
+func studentSessions() Migration {
+	return Migration{
+		Version: 15,
+		Name:    "student sessions",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().AddColumn(&v15Sesiune{}, "Student")
+		},
+		Down: func(tx *gorm.DB) error {
+			err := tx.Where("student <> ?", 0).Delete(&v15Sesiune{}).Error
+			if err != nil {
+				return err
+			}
+			err = tx.Migrator().DropColumn(&v15Sesiune{}, "Student")
+			if err != nil {
+				return err
+			}
+			err = restoreIndex(tx, &v15Sesiune{}, "idx_sesiunes_email")
+			if err != nil {
+				return err
+			}
+			return restoreIndex(tx, &v15Sesiune{}, "idx_sesiunes_refresh_hash")
+		},
+	}
+}
@@ -0,0 +1,121 @@
+package migrations
+
+import "gorm.io/gorm"
+
+// the models below are a snapshot of the schema created by the former AutoMigrate calls, so that later
+// changes of the authentication models do not alter what this migration creates
+
+type v1User struct {
+	gorm.Model
+	Nume     string
+	Prenume  string
+	Username string `gorm:"unique"`
+	Email    string
+	Password string
+	Type     string
+}
+
+type v1Profesor struct {
+	User    v1User `gorm:"embedded"`
+	IsAdmin bool
+	Materie string
+}
+
+func (v1Profesor) TableName() string {
+	return "profesors"
+}
+
+type v1Student struct {
+	User        v1User `gorm:"embedded"`
+	Absent      bool
+	Clasa       string
+	ExamStiinta string
+	ExamLimba   string
+}
+
+func (v1Student) TableName() string {
+	return "students"
+}
+
+type v1Exam struct {
+	Nume string `gorm:"primarykey"`
+}
+
+func (v1Exam) TableName() string {
+	return "exams"
+}
+
+type v1Clasa struct {
+	Nume        string `gorm:"primarykey"`
+	ProfMate    string
+	ProfFizica  string
+	ProfBio     string
+	ProfRomana  string
+	ProfEngleza string
+}
+
+func (v1Clasa) TableName() string {
+	return "clasas"
+}
+
+type v1Exercitiu struct {
+	Numar    string `gorm:"primarykey"`
+	Variante string
+	Materie  string
+	Exam     string `gorm:"primarykey"`
+}
+
+func (v1Exercitiu) TableName() string {
+	return "exercitius"
+}
+
+type v1Calificativ struct {
+	Student   uint `gorm:"primarykey;autoIncrement:false"`
+	Profesor  uint
+	Exam      string `gorm:"primarykey"`
+	Exercitiu int    `gorm:"primarykey;autoIncrement:false"`
+	Varianta  string
+}
+
+func (v1Calificativ) TableName() string {
+	return "calificativs"
+}
+
+// initialSchema creates the tables previously managed by AutoMigrate. Tables that already exist are kept
+// as they are, so databases created before the migrations subsystem can adopt it
+func initialSchema() Migration {
+	models := []interface{}{
+		&v1Profesor{},
+		&v1Student{},
+		&v1Exam{},
+		&v1Clasa{},
+		&v1Exercitiu{},
+		&v1Calificativ{},
+	}
+
+	return Migration{
+		Version: 1,
+		Name:    "initial schema",
+		Up: func(tx *gorm.DB) error {
+			for _, model := range models {
+				if tx.Migrator().HasTable(model) {
+					continue
+				}
+				err := tx.Migrator().CreateTable(model)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			for i := len(models) - 1; i >= 0; i-- {
+				err := tx.Migrator().DropTable(models[i])
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,104 @@
+package migrations
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type v2Exercitiu struct {
+	Numar        string `gorm:"primarykey"`
+	Variante     string
+	Materie      string
+	Exam         string `gorm:"primarykey"`
+	PunctajMaxim *float64
+	Pondere      float64 `gorm:"default:1"`
+}
+
+func (v2Exercitiu) TableName() string {
+	return "exercitius"
+}
+
+type v2VariantaExercitiu struct {
+	Exam      string `gorm:"primarykey"`
+	Exercitiu string `gorm:"primarykey"`
+	Nume      string `gorm:"primarykey"`
+	Puncte    float64
+	Ordine    int
+}
+
+func (v2VariantaExercitiu) TableName() string {
+	return "varianta_exercitius"
+}
+
+type v2Scor struct {
+	Student      uint   `gorm:"primarykey;autoIncrement:false"`
+	Exam         string `gorm:"primarykey"`
+	Materie      string `gorm:"primarykey"`
+	Punctaj      float64
+	PunctajMaxim float64
+	UpdatedAt    time.Time
+}
+
+func (v2Scor) TableName() string {
+	return "scors"
+}
+
+// exercisePoints adds the optional maximum and weight of an exercise, moves the variants into their own table
+// so each one can carry a point value, and creates the table holding the computed totals. Existing variants
+// are copied with 0 points
+func exercisePoints() Migration {
+	return Migration{
+		Version: 2,
+		Name:    "exercise points",
+		Up: func(tx *gorm.DB) error {
+			err := tx.Migrator().AddColumn(&v2Exercitiu{}, "PunctajMaxim")
+			if err != nil {
+				return err
+			}
+			err = tx.Migrator().AddColumn(&v2Exercitiu{}, "Pondere")
+			if err != nil {
+				return err
+			}
+			err = tx.Migrator().CreateTable(&v2VariantaExercitiu{}, &v2Scor{})
+			if err != nil {
+				return err
+			}
+
+			var exercitii []v2Exercitiu
+			err = tx.Find(&exercitii).Error
+			if err != nil {
+				return err
+			}
+			for _, exercitiu := range exercitii {
+				for idx, nume := range strings.Split(exercitiu.Variante, ";") {
+					if len(nume) == 0 {
+						continue
+					}
+					err = tx.Create(&v2VariantaExercitiu{
+						Exam:      exercitiu.Exam,
+						Exercitiu: exercitiu.Numar,
+						Nume:      nume,
+						Ordine:    idx,
+					}).Error
+					if err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			err := tx.Migrator().DropTable(&v2Scor{}, &v2VariantaExercitiu{})
+			if err != nil {
+				return err
+			}
+			err = tx.Migrator().DropColumn(&v2Exercitiu{}, "Pondere")
+			if err != nil {
+				return err
+			}
+			return tx.Migrator().DropColumn(&v2Exercitiu{}, "PunctajMaxim")
+		},
+	}
+}
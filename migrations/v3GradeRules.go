@@ -0,0 +1,35 @@
+package migrations
+
+import "gorm.io/gorm"
+
+type v3Exam struct {
+	Nume         string `gorm:"primarykey"`
+	PuncteOficiu float64
+	Rotunjire    string `gorm:"default:trunchiere"`
+}
+
+func (v3Exam) TableName() string {
+	return "exams"
+}
+
+// gradeRules adds the rule used to convert the points of an exam into a grade
+func gradeRules() Migration {
+	return Migration{
+		Version: 3,
+		Name:    "grade rules",
+		Up: func(tx *gorm.DB) error {
+			err := tx.Migrator().AddColumn(&v3Exam{}, "PuncteOficiu")
+			if err != nil {
+				return err
+			}
+			return tx.Migrator().AddColumn(&v3Exam{}, "Rotunjire")
+		},
+		Down: func(tx *gorm.DB) error {
+			err := tx.Migrator().DropColumn(&v3Exam{}, "Rotunjire")
+			if err != nil {
+				return err
+			}
+			return tx.Migrator().DropColumn(&v3Exam{}, "PuncteOficiu")
+		},
+	}
+}
@@ -0,0 +1,157 @@
+package migrations
+
+import (
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type v3Calificativ struct {
+	Student   uint `gorm:"primarykey;autoIncrement:false"`
+	Profesor  uint
+	Exam      string `gorm:"primarykey"`
+	Exercitiu int    `gorm:"primarykey;autoIncrement:false"`
+	Varianta  string
+}
+
+func (v3Calificativ) TableName() string {
+	return "calificativs"
+}
+
+type v4Calificativ struct {
+	Student   uint `gorm:"primarykey;autoIncrement:false"`
+	Profesor  uint
+	Exam      string `gorm:"primarykey"`
+	Exercitiu string `gorm:"primarykey"`
+	Slot      uint8  `gorm:"primarykey;autoIncrement:false;default:1"`
+	Varianta  string
+}
+
+func (v4Calificativ) TableName() string {
+	return "calificativs"
+}
+
+type v4Exam struct {
+	Nume         string `gorm:"primarykey"`
+	PuncteOficiu float64
+	Rotunjire    string  `gorm:"default:trunchiere"`
+	PragArbitraj float64 `gorm:"default:1"`
+}
+
+func (v4Exam) TableName() string {
+	return "exams"
+}
+
+type v4Evaluator struct {
+	Clasa    string `gorm:"primarykey"`
+	Exam     string `gorm:"primarykey"`
+	Materie  string `gorm:"primarykey"`
+	Slot     uint8  `gorm:"primarykey;autoIncrement:false"`
+	Profesor uint
+}
+
+func (v4Evaluator) TableName() string {
+	return "evaluators"
+}
+
+type v4Arbitraj struct {
+	Student   uint   `gorm:"primarykey;autoIncrement:false"`
+	Exam      string `gorm:"primarykey"`
+	Materie   string `gorm:"primarykey"`
+	Profesor  uint
+	Nota1     float64
+	Nota2     float64
+	Status    string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (v4Arbitraj) TableName() string {
+	return "arbitrajs"
+}
+
+// doubleGrading adds the grading slot to the primary key of calificativs, so that several correctors can grade
+// the same paper, and stores the exercise number as text like everywhere else. Existing marks go to slot 1.
+// It also creates the evaluator assignments and the arbitrations
+func doubleGrading() Migration {
+	return Migration{
+		Version: 4,
+		Name:    "double grading",
+		Up: func(tx *gorm.DB) error {
+			var calificative []v3Calificativ
+			err := tx.Find(&calificative).Error
+			if err != nil {
+				return err
+			}
+			err = tx.Migrator().DropTable(&v3Calificativ{})
+			if err != nil {
+				return err
+			}
+			err = tx.Migrator().CreateTable(&v4Calificativ{})
+			if err != nil {
+				return err
+			}
+			for _, calificativ := range calificative {
+				err = tx.Create(&v4Calificativ{
+					Student:   calificativ.Student,
+					Profesor:  calificativ.Profesor,
+					Exam:      calificativ.Exam,
+					Exercitiu: strconv.Itoa(calificativ.Exercitiu),
+					Slot:      1,
+					Varianta:  calificativ.Varianta,
+				}).Error
+				if err != nil {
+					return err
+				}
+			}
+
+			err = tx.Migrator().AddColumn(&v4Exam{}, "PragArbitraj")
+			if err != nil {
+				return err
+			}
+			return tx.Migrator().CreateTable(&v4Evaluator{}, &v4Arbitraj{})
+		},
+		Down: func(tx *gorm.DB) error {
+			err := tx.Migrator().DropTable(&v4Arbitraj{}, &v4Evaluator{})
+			if err != nil {
+				return err
+			}
+			err = tx.Migrator().DropColumn(&v4Exam{}, "PragArbitraj")
+			if err != nil {
+				return err
+			}
+
+			var calificative []v4Calificativ
+			err = tx.Where("slot = ?", 1).Find(&calificative).Error
+			if err != nil {
+				return err
+			}
+			err = tx.Migrator().DropTable(&v4Calificativ{})
+			if err != nil {
+				return err
+			}
+			err = tx.Migrator().CreateTable(&v3Calificativ{})
+			if err != nil {
+				return err
+			}
+			for _, calificativ := range calificative {
+				exercitiu, errConv := strconv.Atoi(calificativ.Exercitiu)
+				if errConv != nil {
+					return errConv
+				}
+				err = tx.Create(&v3Calificativ{
+					Student:   calificativ.Student,
+					Profesor:  calificativ.Profesor,
+					Exam:      calificativ.Exam,
+					Exercitiu: exercitiu,
+					Varianta:  calificativ.Varianta,
+				}).Error
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,61 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type v5Exam struct {
+	Nume            string `gorm:"primarykey"`
+	PuncteOficiu    float64
+	Rotunjire       string  `gorm:"default:trunchiere"`
+	PragArbitraj    float64 `gorm:"default:1"`
+	PragContestatie float64 `gorm:"default:0.5"`
+}
+
+func (v5Exam) TableName() string {
+	return "exams"
+}
+
+type v5Contestatie struct {
+	Student      uint   `gorm:"primarykey;autoIncrement:false"`
+	Exam         string `gorm:"primarykey"`
+	Materie      string `gorm:"primarykey"`
+	DepusaDe     string
+	Motiv        string
+	Profesor     uint
+	Status       string
+	NotaInitiala float64
+	NotaNoua     *float64
+	NotaFinala   *float64
+	Aplicata     bool
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (v5Contestatie) TableName() string {
+	return "contestaties"
+}
+
+// contestatii adds the appeals and the minimal grade difference for which a re-evaluation changes the grade
+func contestatii() Migration {
+	return Migration{
+		Version: 5,
+		Name:    "contestatii",
+		Up: func(tx *gorm.DB) error {
+			err := tx.Migrator().AddColumn(&v5Exam{}, "PragContestatie")
+			if err != nil {
+				return err
+			}
+			return tx.Migrator().CreateTable(&v5Contestatie{})
+		},
+		Down: func(tx *gorm.DB) error {
+			err := tx.Migrator().DropTable(&v5Contestatie{})
+			if err != nil {
+				return err
+			}
+			return tx.Migrator().DropColumn(&v5Exam{}, "PragContestatie")
+		},
+	}
+}
@@ -0,0 +1,76 @@
+package migrations
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type v6VariantaExercitiu struct {
+	Exam           string `gorm:"primarykey"`
+	Exercitiu      string `gorm:"primarykey"`
+	Nume           string `gorm:"primarykey"`
+	Puncte         float64
+	Ordine         int
+	Rubrica        string
+	ExempluRaspuns string
+}
+
+func (v6VariantaExercitiu) TableName() string {
+	return "varianta_exercitius"
+}
+
+// variantRubrics adds the marking scheme and an optional example answer to every variant and drops the
+// ";"-joined list of variants of an exercise, which is fully covered by varianta_exercitius since version 2
+func variantRubrics() Migration {
+	return Migration{
+		Version: 6,
+		Name:    "variant rubrics",
+		Up: func(tx *gorm.DB) error {
+			err := tx.Migrator().AddColumn(&v6VariantaExercitiu{}, "Rubrica")
+			if err != nil {
+				return err
+			}
+			err = tx.Migrator().AddColumn(&v6VariantaExercitiu{}, "ExempluRaspuns")
+			if err != nil {
+				return err
+			}
+			return tx.Migrator().DropColumn(&v2Exercitiu{}, "Variante")
+		},
+		Down: func(tx *gorm.DB) error {
+			err := tx.Migrator().AddColumn(&v2Exercitiu{}, "Variante")
+			if err != nil {
+				return err
+			}
+
+			var variante []v6VariantaExercitiu
+			err = tx.Order("exam, exercitiu, ordine").Find(&variante).Error
+			if err != nil {
+				return err
+			}
+			type exercitiuKey struct {
+				exam  string
+				numar string
+			}
+			nume := make(map[exercitiuKey][]string)
+			for _, varianta := range variante {
+				key := exercitiuKey{exam: varianta.Exam, numar: varianta.Exercitiu}
+				nume[key] = append(nume[key], varianta.Nume)
+			}
+			for key, list := range nume {
+				err = tx.Model(&v2Exercitiu{}).
+					Where("exam = ? AND numar = ?", key.exam, key.numar).
+					Update("variante", strings.Join(list, ";")).Error
+				if err != nil {
+					return err
+				}
+			}
+
+			err = tx.Migrator().DropColumn(&v6VariantaExercitiu{}, "ExempluRaspuns")
+			if err != nil {
+				return err
+			}
+			return tx.Migrator().DropColumn(&v6VariantaExercitiu{}, "Rubrica")
+		},
+	}
+}
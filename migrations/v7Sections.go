@@ -0,0 +1,50 @@
+package migrations
+
+import "gorm.io/gorm"
+
+type v7Exercitiu struct {
+	Numar        string `gorm:"primarykey"`
+	Materie      string
+	Exam         string `gorm:"primarykey"`
+	PunctajMaxim *float64
+	Pondere      float64 `gorm:"default:1"`
+	Sectiune     string
+}
+
+func (v7Exercitiu) TableName() string {
+	return "exercitius"
+}
+
+type v7Sectiune struct {
+	Exam    string `gorm:"primarykey"`
+	Cod     string `gorm:"primarykey"`
+	Titlu   string
+	Parinte string
+	Ordine  int
+}
+
+func (v7Sectiune) TableName() string {
+	return "sectiunes"
+}
+
+// sections adds the tree of sections of an exam. Exercises stay the graded leaves and reference their section
+func sections() Migration {
+	return Migration{
+		Version: 7,
+		Name:    "sections",
+		Up: func(tx *gorm.DB) error {
+			err := tx.Migrator().CreateTable(&v7Sectiune{})
+			if err != nil {
+				return err
+			}
+			return tx.Migrator().AddColumn(&v7Exercitiu{}, "Sectiune")
+		},
+		Down: func(tx *gorm.DB) error {
+			err := tx.Migrator().DropColumn(&v7Exercitiu{}, "Sectiune")
+			if err != nil {
+				return err
+			}
+			return tx.Migrator().DropTable(&v7Sectiune{})
+		},
+	}
+}
@@ -0,0 +1,73 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type v8Exam struct {
+	Nume            string `gorm:"primarykey"`
+	PuncteOficiu    float64
+	Rotunjire       string  `gorm:"default:trunchiere"`
+	PragArbitraj    float64 `gorm:"default:1"`
+	PragContestatie float64 `gorm:"default:0.5"`
+	Status          string  `gorm:"default:ciorna"`
+	InceputNotare   *time.Time
+	SfarsitNotare   *time.Time
+}
+
+func (v8Exam) TableName() string {
+	return "exams"
+}
+
+type v8TranzitieExam struct {
+	ID        uint `gorm:"primarykey"`
+	Exam      string
+	DinStatus string
+	InStatus  string
+	Admin     string
+	Motiv     string
+	CreatedAt time.Time
+}
+
+func (v8TranzitieExam) TableName() string {
+	return "tranzitie_exams"
+}
+
+// examLifecycle adds the state of an exam, the optional grading window and the audit of state changes.
+// Exams created before this version are already being graded, so they start with grading open
+func examLifecycle() Migration {
+	return Migration{
+		Version: 8,
+		Name:    "exam lifecycle",
+		Up: func(tx *gorm.DB) error {
+			for _, field := range []string{"Status", "InceputNotare", "SfarsitNotare"} {
+				err := tx.Migrator().AddColumn(&v8Exam{}, field)
+				if err != nil {
+					return err
+				}
+			}
+			err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).
+				Model(&v8Exam{}).
+				Update("status", "notare_deschisa").Error
+			if err != nil {
+				return err
+			}
+			return tx.Migrator().CreateTable(&v8TranzitieExam{})
+		},
+		Down: func(tx *gorm.DB) error {
+			err := tx.Migrator().DropTable(&v8TranzitieExam{})
+			if err != nil {
+				return err
+			}
+			for _, field := range []string{"SfarsitNotare", "InceputNotare", "Status"} {
+				err = tx.Migrator().DropColumn(&v8Exam{}, field)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,85 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type v9Absenta struct {
+	Student   uint   `gorm:"primarykey;autoIncrement:false"`
+	Exam      string `gorm:"primarykey"`
+	Motiv     string
+	Admin     string
+	CreatedAt time.Time
+}
+
+func (v9Absenta) TableName() string {
+	return "absenta"
+}
+
+// examAbsences replaces the absent flag of a student with one absence per exam. A student flagged as absent
+// is recorded as absent from every exam assigned to them
+func examAbsences() Migration {
+	return Migration{
+		Version: 9,
+		Name:    "exam absences",
+		Up: func(tx *gorm.DB) error {
+			err := tx.Migrator().CreateTable(&v9Absenta{})
+			if err != nil {
+				return err
+			}
+
+			var students []v1Student
+			err = tx.Where("absent = ?", true).Find(&students).Error
+			if err != nil {
+				return err
+			}
+			for _, student := range students {
+				for _, exam := range []string{student.ExamStiinta, student.ExamLimba} {
+					if len(exam) == 0 {
+						continue
+					}
+					absenta := v9Absenta{Student: student.User.ID, Exam: exam}
+					err = tx.Where(absenta).FirstOrCreate(&absenta).Error
+					if err != nil {
+						return err
+					}
+				}
+			}
+
+			err = tx.Migrator().DropColumn(&v1Student{}, "Absent")
+			if err != nil {
+				return err
+			}
+			return restoreDeletedAtIndex(tx)
+		},
+		Down: func(tx *gorm.DB) error {
+			err := tx.Migrator().AddColumn(&v1Student{}, "Absent")
+			if err != nil {
+				return err
+			}
+			err = tx.Model(&v1Student{}).
+				Where("id IN (?)", tx.Model(&v9Absenta{}).Select("student")).
+				Update("absent", true).Error
+			if err != nil {
+				return err
+			}
+			err = tx.Migrator().DropTable(&v9Absenta{})
+			if err != nil {
+				return err
+			}
+			return restoreDeletedAtIndex(tx)
+		},
+	}
+}
+
+// restoreDeletedAtIndex creates again the soft delete index of the students, which sqlite loses when it
+// rebuilds the table to add or drop a column
+func restoreDeletedAtIndex(tx *gorm.DB) error {
+	index := "idx_students_deleted_at"
+	if tx.Migrator().HasIndex(&v1Student{}, index) {
+		return nil
+	}
+	return tx.Migrator().CreateIndex(&v1Student{}, index)
+}
@@ -0,0 +1,78 @@
+package scoring
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// ValidateCompetencies checks that the competencies assessed by an exercise are not empty and not repeated
+func ValidateCompetencies(competente []string) error {
+	seen := make(map[string]bool, len(competente))
+	for _, cod := range competente {
+		if len(cod) == 0 {
+			return fmt.Errorf("%w: cod gol", ErrInvalidCompetency)
+		}
+		if seen[cod] {
+			return fmt.Errorf("%w: %s apare de doua ori", ErrInvalidCompetency, cod)
+		}
+		seen[cod] = true
+	}
+	return nil
+}
+
+// ComputeCompetencyTotals adds up the points obtained on each exercise, indexed by exercise number, into the
+// competencies the exercise assesses. Only the exercises found in puncte count, so ungraded exercises do not
+// lower the mastery. An exercise assessing several competencies counts in full for each of them. The totals
+// are returned sorted by subject and code
+func ComputeCompetencyTotals(schemes []ExerciseScheme, puncte map[string]float64) []*CompetencyTotal {
+	type competencyKey struct {
+		materie string
+		cod     string
+	}
+	totals := make(map[competencyKey]*CompetencyTotal)
+	for _, scheme := range schemes {
+		punctaj, graded := puncte[scheme.Numar]
+		if !graded {
+			continue
+		}
+		for _, cod := range scheme.Competente {
+			key := competencyKey{materie: scheme.Materie, cod: cod}
+			total, ok := totals[key]
+			if !ok {
+				total = &CompetencyTotal{
+					Materie:   scheme.Materie,
+					Cod:       cod,
+					Exercitii: make([]string, 0),
+				}
+				totals[key] = total
+			}
+			total.Exercitii = append(total.Exercitii, scheme.Numar)
+			total.Punctaj += punctaj
+			total.PunctajMaxim += MaxPoints(scheme)
+		}
+	}
+
+	result := make([]*CompetencyTotal, 0, len(totals))
+	for _, total := range totals {
+		sort.Strings(total.Exercitii)
+		total.Stapanire = Mastery(total.Punctaj, total.PunctajMaxim)
+		result = append(result, total)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Materie != result[j].Materie {
+			return result[i].Materie < result[j].Materie
+		}
+		return result[i].Cod < result[j].Cod
+	})
+	return result
+}
+
+// Mastery returns the points obtained as a percentage of the maximum, rounded to two decimals. A competency
+// without points to obtain has no mastery
+func Mastery(punctaj float64, punctajMaxim float64) float64 {
+	if punctajMaxim <= 0 {
+		return 0
+	}
+	return math.Round(punctaj*10000/punctajMaxim) / 100
+}
@@ -0,0 +1,61 @@
+package scoring
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateCompetencies(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, ValidateCompetencies([]string{"1.1", "2.3"}))
+	assert.Nil(t, ValidateCompetencies(nil))
+	assert.True(t, errors.Is(ValidateCompetencies([]string{"1.1", ""}), ErrInvalidCompetency))
+	assert.True(t, errors.Is(ValidateCompetencies([]string{"1.1", "1.1"}), ErrInvalidCompetency))
+}
+
+func TestComputeCompetencyTotals(t *testing.T) {
+	t.Parallel()
+
+	maxim := 4.0
+	schemes := []ExerciseScheme{
+		{Numar: "1", Materie: "matematica", Competente: []string{"1.1", "2.1"}, Puncte: map[string]float64{"A": 5}},
+		{Numar: "2", Materie: "matematica", Competente: []string{"2.1"}, Maxim: &maxim, Puncte: map[string]float64{"A": 1}},
+		{Numar: "3", Materie: "matematica", Competente: []string{"1.1"}, Puncte: map[string]float64{"A": 3}},
+		{Numar: "4", Materie: "fizica", Competente: []string{"1.1"}, Puncte: map[string]float64{"A": 2}},
+		{Numar: "5", Materie: "matematica", Puncte: map[string]float64{"A": 2}},
+	}
+	puncte := map[string]float64{"1": 5, "2": 1, "4": 0, "5": 2}
+
+	totals := ComputeCompetencyTotals(schemes, puncte)
+	require.Equal(t, 3, len(totals))
+	assert.Equal(t, &CompetencyTotal{
+		Materie:      "fizica",
+		Cod:          "1.1",
+		Exercitii:    []string{"4"},
+		PunctajMaxim: 2,
+	}, totals[0])
+	assert.Equal(t, &CompetencyTotal{
+		Materie:      "matematica",
+		Cod:          "1.1",
+		Exercitii:    []string{"1"},
+		Punctaj:      5,
+		PunctajMaxim: 5,
+		Stapanire:    100,
+	}, totals[1])
+	assert.Equal(t, []string{"1", "2"}, totals[2].Exercitii)
+	assert.Equal(t, 6.0, totals[2].Punctaj)
+	assert.Equal(t, 9.0, totals[2].PunctajMaxim)
+	assert.Equal(t, 66.67, totals[2].Stapanire)
+}
+
+func TestMastery(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 50.0, Mastery(1, 2))
+	assert.Equal(t, 33.33, Mastery(1, 3))
+	assert.Equal(t, 0.0, Mastery(0, 0))
+}
@@ -0,0 +1,9 @@
+package scoring
+
+import "errors"
+
+// ErrInvalidSection signals that the tree of sections of an exam is not valid
+var ErrInvalidSection = errors.New("sectiune invalida")
+
+// ErrInvalidCompetency signals that an exercise references an empty or repeated competency
+var ErrInvalidCompetency = errors.New("competenta invalida")
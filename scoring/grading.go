@@ -0,0 +1,76 @@
+package scoring
+
+import "math"
+
+const (
+	// RotunjireTrunchiere truncates grades to two decimals, as done for the official results
+	RotunjireTrunchiere = "trunchiere"
+
+	// RotunjireMatematica rounds grades half up to two decimals
+	RotunjireMatematica = "rotunjire"
+
+	notaMinima = 1.0
+	notaMaxima = 10.0
+
+	// epsilon absorbs the floating point error of values such as 7.8 stored as 7.7999999
+	epsilon = 1e-9
+)
+
+// GradeRule describes how the points of an exam are converted into a grade
+type GradeRule struct {
+	PuncteOficiu float64
+	Rotunjire    string
+}
+
+// IsValidRotunjire returns true if the provided rounding mode is known
+func IsValidRotunjire(rotunjire string) bool {
+	return rotunjire == RotunjireTrunchiere || rotunjire == RotunjireMatematica
+}
+
+// ComputeGrade converts the points obtained out of the maximum into a grade on the 1-10 scale. The ex officio
+// points are added to both the obtained and the maximum points
+func ComputeGrade(punctaj float64, punctajMaxim float64, rule GradeRule) float64 {
+	maxim := punctajMaxim + rule.PuncteOficiu
+	if maxim <= 0 {
+		return notaMinima
+	}
+
+	nota := (punctaj + rule.PuncteOficiu) * notaMaxima / maxim
+	nota = math.Max(notaMinima, math.Min(notaMaxima, nota))
+	return RoundGrade(nota, rule.Rotunjire)
+}
+
+// AverageGrade returns the average of the provided grades, rounded with the provided mode
+func AverageGrade(note []float64, rotunjire string) float64 {
+	if len(note) == 0 {
+		return 0
+	}
+
+	sum := 0.0
+	for _, nota := range note {
+		sum += nota
+	}
+	return RoundGrade(sum/float64(len(note)), rotunjire)
+}
+
+// RoundGrade reduces a grade to two decimals, truncating unless mathematical rounding is requested
+func RoundGrade(nota float64, rotunjire string) float64 {
+	if rotunjire == RotunjireMatematica {
+		return math.Round(nota*100) / 100
+	}
+	return math.Floor(nota*100+epsilon) / 100
+}
+
+// NeedsArbitraj returns true if the grades given by the two correctors differ by more than the threshold
+func NeedsArbitraj(nota1 float64, nota2 float64, prag float64) bool {
+	return math.Abs(nota1-nota2)-prag > epsilon
+}
+
+// ApplyContestatie returns the final grade after a re-evaluation. The new grade replaces the initial one only
+// if they differ by at least the threshold
+func ApplyContestatie(notaInitiala float64, notaNoua float64, prag float64) float64 {
+	if math.Abs(notaNoua-notaInitiala)-prag > -epsilon {
+		return notaNoua
+	}
+	return notaInitiala
+}
@@ -0,0 +1,60 @@
+package scoring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeGrade(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ex officio points", func(t *testing.T) {
+		t.Parallel()
+
+		rule := GradeRule{PuncteOficiu: 10, Rotunjire: RotunjireTrunchiere}
+		assert.Equal(t, 7.8, ComputeGrade(68, 90, rule))
+		assert.Equal(t, 10.0, ComputeGrade(90, 90, rule))
+		assert.Equal(t, 1.0, ComputeGrade(0, 90, rule))
+	})
+	t.Run("truncation vs rounding", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, 6.66, ComputeGrade(2, 3, GradeRule{Rotunjire: RotunjireTrunchiere}))
+		assert.Equal(t, 6.67, ComputeGrade(2, 3, GradeRule{Rotunjire: RotunjireMatematica}))
+	})
+	t.Run("grade is clamped to the 1-10 scale", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, 1.0, ComputeGrade(0, 0, GradeRule{}))
+		assert.Equal(t, 1.0, ComputeGrade(0.5, 10, GradeRule{}))
+		assert.Equal(t, 10.0, ComputeGrade(12, 10, GradeRule{}))
+	})
+}
+
+func TestAverageGrade(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 0.0, AverageGrade(nil, RotunjireTrunchiere))
+	assert.Equal(t, 8.72, AverageGrade([]float64{8.45, 9.0}, RotunjireTrunchiere))
+	assert.Equal(t, 8.73, AverageGrade([]float64{8.45, 9.0}, RotunjireMatematica))
+}
+
+func TestNeedsArbitraj(t *testing.T) {
+	t.Parallel()
+
+	assert.False(t, NeedsArbitraj(8.55, 7.55, 1))
+	assert.False(t, NeedsArbitraj(7.5, 8, 1))
+	assert.True(t, NeedsArbitraj(8.56, 7.55, 1))
+	assert.True(t, NeedsArbitraj(5, 9.5, 1))
+	assert.True(t, NeedsArbitraj(6, 6.5, 0))
+}
+
+func TestApplyContestatie(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 6.0, ApplyContestatie(6, 6.45, 0.5))
+	assert.Equal(t, 6.5, ApplyContestatie(6, 6.5, 0.5))
+	assert.Equal(t, 5.2, ApplyContestatie(6, 5.2, 0.5))
+	assert.Equal(t, 6.1, ApplyContestatie(6, 6.1, 0))
+}
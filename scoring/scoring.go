@@ -0,0 +1,111 @@
+package scoring
+
+import "sort"
+
+// ExercisePoints returns the points awarded for the chosen variant, capped at the exercise maximum and
+// multiplied by the exercise weight
+func ExercisePoints(scheme ExerciseScheme, varianta string) float64 {
+	points := scheme.Puncte[varianta]
+	if scheme.Maxim != nil && points > *scheme.Maxim {
+		points = *scheme.Maxim
+	}
+	return points * weight(scheme)
+}
+
+// MaxPoints returns the most points an exercise can contribute to the subject total
+func MaxPoints(scheme ExerciseScheme) float64 {
+	if scheme.Maxim != nil {
+		return *scheme.Maxim * weight(scheme)
+	}
+
+	max := 0.0
+	for _, points := range scheme.Puncte {
+		if points > max {
+			max = points
+		}
+	}
+	return max * weight(scheme)
+}
+
+// ComputeSubjectTotals sums the points of the chosen variants, indexed by exercise number, for every subject
+// found in the schemes. The result is sorted by subject
+func ComputeSubjectTotals(schemes []ExerciseScheme, variante map[string]string) []*SubjectTotal {
+	totalsByMaterie := make(map[string]*SubjectTotal)
+	for _, scheme := range schemes {
+		total, ok := totalsByMaterie[scheme.Materie]
+		if !ok {
+			total = &SubjectTotal{Materie: scheme.Materie}
+			totalsByMaterie[scheme.Materie] = total
+		}
+
+		total.Total++
+		total.PunctajMaxim += MaxPoints(scheme)
+		varianta, graded := variante[scheme.Numar]
+		if !graded {
+			continue
+		}
+		total.Notate++
+		total.Punctaj += ExercisePoints(scheme, varianta)
+	}
+
+	totals := make([]*SubjectTotal, 0, len(totalsByMaterie))
+	for _, total := range totalsByMaterie {
+		totals = append(totals, total)
+	}
+	sort.Slice(totals, func(i, j int) bool {
+		return totals[i].Materie < totals[j].Materie
+	})
+	return totals
+}
+
+func weight(scheme ExerciseScheme) float64 {
+	if scheme.Pondere <= 0 {
+		return 1
+	}
+	return scheme.Pondere
+}
+
+// IsComplete returns true if every exercise of the subject has been graded
+func IsComplete(total *SubjectTotal) bool {
+	return total != nil && total.Total > 0 && total.Notate == total.Total
+}
+
+// CombineCorrections returns the total that counts for a subject out of the totals of the two correctors and
+// of the arbiter, any of which may be missing. A complete arbitration is final, otherwise two complete
+// corrections are averaged, otherwise the first correction is used
+func CombineCorrections(corector1 *SubjectTotal, corector2 *SubjectTotal, arbitru *SubjectTotal) *SubjectTotal {
+	if IsComplete(arbitru) {
+		return arbitru
+	}
+	if IsComplete(corector1) && IsComplete(corector2) {
+		return &SubjectTotal{
+			Materie:      corector1.Materie,
+			Punctaj:      (corector1.Punctaj + corector2.Punctaj) / 2,
+			PunctajMaxim: corector1.PunctajMaxim,
+			Notate:       corector1.Notate,
+			Total:        corector1.Total,
+		}
+	}
+	if corector1 != nil {
+		return corector1
+	}
+	return corector2
+}
+
+// CorrectionWeights returns how much the totals of the two correctors and of the arbiter count towards the
+// final total of a subject, following the rules of CombineCorrections
+func CorrectionWeights(corector1 *SubjectTotal, corector2 *SubjectTotal, arbitru *SubjectTotal) (float64, float64, float64) {
+	if IsComplete(arbitru) {
+		return 0, 0, 1
+	}
+	if IsComplete(corector1) && IsComplete(corector2) {
+		return 0.5, 0.5, 0
+	}
+	if corector1 != nil {
+		return 1, 0, 0
+	}
+	if corector2 != nil {
+		return 0, 1, 0
+	}
+	return 0, 0, 0
+}
@@ -0,0 +1,90 @@
+package scoring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createSchemes() []ExerciseScheme {
+	maxim := 4.0
+	return []ExerciseScheme{
+		{Numar: "1", Materie: "matematica", Puncte: map[string]float64{"A": 0, "B": 3, "C": 5}},
+		{Numar: "2", Materie: "matematica", Puncte: map[string]float64{"A": 0, "B": 5}, Maxim: &maxim},
+		{Numar: "3", Materie: "matematica", Puncte: map[string]float64{"A": 1, "B": 2}, Pondere: 2},
+		{Numar: "4", Materie: "biologie", Puncte: map[string]float64{"A": 0, "B": 10}},
+	}
+}
+
+func TestExercisePoints(t *testing.T) {
+	t.Parallel()
+
+	schemes := createSchemes()
+	assert.Equal(t, 3.0, ExercisePoints(schemes[0], "B"))
+	assert.Equal(t, 0.0, ExercisePoints(schemes[0], "unknown"))
+	assert.Equal(t, 4.0, ExercisePoints(schemes[1], "B"))
+	assert.Equal(t, 4.0, ExercisePoints(schemes[2], "B"))
+}
+
+func TestMaxPoints(t *testing.T) {
+	t.Parallel()
+
+	schemes := createSchemes()
+	assert.Equal(t, 5.0, MaxPoints(schemes[0]))
+	assert.Equal(t, 4.0, MaxPoints(schemes[1]))
+	assert.Equal(t, 4.0, MaxPoints(schemes[2]))
+}
+
+func TestComputeSubjectTotals(t *testing.T) {
+	t.Parallel()
+
+	totals := ComputeSubjectTotals(createSchemes(), map[string]string{"1": "C", "3": "A", "4": "X"})
+	require.Equal(t, 2, len(totals))
+
+	assert.Equal(t, "biologie", totals[0].Materie)
+	assert.Equal(t, 0.0, totals[0].Punctaj)
+	assert.Equal(t, 1, totals[0].Notate)
+	assert.Equal(t, 10.0, totals[0].PunctajMaxim)
+
+	assert.Equal(t, "matematica", totals[1].Materie)
+	assert.Equal(t, 7.0, totals[1].Punctaj)
+	assert.Equal(t, 13.0, totals[1].PunctajMaxim)
+	assert.Equal(t, 2, totals[1].Notate)
+	assert.Equal(t, 3, totals[1].Total)
+}
+
+func TestCombineCorrections(t *testing.T) {
+	t.Parallel()
+
+	complete1 := &SubjectTotal{Materie: "matematica", Punctaj: 6, PunctajMaxim: 10, Notate: 2, Total: 2}
+	complete2 := &SubjectTotal{Materie: "matematica", Punctaj: 8, PunctajMaxim: 10, Notate: 2, Total: 2}
+	partial := &SubjectTotal{Materie: "matematica", Punctaj: 3, PunctajMaxim: 10, Notate: 1, Total: 2}
+	arbitru := &SubjectTotal{Materie: "matematica", Punctaj: 9, PunctajMaxim: 10, Notate: 2, Total: 2}
+
+	assert.Equal(t, complete1, CombineCorrections(complete1, nil, nil))
+	assert.Equal(t, complete1, CombineCorrections(complete1, partial, nil))
+	assert.Equal(t, 7.0, CombineCorrections(complete1, complete2, nil).Punctaj)
+	assert.Equal(t, 7.0, CombineCorrections(complete1, complete2, partial).Punctaj)
+	assert.Equal(t, arbitru, CombineCorrections(complete1, complete2, arbitru))
+	assert.Equal(t, partial, CombineCorrections(nil, partial, nil))
+	assert.False(t, IsComplete(&SubjectTotal{}))
+}
+
+func TestCorrectionWeights(t *testing.T) {
+	t.Parallel()
+
+	complete := &SubjectTotal{Materie: "matematica", Punctaj: 6, PunctajMaxim: 10, Notate: 2, Total: 2}
+	partial := &SubjectTotal{Materie: "matematica", Punctaj: 3, PunctajMaxim: 10, Notate: 1, Total: 2}
+
+	w1, w2, w3 := CorrectionWeights(complete, complete, complete)
+	assert.Equal(t, []float64{0, 0, 1}, []float64{w1, w2, w3})
+	w1, w2, w3 = CorrectionWeights(complete, complete, partial)
+	assert.Equal(t, []float64{0.5, 0.5, 0}, []float64{w1, w2, w3})
+	w1, w2, w3 = CorrectionWeights(partial, complete, nil)
+	assert.Equal(t, []float64{1, 0, 0}, []float64{w1, w2, w3})
+	w1, w2, w3 = CorrectionWeights(nil, partial, nil)
+	assert.Equal(t, []float64{0, 1, 0}, []float64{w1, w2, w3})
+	w1, w2, w3 = CorrectionWeights(nil, nil, nil)
+	assert.Equal(t, []float64{0, 0, 0}, []float64{w1, w2, w3})
+}
@@ -0,0 +1,73 @@
+package scoring
+
+import "fmt"
+
+// ValidateSections checks that section codes are unique and not empty, that every parent exists and that
+// the sections form a tree
+func ValidateSections(sections []Section) error {
+	parents := make(map[string]string, len(sections))
+	for _, section := range sections {
+		if len(section.Cod) == 0 {
+			return fmt.Errorf("%w: cod gol", ErrInvalidSection)
+		}
+		_, exists := parents[section.Cod]
+		if exists {
+			return fmt.Errorf("%w: %s apare de doua ori", ErrInvalidSection, section.Cod)
+		}
+		parents[section.Cod] = section.Parinte
+	}
+
+	for _, section := range sections {
+		visited := make(map[string]bool)
+		for cod := section.Cod; len(cod) > 0; cod = parents[cod] {
+			if visited[cod] {
+				return fmt.Errorf("%w: %s este propriul stramos", ErrInvalidSection, section.Cod)
+			}
+			visited[cod] = true
+
+			_, exists := parents[cod]
+			if !exists {
+				return fmt.Errorf("%w: parintele %s nu exista", ErrInvalidSection, cod)
+			}
+		}
+	}
+	return nil
+}
+
+// ComputeSectionTotals rolls up the points obtained on each exercise, indexed by exercise number, and the
+// maximum of each exercise into the sections holding them. The sections must be valid. The top level
+// sections are returned in the order they were provided, each one holding its subsections
+func ComputeSectionTotals(sections []Section, schemes []ExerciseScheme, puncte map[string]float64) []*SectionTotal {
+	totals := make(map[string]*SectionTotal, len(sections))
+	parents := make(map[string]string, len(sections))
+	for _, section := range sections {
+		totals[section.Cod] = &SectionTotal{
+			Cod:   section.Cod,
+			Titlu: section.Titlu,
+		}
+		parents[section.Cod] = section.Parinte
+	}
+
+	for _, scheme := range schemes {
+		for cod := scheme.Sectiune; len(cod) > 0; cod = parents[cod] {
+			total, ok := totals[cod]
+			if !ok {
+				break
+			}
+			total.Punctaj += puncte[scheme.Numar]
+			total.PunctajMaxim += MaxPoints(scheme)
+		}
+	}
+
+	roots := make([]*SectionTotal, 0)
+	for _, section := range sections {
+		total := totals[section.Cod]
+		parent, ok := totals[section.Parinte]
+		if !ok {
+			roots = append(roots, total)
+			continue
+		}
+		parent.Subsectiuni = append(parent.Subsectiuni, total)
+	}
+	return roots
+}
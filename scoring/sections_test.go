@@ -0,0 +1,56 @@
+package scoring
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestSections() []Section {
+	return []Section{
+		{Cod: "I", Titlu: "Subiectul I"},
+		{Cod: "II", Titlu: "Subiectul II"},
+		{Cod: "II.1", Titlu: "1", Parinte: "II"},
+	}
+}
+
+func TestValidateSections(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, ValidateSections(createTestSections()))
+	assert.Nil(t, ValidateSections(nil))
+
+	sections := append(createTestSections(), Section{Cod: "I"})
+	assert.True(t, errors.Is(ValidateSections(sections), ErrInvalidSection))
+
+	sections = append(createTestSections(), Section{Cod: "III.1", Parinte: "III"})
+	assert.True(t, errors.Is(ValidateSections(sections), ErrInvalidSection))
+
+	sections = []Section{{Cod: "a", Parinte: "b"}, {Cod: "b", Parinte: "a"}}
+	assert.True(t, errors.Is(ValidateSections(sections), ErrInvalidSection))
+
+	sections = []Section{{Cod: ""}}
+	assert.True(t, errors.Is(ValidateSections(sections), ErrInvalidSection))
+}
+
+func TestComputeSectionTotals(t *testing.T) {
+	t.Parallel()
+
+	schemes := []ExerciseScheme{
+		{Numar: "1", Sectiune: "I", Puncte: map[string]float64{"A": 5}},
+		{Numar: "II.1.a", Sectiune: "II.1", Puncte: map[string]float64{"A": 2, "B": 3}},
+		{Numar: "II.1.b", Sectiune: "II.1", Puncte: map[string]float64{"A": 4}},
+		{Numar: "liber", Puncte: map[string]float64{"A": 1}},
+	}
+	puncte := map[string]float64{"1": 5, "II.1.a": 2, "liber": 1}
+
+	roots := ComputeSectionTotals(createTestSections(), schemes, puncte)
+	require.Equal(t, 2, len(roots))
+	assert.Equal(t, &SectionTotal{Cod: "I", Titlu: "Subiectul I", Punctaj: 5, PunctajMaxim: 5}, roots[0])
+	assert.Equal(t, 2.0, roots[1].Punctaj)
+	assert.Equal(t, 7.0, roots[1].PunctajMaxim)
+	require.Equal(t, 1, len(roots[1].Subsectiuni))
+	assert.Equal(t, 7.0, roots[1].Subsectiuni[0].PunctajMaxim)
+}
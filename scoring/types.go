@@ -0,0 +1,49 @@
+package scoring
+
+// ExerciseScheme describes how many points each variant of an exercise is worth
+type ExerciseScheme struct {
+	Numar      string
+	Materie    string
+	Sectiune   string
+	Competente []string
+	Puncte     map[string]float64
+	Maxim      *float64
+	Pondere    float64
+}
+
+// SubjectTotal holds the points obtained by a student on one subject of an exam
+type SubjectTotal struct {
+	Materie      string  `json:"materie"`
+	Punctaj      float64 `json:"punctaj"`
+	PunctajMaxim float64 `json:"punctaj_maxim"`
+	Notate       int     `json:"exercitii_notate"`
+	Total        int     `json:"exercitii_total"`
+}
+
+// Section is a node of the tree of an exam, such as "Subiectul I" or item "1". Sections and exercises point
+// to their parent by code, an empty parent marking a top level node
+type Section struct {
+	Cod     string
+	Titlu   string
+	Parinte string
+}
+
+// SectionTotal holds the points of the exercises found under a section, including its subsections
+type SectionTotal struct {
+	Cod          string          `json:"cod"`
+	Titlu        string          `json:"titlu"`
+	Punctaj      float64         `json:"punctaj"`
+	PunctajMaxim float64         `json:"punctaj_maxim"`
+	Subsectiuni  []*SectionTotal `json:"subsectiuni,omitempty"`
+}
+
+// CompetencyTotal holds the points obtained by a student on the graded exercises that assess one competency
+// of a subject, and the share of the maximum they represent
+type CompetencyTotal struct {
+	Materie      string   `json:"materie"`
+	Cod          string   `json:"cod"`
+	Exercitii    []string `json:"exercitii"`
+	Punctaj      float64  `json:"punctaj"`
+	PunctajMaxim float64  `json:"punctaj_maxim"`
+	Stapanire    float64  `json:"stapanire"`
+}
@@ -0,0 +1,143 @@
+package statistics
+
+import (
+	"math"
+	"sort"
+)
+
+// Mean returns the arithmetic mean of the values, or 0 if there are none
+func Mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sum := 0.0
+	for _, value := range values {
+		sum += value
+	}
+	return sum / float64(len(values))
+}
+
+// Median returns the middle value of the values, or the mean of the two middle values if their count is even
+func Median(values []float64) float64 {
+	return Percentile(values, 50)
+}
+
+// Percentile returns the value under which the provided percent of the values fall, interpolating linearly
+// between the closest ranks
+func Percentile(values []float64, percent float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := sortedCopy(values)
+	rank := percent / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	if lower < 0 {
+		return sorted[0]
+	}
+	if upper >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
+
+// Summarize returns the count, mean, median, extremes and percentile bands of the values
+func Summarize(values []float64) *Summary {
+	summary := &Summary{
+		Count:   len(values),
+		Media:   Round(Mean(values)),
+		Mediana: Round(Median(values)),
+		Benzi: PercentileBands{
+			P10: Round(Percentile(values, 10)),
+			P25: Round(Percentile(values, 25)),
+			P50: Round(Percentile(values, 50)),
+			P75: Round(Percentile(values, 75)),
+			P90: Round(Percentile(values, 90)),
+		},
+	}
+	if len(values) > 0 {
+		sorted := sortedCopy(values)
+		summary.Minim = sorted[0]
+		summary.Maxim = sorted[len(sorted)-1]
+	}
+	return summary
+}
+
+func sortedCopy(values []float64) []float64 {
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+	return sorted
+}
+
+// Round keeps two decimals, the precision used for grades and indices
+func Round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// FacilityIndex returns the mean of the points obtained on an item divided by its maximum. Values close to 1
+// mark easy items
+func FacilityIndex(puncte []float64, maxim float64) float64 {
+	if maxim <= 0 {
+		return 0
+	}
+	return Mean(puncte) / maxim
+}
+
+// DiscriminationIndex compares the points obtained on an item by the strongest and the weakest students, ranked
+// by their totals. Each group holds the provided fraction of the students, usually 27%. The difference of the
+// group means is divided by the maximum of the item
+func DiscriminationIndex(puncte []float64, totals []float64, maxim float64, fraction float64) float64 {
+	if maxim <= 0 || len(puncte) != len(totals) || len(puncte) < 2 {
+		return 0
+	}
+
+	indices := make([]int, len(totals))
+	for i := range indices {
+		indices[i] = i
+	}
+	sort.SliceStable(indices, func(i, j int) bool {
+		return totals[indices[i]] < totals[indices[j]]
+	})
+
+	size := int(math.Ceil(fraction * float64(len(indices))))
+	if size < 1 {
+		size = 1
+	}
+	if size > len(indices)/2 {
+		size = len(indices) / 2
+	}
+
+	lower := make([]float64, 0, size)
+	upper := make([]float64, 0, size)
+	for i := 0; i < size; i++ {
+		lower = append(lower, puncte[indices[i]])
+		upper = append(upper, puncte[indices[len(indices)-1-i]])
+	}
+	return (Mean(upper) - Mean(lower)) / maxim
+}
+
+// Correlation returns the Pearson correlation of two series, or 0 if either of them is constant. Applied to
+// the points of an item and the totals of the students it gives the point-biserial correlation
+func Correlation(x []float64, y []float64) float64 {
+	if len(x) != len(y) || len(x) < 2 {
+		return 0
+	}
+
+	meanX := Mean(x)
+	meanY := Mean(y)
+	covariance, varianceX, varianceY := 0.0, 0.0, 0.0
+	for i := range x {
+		dx := x[i] - meanX
+		dy := y[i] - meanY
+		covariance += dx * dy
+		varianceX += dx * dx
+		varianceY += dy * dy
+	}
+	if varianceX == 0 || varianceY == 0 {
+		return 0
+	}
+	return covariance / math.Sqrt(varianceX*varianceY)
+}
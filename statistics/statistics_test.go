@@ -0,0 +1,76 @@
+package statistics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMean(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 0.0, Mean(nil))
+	assert.Equal(t, 2.5, Mean([]float64{1, 2, 3, 4}))
+}
+
+func TestMedian(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 0.0, Median(nil))
+	assert.Equal(t, 3.0, Median([]float64{5, 1, 3}))
+	assert.Equal(t, 2.5, Median([]float64{4, 1, 3, 2}))
+}
+
+func TestPercentile(t *testing.T) {
+	t.Parallel()
+
+	values := []float64{10, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	assert.Equal(t, 1.0, Percentile(values, 0))
+	assert.Equal(t, 10.0, Percentile(values, 100))
+	assert.Equal(t, 3.25, Percentile(values, 25))
+	assert.Equal(t, 7.0, Percentile([]float64{7}, 90))
+}
+
+func TestSummarize(t *testing.T) {
+	t.Parallel()
+
+	summary := Summarize([]float64{6, 9, 7.5})
+	assert.Equal(t, 3, summary.Count)
+	assert.Equal(t, 7.5, summary.Media)
+	assert.Equal(t, 7.5, summary.Mediana)
+	assert.Equal(t, 6.0, summary.Minim)
+	assert.Equal(t, 9.0, summary.Maxim)
+	assert.Equal(t, 6.3, summary.Benzi.P10)
+	assert.Equal(t, 8.7, summary.Benzi.P90)
+
+	empty := Summarize(nil)
+	assert.Equal(t, 0, empty.Count)
+	assert.Equal(t, 0.0, empty.Maxim)
+}
+
+func TestFacilityIndex(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 0.0, FacilityIndex([]float64{1, 2}, 0))
+	assert.Equal(t, 0.5, FacilityIndex([]float64{0, 2, 1, 1}, 2))
+}
+
+func TestDiscriminationIndex(t *testing.T) {
+	t.Parallel()
+
+	puncte := []float64{0, 0, 1, 2, 2, 2}
+	totals := []float64{1, 2, 5, 9, 7, 8}
+	assert.Equal(t, 1.0, DiscriminationIndex(puncte, totals, 2, 0.27))
+	assert.Equal(t, -1.0, DiscriminationIndex([]float64{2, 0}, []float64{1, 9}, 2, 0.27))
+	assert.Equal(t, 0.0, DiscriminationIndex([]float64{2}, []float64{1}, 2, 0.27))
+	assert.Equal(t, 0.0, DiscriminationIndex(puncte, totals[:2], 2, 0.27))
+}
+
+func TestCorrelation(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 1.0, Round(Correlation([]float64{1, 2, 3}, []float64{2, 4, 6})))
+	assert.Equal(t, -1.0, Round(Correlation([]float64{1, 2, 3}, []float64{6, 4, 2})))
+	assert.Equal(t, 0.0, Correlation([]float64{1, 1, 1}, []float64{6, 4, 2}))
+	assert.Equal(t, 0.0, Correlation([]float64{1}, []float64{6}))
+}
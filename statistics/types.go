@@ -0,0 +1,20 @@
+package statistics
+
+// Summary describes the distribution of a set of values
+type Summary struct {
+	Count   int             `json:"count"`
+	Media   float64         `json:"media"`
+	Mediana float64         `json:"mediana"`
+	Minim   float64         `json:"minim"`
+	Maxim   float64         `json:"maxim"`
+	Benzi   PercentileBands `json:"percentile"`
+}
+
+// PercentileBands holds the values under which 10, 25, 50, 75 and 90 percent of the values fall
+type PercentileBands struct {
+	P10 float64 `json:"p10"`
+	P25 float64 `json:"p25"`
+	P50 float64 `json:"p50"`
+	P75 float64 `json:"p75"`
+	P90 float64 `json:"p90"`
+}
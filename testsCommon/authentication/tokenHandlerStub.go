@@ -0,0 +1,70 @@
+package authentication
+
+import (
+	"time"
+
+	"github.com/dragos-rebegea/evaluare-tool/authentication"
+)
+
+// TokenHandlerStub -
+type TokenHandlerStub struct {
+	GenerateJWTCalled          func(email string, username string, sesiune string, roluri []string) (string, error)
+	ValidateTokenCalled        func(signedToken string) (*authentication.JWTClaim, error)
+	GenerateStudentJWTCalled   func(student uint, email string, username string, sesiune string) (string, error)
+	RefreshTokenLifetimeCalled func() time.Duration
+	GenerateInvitationCalled   func(id string, email string, rol string, expiresAt time.Time) (string, error)
+	ValidateInvitationCalled   func(signedInvitation string) (*authentication.InvitationClaim, error)
+}
+
+// GenerateJWT -
+func (stub *TokenHandlerStub) GenerateJWT(email string, username string, sesiune string, roluri []string) (string, error) {
+	if stub.GenerateJWTCalled != nil {
+		return stub.GenerateJWTCalled(email, username, sesiune, roluri)
+	}
+	return "", nil
+}
+
+// ValidateToken -
+func (stub *TokenHandlerStub) ValidateToken(signedToken string) (*authentication.JWTClaim, error) {
+	if stub.ValidateTokenCalled != nil {
+		return stub.ValidateTokenCalled(signedToken)
+	}
+	return &authentication.JWTClaim{}, nil
+}
+
+// RefreshTokenLifetime -
+func (stub *TokenHandlerStub) RefreshTokenLifetime() time.Duration {
+	if stub.RefreshTokenLifetimeCalled != nil {
+		return stub.RefreshTokenLifetimeCalled()
+	}
+	return time.Hour
+}
+
+// GenerateStudentJWT -
+func (stub *TokenHandlerStub) GenerateStudentJWT(student uint, email string, username string, sesiune string) (string, error) {
+	if stub.GenerateStudentJWTCalled != nil {
+		return stub.GenerateStudentJWTCalled(student, email, username, sesiune)
+	}
+	return "", nil
+}
+
+// GenerateInvitation -
+func (stub *TokenHandlerStub) GenerateInvitation(id string, email string, rol string, expiresAt time.Time) (string, error) {
+	if stub.GenerateInvitationCalled != nil {
+		return stub.GenerateInvitationCalled(id, email, rol, expiresAt)
+	}
+	return "", nil
+}
+
+// ValidateInvitation -
+func (stub *TokenHandlerStub) ValidateInvitation(signedInvitation string) (*authentication.InvitationClaim, error) {
+	if stub.ValidateInvitationCalled != nil {
+		return stub.ValidateInvitationCalled(signedInvitation)
+	}
+	return &authentication.InvitationClaim{}, nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (stub *TokenHandlerStub) IsInterfaceNil() bool {
+	return stub == nil
+}
@@ -0,0 +1,566 @@
+package database
+
+import (
+	"time"
+
+	"github.com/dragos-rebegea/evaluare-tool/authentication"
+	"github.com/dragos-rebegea/evaluare-tool/core"
+)
+
+// DatabaseHandlerStub -
+type DatabaseHandlerStub struct {
+	GetStudentByIDCalled                      func(id uint) (*authentication.Student, error)
+	GetExamByNameCalled                       func(name string) (*authentication.Exam, error)
+	GetExercitiuStiintaByExamAndNumberCalled  func(exam string, number string) (*authentication.Exercitiu, error)
+	GetExercitiuLimbaByExamAndNumberCalled    func(exam string, number string) (*authentication.Exercitiu, error)
+	GetProfesorByEmailCalled                  func(email string) (*authentication.Profesor, error)
+	GetStudentsByClassCalled                  func(clasa string, exam string) ([]*core.StudentStatus, error)
+	GetAllClassesCalled                       func(profEmail string) ([]string, error)
+	GetClassByIDCalled                        func(studentId uint) (*authentication.Clasa, error)
+	SetAbsentCalled                           func(email string, status *core.AbsentStatus) error
+	CreateProfesorCalled                      func(profesor *authentication.Profesor, roluri ...string) error
+	CreateClassCalled                         func(class *core.Class) (*core.CreateClassResult, error)
+	CreateStudentCalled                       func(student *authentication.Student) error
+	DeleteStudentCalled                       func(id *uint) error
+	CreateExamCalled                          func(exam *core.Exam) error
+	UpdateRubricaCalled                       func(update *core.RubricaUpdate) error
+	CloneExamCalled                           func(request *core.CloneExamRequest) (*core.Exam, error)
+	MoveExamStudentsCalled                    func(request *core.MoveStudentsRequest) (*core.MoveStudentsResult, error)
+	SetExamStatusCalled                       func(email string, request *core.ExamStatusRequest) (*authentication.TranzitieExam, error)
+	GetExamTranzitiiCalled                    func(exam string) ([]authentication.TranzitieExam, error)
+	AddCalificativCalled                      func(profEmail string, calificativ *core.Calificativ) error
+	UpdateCalificativCalled                   func(profEmail string, calificativ *core.Calificativ) error
+	AddCalificativeCalled                     func(profEmail string, batch *core.CalificativBatch) (*core.CalificativBatchResult, error)
+	GetCalificativByStudentAndExercitiuCalled func(id uint, exercitiu uint) (*core.Calificativ, error)
+	GetCalificativeCalled                     func(email string, student string) ([]*core.Calificativ, error)
+	SetComentariuCalled                       func(email string, request *core.ComentariuRequest) (*core.Comentariu, error)
+	GetExercitiiForProfesorAndStudentCalled   func(email string, studentId string) ([]*core.Exercitiu, error)
+	GetStudentScoreCalled                     func(email string, studentId string) (*core.StudentScore, error)
+	GetStudentGradesCalled                    func(email string, studentId string) (*core.StudentGrades, error)
+	AssignEvaluatorCalled                     func(assignment *core.EvaluatorAssignment) error
+	AssignArbiterCalled                       func(assignment *core.ArbiterAssignment) error
+	GetArbitrajeCalled                        func() ([]authentication.Arbitraj, error)
+	FileContestatieCalled                     func(email string, request *core.ContestatieRequest) (*authentication.Contestatie, error)
+	AssignContestatieCalled                   func(assignment *core.ContestatieAssignment) error
+	ResolveContestatieCalled                  func(request *core.ContestatieRequest) (*authentication.Contestatie, error)
+	GetContestatiiCalled                      func(status string) ([]authentication.Contestatie, error)
+	GetClassProgressCalled                    func(clasa string, exam string) (*core.GradingProgress, error)
+	GetProfesorProgressCalled                 func(email string) ([]*core.ProfesorProgress, error)
+	GetClassStatisticsCalled                  func(email string, clasa string, exam string) (*core.ExamStatistics, error)
+	GetExamStatisticsCalled                   func(exam string) (*core.ExamStatistics, error)
+	GetItemAnalysisCalled                     func(exam string) (*core.ItemAnalysis, error)
+	SetCompetenteCalled                       func(competente []*core.Competenta) error
+	GetCompetenteCalled                       func(materie string) ([]*core.Competenta, error)
+	GetCompetencyReportCalled                 func(email string, clasa string, exam string) (*core.CompetencyReport, error)
+	GetStudentEvolutionCalled                 func(email string, studentId string) (*core.StudentEvolution, error)
+	GetClassEvolutionCalled                   func(email string, clasa string) (*core.ClassEvolution, error)
+	CreateSesiuneCalled                       func(sesiune *authentication.Sesiune) error
+	RefreshSesiuneCalled                      func(refreshHash string, newHash string, expiresAt time.Time) (*authentication.Sesiune, error)
+	RevokeSesiuneCalled                       func(refreshHash string) error
+	RevokeSesiuniProfesorCalled               func(email string) (int64, error)
+	IsSesiuneActivaCalled                     func(id string) (bool, error)
+	GetRoluriCalled                           func(email string) ([]authentication.RolUtilizator, error)
+	AssignRolCalled                           func(request *core.RolRequest) error
+	RevokeRolCalled                           func(request *core.RolRequest) error
+	HasAdminCalled                            func() (bool, error)
+	CreateFirstAdminCalled                    func(profesor *authentication.Profesor) error
+	CreateInvitatieCalled                     func(invitatie *authentication.Invitatie) error
+	RedeemInvitatieCalled                     func(id string, profesor *authentication.Profesor) error
+	GetStudentByUsernameCalled                func(username string) (*authentication.Student, error)
+	GetStudentExameCalled                     func(studentId uint) ([]*core.StudentExam, error)
+	GetStudentRezultateCalled                 func(studentId uint, exam string) (*core.StudentRezultate, error)
+	FileStudentContestatieCalled              func(studentId uint, request *core.StudentContestatieRequest) (*authentication.Contestatie, error)
+}
+
+// GetStudentByID -
+func (stub *DatabaseHandlerStub) GetStudentByID(id uint) (*authentication.Student, error) {
+	if stub.GetStudentByIDCalled != nil {
+		return stub.GetStudentByIDCalled(id)
+	}
+	return nil, nil
+}
+
+// GetExamByName -
+func (stub *DatabaseHandlerStub) GetExamByName(name string) (*authentication.Exam, error) {
+	if stub.GetExamByNameCalled != nil {
+		return stub.GetExamByNameCalled(name)
+	}
+	return nil, nil
+}
+
+// GetExercitiuStiintaByExamAndNumber -
+func (stub *DatabaseHandlerStub) GetExercitiuStiintaByExamAndNumber(exam string, number string) (*authentication.Exercitiu, error) {
+	if stub.GetExercitiuStiintaByExamAndNumberCalled != nil {
+		return stub.GetExercitiuStiintaByExamAndNumberCalled(exam, number)
+	}
+	return nil, nil
+}
+
+// GetExercitiuLimbaByExamAndNumber -
+func (stub *DatabaseHandlerStub) GetExercitiuLimbaByExamAndNumber(exam string, number string) (*authentication.Exercitiu, error) {
+	if stub.GetExercitiuLimbaByExamAndNumberCalled != nil {
+		return stub.GetExercitiuLimbaByExamAndNumberCalled(exam, number)
+	}
+	return nil, nil
+}
+
+// GetProfesorByEmail -
+func (stub *DatabaseHandlerStub) GetProfesorByEmail(email string) (*authentication.Profesor, error) {
+	if stub.GetProfesorByEmailCalled != nil {
+		return stub.GetProfesorByEmailCalled(email)
+	}
+	return nil, nil
+}
+
+// GetStudentsByClass -
+func (stub *DatabaseHandlerStub) GetStudentsByClass(clasa string, exam string) ([]*core.StudentStatus, error) {
+	if stub.GetStudentsByClassCalled != nil {
+		return stub.GetStudentsByClassCalled(clasa, exam)
+	}
+	return nil, nil
+}
+
+// GetAllClasses -
+func (stub *DatabaseHandlerStub) GetAllClasses(profEmail string) ([]string, error) {
+	if stub.GetAllClassesCalled != nil {
+		return stub.GetAllClassesCalled(profEmail)
+	}
+	return nil, nil
+}
+
+// GetClassByID -
+func (stub *DatabaseHandlerStub) GetClassByID(studentId uint) (*authentication.Clasa, error) {
+	if stub.GetClassByIDCalled != nil {
+		return stub.GetClassByIDCalled(studentId)
+	}
+	return nil, nil
+}
+
+// SetAbsent -
+func (stub *DatabaseHandlerStub) SetAbsent(email string, status *core.AbsentStatus) error {
+	if stub.SetAbsentCalled != nil {
+		return stub.SetAbsentCalled(email, status)
+	}
+	return nil
+}
+
+// CreateProfesor -
+func (stub *DatabaseHandlerStub) CreateProfesor(profesor *authentication.Profesor, roluri ...string) error {
+	if stub.CreateProfesorCalled != nil {
+		return stub.CreateProfesorCalled(profesor, roluri...)
+	}
+	return nil
+}
+
+// CreateClass -
+func (stub *DatabaseHandlerStub) CreateClass(class *core.Class) (*core.CreateClassResult, error) {
+	if stub.CreateClassCalled != nil {
+		return stub.CreateClassCalled(class)
+	}
+	return nil, nil
+}
+
+// CreateStudent -
+func (stub *DatabaseHandlerStub) CreateStudent(student *authentication.Student) error {
+	if stub.CreateStudentCalled != nil {
+		return stub.CreateStudentCalled(student)
+	}
+	return nil
+}
+
+// DeleteStudent -
+func (stub *DatabaseHandlerStub) DeleteStudent(id *uint) error {
+	if stub.DeleteStudentCalled != nil {
+		return stub.DeleteStudentCalled(id)
+	}
+	return nil
+}
+
+// CreateExam -
+func (stub *DatabaseHandlerStub) CreateExam(exam *core.Exam) error {
+	if stub.CreateExamCalled != nil {
+		return stub.CreateExamCalled(exam)
+	}
+	return nil
+}
+
+// UpdateRubrica -
+func (stub *DatabaseHandlerStub) UpdateRubrica(update *core.RubricaUpdate) error {
+	if stub.UpdateRubricaCalled != nil {
+		return stub.UpdateRubricaCalled(update)
+	}
+	return nil
+}
+
+// CloneExam -
+func (stub *DatabaseHandlerStub) CloneExam(request *core.CloneExamRequest) (*core.Exam, error) {
+	if stub.CloneExamCalled != nil {
+		return stub.CloneExamCalled(request)
+	}
+	return nil, nil
+}
+
+// MoveExamStudents -
+func (stub *DatabaseHandlerStub) MoveExamStudents(request *core.MoveStudentsRequest) (*core.MoveStudentsResult, error) {
+	if stub.MoveExamStudentsCalled != nil {
+		return stub.MoveExamStudentsCalled(request)
+	}
+	return nil, nil
+}
+
+// SetExamStatus -
+func (stub *DatabaseHandlerStub) SetExamStatus(email string, request *core.ExamStatusRequest) (*authentication.TranzitieExam, error) {
+	if stub.SetExamStatusCalled != nil {
+		return stub.SetExamStatusCalled(email, request)
+	}
+	return nil, nil
+}
+
+// GetExamTranzitii -
+func (stub *DatabaseHandlerStub) GetExamTranzitii(exam string) ([]authentication.TranzitieExam, error) {
+	if stub.GetExamTranzitiiCalled != nil {
+		return stub.GetExamTranzitiiCalled(exam)
+	}
+	return nil, nil
+}
+
+// AddCalificativ -
+func (stub *DatabaseHandlerStub) AddCalificativ(profEmail string, calificativ *core.Calificativ) error {
+	if stub.AddCalificativCalled != nil {
+		return stub.AddCalificativCalled(profEmail, calificativ)
+	}
+	return nil
+}
+
+// UpdateCalificativ -
+func (stub *DatabaseHandlerStub) UpdateCalificativ(profEmail string, calificativ *core.Calificativ) error {
+	if stub.UpdateCalificativCalled != nil {
+		return stub.UpdateCalificativCalled(profEmail, calificativ)
+	}
+	return nil
+}
+
+// AddCalificative -
+func (stub *DatabaseHandlerStub) AddCalificative(profEmail string, batch *core.CalificativBatch) (*core.CalificativBatchResult, error) {
+	if stub.AddCalificativeCalled != nil {
+		return stub.AddCalificativeCalled(profEmail, batch)
+	}
+	return nil, nil
+}
+
+// GetCalificativByStudentAndExercitiu -
+func (stub *DatabaseHandlerStub) GetCalificativByStudentAndExercitiu(id uint, exercitiu uint) (*core.Calificativ, error) {
+	if stub.GetCalificativByStudentAndExercitiuCalled != nil {
+		return stub.GetCalificativByStudentAndExercitiuCalled(id, exercitiu)
+	}
+	return nil, nil
+}
+
+// GetCalificative -
+func (stub *DatabaseHandlerStub) GetCalificative(email string, student string) ([]*core.Calificativ, error) {
+	if stub.GetCalificativeCalled != nil {
+		return stub.GetCalificativeCalled(email, student)
+	}
+	return nil, nil
+}
+
+// SetComentariu -
+func (stub *DatabaseHandlerStub) SetComentariu(email string, request *core.ComentariuRequest) (*core.Comentariu, error) {
+	if stub.SetComentariuCalled != nil {
+		return stub.SetComentariuCalled(email, request)
+	}
+	return nil, nil
+}
+
+// GetExercitiiForProfesorAndStudent -
+func (stub *DatabaseHandlerStub) GetExercitiiForProfesorAndStudent(email string, studentId string) ([]*core.Exercitiu, error) {
+	if stub.GetExercitiiForProfesorAndStudentCalled != nil {
+		return stub.GetExercitiiForProfesorAndStudentCalled(email, studentId)
+	}
+	return nil, nil
+}
+
+// GetStudentScore -
+func (stub *DatabaseHandlerStub) GetStudentScore(email string, studentId string) (*core.StudentScore, error) {
+	if stub.GetStudentScoreCalled != nil {
+		return stub.GetStudentScoreCalled(email, studentId)
+	}
+	return nil, nil
+}
+
+// GetStudentGrades -
+func (stub *DatabaseHandlerStub) GetStudentGrades(email string, studentId string) (*core.StudentGrades, error) {
+	if stub.GetStudentGradesCalled != nil {
+		return stub.GetStudentGradesCalled(email, studentId)
+	}
+	return nil, nil
+}
+
+// AssignEvaluator -
+func (stub *DatabaseHandlerStub) AssignEvaluator(assignment *core.EvaluatorAssignment) error {
+	if stub.AssignEvaluatorCalled != nil {
+		return stub.AssignEvaluatorCalled(assignment)
+	}
+	return nil
+}
+
+// AssignArbiter -
+func (stub *DatabaseHandlerStub) AssignArbiter(assignment *core.ArbiterAssignment) error {
+	if stub.AssignArbiterCalled != nil {
+		return stub.AssignArbiterCalled(assignment)
+	}
+	return nil
+}
+
+// GetArbitraje -
+func (stub *DatabaseHandlerStub) GetArbitraje() ([]authentication.Arbitraj, error) {
+	if stub.GetArbitrajeCalled != nil {
+		return stub.GetArbitrajeCalled()
+	}
+	return nil, nil
+}
+
+// FileContestatie -
+func (stub *DatabaseHandlerStub) FileContestatie(email string, request *core.ContestatieRequest) (*authentication.Contestatie, error) {
+	if stub.FileContestatieCalled != nil {
+		return stub.FileContestatieCalled(email, request)
+	}
+	return nil, nil
+}
+
+// AssignContestatie -
+func (stub *DatabaseHandlerStub) AssignContestatie(assignment *core.ContestatieAssignment) error {
+	if stub.AssignContestatieCalled != nil {
+		return stub.AssignContestatieCalled(assignment)
+	}
+	return nil
+}
+
+// ResolveContestatie -
+func (stub *DatabaseHandlerStub) ResolveContestatie(request *core.ContestatieRequest) (*authentication.Contestatie, error) {
+	if stub.ResolveContestatieCalled != nil {
+		return stub.ResolveContestatieCalled(request)
+	}
+	return nil, nil
+}
+
+// GetContestatii -
+func (stub *DatabaseHandlerStub) GetContestatii(status string) ([]authentication.Contestatie, error) {
+	if stub.GetContestatiiCalled != nil {
+		return stub.GetContestatiiCalled(status)
+	}
+	return nil, nil
+}
+
+// GetClassProgress -
+func (stub *DatabaseHandlerStub) GetClassProgress(clasa string, exam string) (*core.GradingProgress, error) {
+	if stub.GetClassProgressCalled != nil {
+		return stub.GetClassProgressCalled(clasa, exam)
+	}
+	return nil, nil
+}
+
+// GetProfesorProgress -
+func (stub *DatabaseHandlerStub) GetProfesorProgress(email string) ([]*core.ProfesorProgress, error) {
+	if stub.GetProfesorProgressCalled != nil {
+		return stub.GetProfesorProgressCalled(email)
+	}
+	return nil, nil
+}
+
+// GetClassStatistics -
+func (stub *DatabaseHandlerStub) GetClassStatistics(email string, clasa string, exam string) (*core.ExamStatistics, error) {
+	if stub.GetClassStatisticsCalled != nil {
+		return stub.GetClassStatisticsCalled(email, clasa, exam)
+	}
+	return nil, nil
+}
+
+// GetExamStatistics -
+func (stub *DatabaseHandlerStub) GetExamStatistics(exam string) (*core.ExamStatistics, error) {
+	if stub.GetExamStatisticsCalled != nil {
+		return stub.GetExamStatisticsCalled(exam)
+	}
+	return nil, nil
+}
+
+// GetItemAnalysis -
+func (stub *DatabaseHandlerStub) GetItemAnalysis(exam string) (*core.ItemAnalysis, error) {
+	if stub.GetItemAnalysisCalled != nil {
+		return stub.GetItemAnalysisCalled(exam)
+	}
+	return nil, nil
+}
+
+// SetCompetente -
+func (stub *DatabaseHandlerStub) SetCompetente(competente []*core.Competenta) error {
+	if stub.SetCompetenteCalled != nil {
+		return stub.SetCompetenteCalled(competente)
+	}
+	return nil
+}
+
+// GetCompetente -
+func (stub *DatabaseHandlerStub) GetCompetente(materie string) ([]*core.Competenta, error) {
+	if stub.GetCompetenteCalled != nil {
+		return stub.GetCompetenteCalled(materie)
+	}
+	return nil, nil
+}
+
+// GetCompetencyReport -
+func (stub *DatabaseHandlerStub) GetCompetencyReport(email string, clasa string, exam string) (*core.CompetencyReport, error) {
+	if stub.GetCompetencyReportCalled != nil {
+		return stub.GetCompetencyReportCalled(email, clasa, exam)
+	}
+	return nil, nil
+}
+
+// GetStudentEvolution -
+func (stub *DatabaseHandlerStub) GetStudentEvolution(email string, studentId string) (*core.StudentEvolution, error) {
+	if stub.GetStudentEvolutionCalled != nil {
+		return stub.GetStudentEvolutionCalled(email, studentId)
+	}
+	return nil, nil
+}
+
+// GetClassEvolution -
+func (stub *DatabaseHandlerStub) GetClassEvolution(email string, clasa string) (*core.ClassEvolution, error) {
+	if stub.GetClassEvolutionCalled != nil {
+		return stub.GetClassEvolutionCalled(email, clasa)
+	}
+	return nil, nil
+}
+
+// CreateSesiune -
+func (stub *DatabaseHandlerStub) CreateSesiune(sesiune *authentication.Sesiune) error {
+	if stub.CreateSesiuneCalled != nil {
+		return stub.CreateSesiuneCalled(sesiune)
+	}
+	return nil
+}
+
+// RefreshSesiune -
+func (stub *DatabaseHandlerStub) RefreshSesiune(refreshHash string, newHash string, expiresAt time.Time) (*authentication.Sesiune, error) {
+	if stub.RefreshSesiuneCalled != nil {
+		return stub.RefreshSesiuneCalled(refreshHash, newHash, expiresAt)
+	}
+	return nil, nil
+}
+
+// RevokeSesiune -
+func (stub *DatabaseHandlerStub) RevokeSesiune(refreshHash string) error {
+	if stub.RevokeSesiuneCalled != nil {
+		return stub.RevokeSesiuneCalled(refreshHash)
+	}
+	return nil
+}
+
+// RevokeSesiuniProfesor -
+func (stub *DatabaseHandlerStub) RevokeSesiuniProfesor(email string) (int64, error) {
+	if stub.RevokeSesiuniProfesorCalled != nil {
+		return stub.RevokeSesiuniProfesorCalled(email)
+	}
+	return 0, nil
+}
+
+// IsSesiuneActiva -
+func (stub *DatabaseHandlerStub) IsSesiuneActiva(id string) (bool, error) {
+	if stub.IsSesiuneActivaCalled != nil {
+		return stub.IsSesiuneActivaCalled(id)
+	}
+	return false, nil
+}
+
+// GetRoluri -
+func (stub *DatabaseHandlerStub) GetRoluri(email string) ([]authentication.RolUtilizator, error) {
+	if stub.GetRoluriCalled != nil {
+		return stub.GetRoluriCalled(email)
+	}
+	return nil, nil
+}
+
+// AssignRol -
+func (stub *DatabaseHandlerStub) AssignRol(request *core.RolRequest) error {
+	if stub.AssignRolCalled != nil {
+		return stub.AssignRolCalled(request)
+	}
+	return nil
+}
+
+// RevokeRol -
+func (stub *DatabaseHandlerStub) RevokeRol(request *core.RolRequest) error {
+	if stub.RevokeRolCalled != nil {
+		return stub.RevokeRolCalled(request)
+	}
+	return nil
+}
+
+// HasAdmin -
+func (stub *DatabaseHandlerStub) HasAdmin() (bool, error) {
+	if stub.HasAdminCalled != nil {
+		return stub.HasAdminCalled()
+	}
+	return false, nil
+}
+
+// CreateFirstAdmin -
+func (stub *DatabaseHandlerStub) CreateFirstAdmin(profesor *authentication.Profesor) error {
+	if stub.CreateFirstAdminCalled != nil {
+		return stub.CreateFirstAdminCalled(profesor)
+	}
+	return nil
+}
+
+// CreateInvitatie -
+func (stub *DatabaseHandlerStub) CreateInvitatie(invitatie *authentication.Invitatie) error {
+	if stub.CreateInvitatieCalled != nil {
+		return stub.CreateInvitatieCalled(invitatie)
+	}
+	return nil
+}
+
+// RedeemInvitatie -
+func (stub *DatabaseHandlerStub) RedeemInvitatie(id string, profesor *authentication.Profesor) error {
+	if stub.RedeemInvitatieCalled != nil {
+		return stub.RedeemInvitatieCalled(id, profesor)
+	}
+	return nil
+}
+
+// GetStudentByUsername -
+func (stub *DatabaseHandlerStub) GetStudentByUsername(username string) (*authentication.Student, error) {
+	if stub.GetStudentByUsernameCalled != nil {
+		return stub.GetStudentByUsernameCalled(username)
+	}
+	return nil, nil
+}
+
+// GetStudentExame -
+func (stub *DatabaseHandlerStub) GetStudentExame(studentId uint) ([]*core.StudentExam, error) {
+	if stub.GetStudentExameCalled != nil {
+		return stub.GetStudentExameCalled(studentId)
+	}
+	return nil, nil
+}
+
+// GetStudentRezultate -
+func (stub *DatabaseHandlerStub) GetStudentRezultate(studentId uint, exam string) (*core.StudentRezultate, error) {
+	if stub.GetStudentRezultateCalled != nil {
+		return stub.GetStudentRezultateCalled(studentId, exam)
+	}
+	return nil, nil
+}
+
+// FileStudentContestatie -
+func (stub *DatabaseHandlerStub) FileStudentContestatie(studentId uint, request *core.StudentContestatieRequest) (*authentication.Contestatie, error) {
+	if stub.FileStudentContestatieCalled != nil {
+		return stub.FileStudentContestatieCalled(studentId, request)
+	}
+	return nil, nil
+}
+
+// IsInterfaceNil -
+func (stub *DatabaseHandlerStub) IsInterfaceNil() bool {
+	return stub == nil
+}
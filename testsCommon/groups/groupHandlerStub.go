@@ -8,8 +8,9 @@ import (
 
 // GroupHandlerStub -
 type GroupHandlerStub struct {
-	UpdateFacadeCalled   func(newFacade shared.FacadeHandler) error
-	RegisterRoutesCalled func(ws *gin.RouterGroup, apiConfig config.ApiRoutesConfig)
+	UpdateFacadeCalled           func(newFacade shared.FacadeHandler) error
+	RegisterRoutesCalled         func(ws *gin.RouterGroup, apiConfig config.ApiRoutesConfig)
+	IsAuthenticationNeededCalled func() bool
 }
 
 // UpdateFacade -
@@ -27,6 +28,14 @@ func (g *GroupHandlerStub) RegisterRoutes(ws *gin.RouterGroup, apiConfig config.
 	}
 }
 
+// IsAuthenticationNeeded -
+func (g *GroupHandlerStub) IsAuthenticationNeeded() bool {
+	if g.IsAuthenticationNeededCalled != nil {
+		return g.IsAuthenticationNeededCalled()
+	}
+	return false
+}
+
 // IsInterfaceNil -
 func (g *GroupHandlerStub) IsInterfaceNil() bool {
 	return g == nil
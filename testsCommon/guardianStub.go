@@ -1,14 +1,14 @@
 package testsCommon
 
-/data"
+import "github.com/multiversx/mx-chain-core-go/data/transaction"
 
 // GuardianStub -
 type GuardianStub struct {
-	ValidateAndSendCalled func(transaction data.Transaction) (string, error)
+	ValidateAndSendCalled func(transaction transaction.Transaction) (string, error)
 }
 
 // ValidateAndSend -
-func (g *GuardianStub) ValidateAndSend(transaction data.Transaction) (string, error) {
+func (g *GuardianStub) ValidateAndSend(transaction transaction.Transaction) (string, error) {
 	if g.ValidateAndSendCalled != nil {
 		return g.ValidateAndSendCalled(transaction)
 	}